@@ -34,6 +34,8 @@ const (
 	TransactionType_CTF_REDEEM                   TransactionType = 5 // CTF 赎回
 	TransactionType_CLOB_ORDER                   TransactionType = 6 // CLOB 订单执行
 	TransactionType_CUSTOM                       TransactionType = 7 // 自定义交易
+	TransactionType_MULTICALL                    TransactionType = 8 // Multicall3 原子批量交易（aggregate3）
+	TransactionType_PROXY_RELAY                  TransactionType = 9 // Proxy Wallet 中继交易（经 RelayHub 调用 ProxyWalletFactory.proxy）
 )
 
 // Enum value maps for TransactionType.
@@ -47,6 +49,8 @@ var (
 		5: "CTF_REDEEM",
 		6: "CLOB_ORDER",
 		7: "CUSTOM",
+		8: "MULTICALL",
+		9: "PROXY_RELAY",
 	}
 	TransactionType_value = map[string]int32{
 		"TRANSACTION_TYPE_UNSPECIFIED": 0,
@@ -57,6 +61,8 @@ var (
 		"CTF_REDEEM":                   5,
 		"CLOB_ORDER":                   6,
 		"CUSTOM":                       7,
+		"MULTICALL":                    8,
+		"PROXY_RELAY":                  9,
 	}
 )
 
@@ -146,7 +152,10 @@ type SubmitTransactionRequest struct {
 	Forwarder       string                 `protobuf:"bytes,4,opt,name=forwarder,proto3" json:"forwarder,omitempty"`                                                                     // 转发器合约地址（可选）
 	GasLimit        int64                  `protobuf:"varint,5,opt,name=gas_limit,json=gasLimit,proto3" json:"gas_limit,omitempty"`                                                      // 预估 Gas Limit（可选）
 	TransactionType TransactionType        `protobuf:"varint,6,opt,name=transaction_type,json=transactionType,proto3,enum=relayer.v1.TransactionType" json:"transaction_type,omitempty"` // 交易类型
-	Value           string                 `protobuf:"bytes,7,opt,name=value,proto3" json:"value,omitempty"`                                                                             // 交易金额（hex，通常为 "0x0"）
+	Value           string                 `protobuf:"bytes,7,opt,name=value,proto3" json:"value,omitempty"`                                                                             // 交易金额（wei，十进制或 0x 十六进制，通常为 "0x0"）
+	ChainId         int64                  `protobuf:"varint,8,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`                                                         // 目标链 ID（可选，为 0 时使用默认链，例如 137 Polygon、80002 Amoy）
+	SafeTx          *SafeTransaction       `protobuf:"bytes,9,opt,name=safe_tx,json=safeTx,proto3" json:"safe_tx,omitempty"`                                                             // Safe 交易（可选）：不能与 to / data、proxy_tx 同时设置（忽略 value），校验 owner 签名后中继 execTransaction
+	ProxyTx         *ProxyTransaction      `protobuf:"bytes,10,opt,name=proxy_tx,json=proxyTx,proto3" json:"proxy_tx,omitempty"`                                                         // Proxy Wallet 中继请求（可选）：不能与 to / data、safe_tx 同时设置（忽略 value），校验 owner 签名后经 RelayHub 中继 ProxyWalletFactory.proxy
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -230,6 +239,393 @@ func (x *SubmitTransactionRequest) GetValue() string {
 	return ""
 }
 
+func (x *SubmitTransactionRequest) GetChainId() int64 {
+	if x != nil {
+		return x.ChainId
+	}
+	return 0
+}
+
+func (x *SubmitTransactionRequest) GetSafeTx() *SafeTransaction {
+	if x != nil {
+		return x.SafeTx
+	}
+	return nil
+}
+
+func (x *SubmitTransactionRequest) GetProxyTx() *ProxyTransaction {
+	if x != nil {
+		return x.ProxyTx
+	}
+	return nil
+}
+
+// SafeTransaction Gnosis Safe 交易（EIP-712 SafeTx 字段 + owner 签名），数值字段支持十进制或 0x 十六进制
+type SafeTransaction struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SafeAddress    string                 `protobuf:"bytes,1,opt,name=safe_address,json=safeAddress,proto3" json:"safe_address,omitempty"` // Safe 合约地址
+	To             string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Value          string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Data           string                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`            // hex
+	Operation      uint32                 `protobuf:"varint,5,opt,name=operation,proto3" json:"operation,omitempty"` // 0 CALL，1 DELEGATECALL
+	SafeTxGas      string                 `protobuf:"bytes,6,opt,name=safe_tx_gas,json=safeTxGas,proto3" json:"safe_tx_gas,omitempty"`
+	BaseGas        string                 `protobuf:"bytes,7,opt,name=base_gas,json=baseGas,proto3" json:"base_gas,omitempty"`
+	GasPrice       string                 `protobuf:"bytes,8,opt,name=gas_price,json=gasPrice,proto3" json:"gas_price,omitempty"`
+	GasToken       string                 `protobuf:"bytes,9,opt,name=gas_token,json=gasToken,proto3" json:"gas_token,omitempty"`
+	RefundReceiver string                 `protobuf:"bytes,10,opt,name=refund_receiver,json=refundReceiver,proto3" json:"refund_receiver,omitempty"`
+	Nonce          string                 `protobuf:"bytes,11,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Signatures     string                 `protobuf:"bytes,12,opt,name=signatures,proto3" json:"signatures,omitempty"` // owner 签名（hex，按 owner 地址升序拼接的 65 字节签名）
+	Calls          []*SafeCall            `protobuf:"bytes,13,rep,name=calls,proto3" json:"calls,omitempty"`           // 批量调用（可选）：设置时 relayer 按顺序编码为 MultiSendCallOnly.multiSend，
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SafeTransaction) Reset() {
+	*x = SafeTransaction{}
+	mi := &file_relayer_v1_relayer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SafeTransaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SafeTransaction) ProtoMessage() {}
+
+func (x *SafeTransaction) ProtoReflect() protoreflect.Message {
+	mi := &file_relayer_v1_relayer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SafeTransaction.ProtoReflect.Descriptor instead.
+func (*SafeTransaction) Descriptor() ([]byte, []int) {
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SafeTransaction) GetSafeAddress() string {
+	if x != nil {
+		return x.SafeAddress
+	}
+	return ""
+}
+
+func (x *SafeTransaction) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *SafeTransaction) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *SafeTransaction) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+func (x *SafeTransaction) GetOperation() uint32 {
+	if x != nil {
+		return x.Operation
+	}
+	return 0
+}
+
+func (x *SafeTransaction) GetSafeTxGas() string {
+	if x != nil {
+		return x.SafeTxGas
+	}
+	return ""
+}
+
+func (x *SafeTransaction) GetBaseGas() string {
+	if x != nil {
+		return x.BaseGas
+	}
+	return ""
+}
+
+func (x *SafeTransaction) GetGasPrice() string {
+	if x != nil {
+		return x.GasPrice
+	}
+	return ""
+}
+
+func (x *SafeTransaction) GetGasToken() string {
+	if x != nil {
+		return x.GasToken
+	}
+	return ""
+}
+
+func (x *SafeTransaction) GetRefundReceiver() string {
+	if x != nil {
+		return x.RefundReceiver
+	}
+	return ""
+}
+
+func (x *SafeTransaction) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *SafeTransaction) GetSignatures() string {
+	if x != nil {
+		return x.Signatures
+	}
+	return ""
+}
+
+func (x *SafeTransaction) GetCalls() []*SafeCall {
+	if x != nil {
+		return x.Calls
+	}
+	return nil
+}
+
+// ProxyTransaction Proxy Wallet 中继请求（GSN RelayHub relayCall），数值字段支持十进制或 0x 十六进制
+// owner 对 keccak256("rlx:" ++ from ++ to ++ data ++ relayer_fee ++ gas_price ++ gas_limit ++ nonce ++ relayHub ++ relay) 做 eth_sign 签名，
+// 其中 to 为 ProxyWalletFactory 地址，data 为按 calls 编码的 proxy(calls) 调用数据；Proxy Wallet 不存在时由 Factory 在首次调用时自动部署
+type ProxyTransaction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`                               // Proxy Wallet owner（签名者）
+	Calls         []*ProxyCall           `protobuf:"bytes,2,rep,name=calls,proto3" json:"calls,omitempty"`                             // Proxy Wallet 按顺序执行的调用
+	RelayerFee    string                 `protobuf:"bytes,3,opt,name=relayer_fee,json=relayerFee,proto3" json:"relayer_fee,omitempty"` // RelayHub transactionFee（百分比）
+	GasPrice      string                 `protobuf:"bytes,4,opt,name=gas_price,json=gasPrice,proto3" json:"gas_price,omitempty"`       // 不高于 relayer 实际 Gas 价格
+	GasLimit      string                 `protobuf:"bytes,5,opt,name=gas_limit,json=gasLimit,proto3" json:"gas_limit,omitempty"`       // Factory 调用的 Gas 上限
+	Nonce         string                 `protobuf:"bytes,6,opt,name=nonce,proto3" json:"nonce,omitempty"`                             // RelayHub 中 owner 的 Nonce
+	Relay         string                 `protobuf:"bytes,7,opt,name=relay,proto3" json:"relay,omitempty"`                             // 签名绑定的 relay 地址（须为本链的 Operator，交易固定由该 Operator 发送）
+	Signature     string                 `protobuf:"bytes,8,opt,name=signature,proto3" json:"signature,omitempty"`                     // owner 签名（hex，65 字节）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProxyTransaction) Reset() {
+	*x = ProxyTransaction{}
+	mi := &file_relayer_v1_relayer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProxyTransaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProxyTransaction) ProtoMessage() {}
+
+func (x *ProxyTransaction) ProtoReflect() protoreflect.Message {
+	mi := &file_relayer_v1_relayer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProxyTransaction.ProtoReflect.Descriptor instead.
+func (*ProxyTransaction) Descriptor() ([]byte, []int) {
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProxyTransaction) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ProxyTransaction) GetCalls() []*ProxyCall {
+	if x != nil {
+		return x.Calls
+	}
+	return nil
+}
+
+func (x *ProxyTransaction) GetRelayerFee() string {
+	if x != nil {
+		return x.RelayerFee
+	}
+	return ""
+}
+
+func (x *ProxyTransaction) GetGasPrice() string {
+	if x != nil {
+		return x.GasPrice
+	}
+	return ""
+}
+
+func (x *ProxyTransaction) GetGasLimit() string {
+	if x != nil {
+		return x.GasLimit
+	}
+	return ""
+}
+
+func (x *ProxyTransaction) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *ProxyTransaction) GetRelay() string {
+	if x != nil {
+		return x.Relay
+	}
+	return ""
+}
+
+func (x *ProxyTransaction) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+// ProxyCall Proxy Wallet 调用（仅 CALL）
+type ProxyCall struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	To            string                 `protobuf:"bytes,1,opt,name=to,proto3" json:"to,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"` // 十进制或 0x 十六进制
+	Data          string                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`   // hex
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProxyCall) Reset() {
+	*x = ProxyCall{}
+	mi := &file_relayer_v1_relayer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProxyCall) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProxyCall) ProtoMessage() {}
+
+func (x *ProxyCall) ProtoReflect() protoreflect.Message {
+	mi := &file_relayer_v1_relayer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProxyCall.ProtoReflect.Descriptor instead.
+func (*ProxyCall) Descriptor() ([]byte, []int) {
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ProxyCall) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ProxyCall) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *ProxyCall) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+// SafeCall Safe MultiSend 子调用（仅 CALL）
+type SafeCall struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	To            string                 `protobuf:"bytes,1,opt,name=to,proto3" json:"to,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"` // 十进制或 0x 十六进制
+	Data          string                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`   // hex
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SafeCall) Reset() {
+	*x = SafeCall{}
+	mi := &file_relayer_v1_relayer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SafeCall) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SafeCall) ProtoMessage() {}
+
+func (x *SafeCall) ProtoReflect() protoreflect.Message {
+	mi := &file_relayer_v1_relayer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SafeCall.ProtoReflect.Descriptor instead.
+func (*SafeCall) Descriptor() ([]byte, []int) {
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SafeCall) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *SafeCall) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *SafeCall) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
 // SubmitTransactionReply 提交交易响应
 type SubmitTransactionReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -242,7 +638,7 @@ type SubmitTransactionReply struct {
 
 func (x *SubmitTransactionReply) Reset() {
 	*x = SubmitTransactionReply{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[1]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -254,7 +650,7 @@ func (x *SubmitTransactionReply) String() string {
 func (*SubmitTransactionReply) ProtoMessage() {}
 
 func (x *SubmitTransactionReply) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[1]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -267,7 +663,7 @@ func (x *SubmitTransactionReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubmitTransactionReply.ProtoReflect.Descriptor instead.
 func (*SubmitTransactionReply) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{1}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *SubmitTransactionReply) GetTaskId() string {
@@ -296,13 +692,14 @@ type SubmitBatchTransactionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Transactions  []*TransactionRequest  `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`                          // 交易数组
 	BuilderApiKey string                 `protobuf:"bytes,2,opt,name=builder_api_key,json=builderApiKey,proto3" json:"builder_api_key,omitempty"` // Builder API Key（用于费用追踪）
+	Atomic        bool                   `protobuf:"varint,3,opt,name=atomic,proto3" json:"atomic,omitempty"`                                     // 原子模式：打包为一笔 Multicall3 aggregate3 交易（单个任务 ID、单个 Nonce）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SubmitBatchTransactionRequest) Reset() {
 	*x = SubmitBatchTransactionRequest{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[2]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -314,7 +711,7 @@ func (x *SubmitBatchTransactionRequest) String() string {
 func (*SubmitBatchTransactionRequest) ProtoMessage() {}
 
 func (x *SubmitBatchTransactionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[2]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -327,7 +724,7 @@ func (x *SubmitBatchTransactionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubmitBatchTransactionRequest.ProtoReflect.Descriptor instead.
 func (*SubmitBatchTransactionRequest) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{2}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *SubmitBatchTransactionRequest) GetTransactions() []*TransactionRequest {
@@ -344,6 +741,13 @@ func (x *SubmitBatchTransactionRequest) GetBuilderApiKey() string {
 	return ""
 }
 
+func (x *SubmitBatchTransactionRequest) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
 // TransactionRequest 单笔交易请求（用于批量）
 type TransactionRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
@@ -354,13 +758,15 @@ type TransactionRequest struct {
 	GasLimit        int64                  `protobuf:"varint,5,opt,name=gas_limit,json=gasLimit,proto3" json:"gas_limit,omitempty"`
 	TransactionType TransactionType        `protobuf:"varint,6,opt,name=transaction_type,json=transactionType,proto3,enum=relayer.v1.TransactionType" json:"transaction_type,omitempty"`
 	Value           string                 `protobuf:"bytes,7,opt,name=value,proto3" json:"value,omitempty"`
+	ChainId         int64                  `protobuf:"varint,8,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`                // 目标链 ID（可选，为 0 时使用默认链）
+	AllowFailure    bool                   `protobuf:"varint,9,opt,name=allow_failure,json=allowFailure,proto3" json:"allow_failure,omitempty"` // 原子模式下允许该调用失败（为 false 时该调用失败会回滚整批）
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *TransactionRequest) Reset() {
 	*x = TransactionRequest{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[3]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -372,7 +778,7 @@ func (x *TransactionRequest) String() string {
 func (*TransactionRequest) ProtoMessage() {}
 
 func (x *TransactionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[3]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -385,7 +791,7 @@ func (x *TransactionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TransactionRequest.ProtoReflect.Descriptor instead.
 func (*TransactionRequest) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{3}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *TransactionRequest) GetTo() string {
@@ -437,19 +843,35 @@ func (x *TransactionRequest) GetValue() string {
 	return ""
 }
 
+func (x *TransactionRequest) GetChainId() int64 {
+	if x != nil {
+		return x.ChainId
+	}
+	return 0
+}
+
+func (x *TransactionRequest) GetAllowFailure() bool {
+	if x != nil {
+		return x.AllowFailure
+	}
+	return false
+}
+
 // SubmitBatchTransactionReply 批量提交交易响应
 type SubmitBatchTransactionReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	TaskIds       []string               `protobuf:"bytes,1,rep,name=task_ids,json=taskIds,proto3" json:"task_ids,omitempty"` // 任务 ID 数组
 	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	TaskId        string                 `protobuf:"bytes,4,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"` // 原子模式下整批交易的任务 ID
+	Results       []*CallResult          `protobuf:"bytes,5,rep,name=results,proto3" json:"results,omitempty"`             // 原子模式下各调用的模拟执行结果（按请求顺序）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SubmitBatchTransactionReply) Reset() {
 	*x = SubmitBatchTransactionReply{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[4]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -461,7 +883,7 @@ func (x *SubmitBatchTransactionReply) String() string {
 func (*SubmitBatchTransactionReply) ProtoMessage() {}
 
 func (x *SubmitBatchTransactionReply) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[4]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -474,7 +896,7 @@ func (x *SubmitBatchTransactionReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubmitBatchTransactionReply.ProtoReflect.Descriptor instead.
 func (*SubmitBatchTransactionReply) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{4}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *SubmitBatchTransactionReply) GetTaskIds() []string {
@@ -498,6 +920,89 @@ func (x *SubmitBatchTransactionReply) GetMessage() string {
 	return ""
 }
 
+func (x *SubmitBatchTransactionReply) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *SubmitBatchTransactionReply) GetResults() []*CallResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// CallResult Multicall3 子调用结果（由 aggregate3 返回数据解码）
+type CallResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // 调用在批量中的序号
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	ReturnData    string                 `protobuf:"bytes,3,opt,name=return_data,json=returnData,proto3" json:"return_data,omitempty"`       // 返回数据（hex）
+	ErrorMessage  string                 `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"` // 解码后的回滚原因（失败时）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CallResult) Reset() {
+	*x = CallResult{}
+	mi := &file_relayer_v1_relayer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CallResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallResult) ProtoMessage() {}
+
+func (x *CallResult) ProtoReflect() protoreflect.Message {
+	mi := &file_relayer_v1_relayer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallResult.ProtoReflect.Descriptor instead.
+func (*CallResult) Descriptor() ([]byte, []int) {
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CallResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *CallResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CallResult) GetReturnData() string {
+	if x != nil {
+		return x.ReturnData
+	}
+	return ""
+}
+
+func (x *CallResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
 // DeployWalletRequest 部署钱包请求
 type DeployWalletRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -509,7 +1014,7 @@ type DeployWalletRequest struct {
 
 func (x *DeployWalletRequest) Reset() {
 	*x = DeployWalletRequest{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[5]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -521,7 +1026,7 @@ func (x *DeployWalletRequest) String() string {
 func (*DeployWalletRequest) ProtoMessage() {}
 
 func (x *DeployWalletRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[5]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -534,7 +1039,7 @@ func (x *DeployWalletRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeployWalletRequest.ProtoReflect.Descriptor instead.
 func (*DeployWalletRequest) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{5}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *DeployWalletRequest) GetWalletType() WalletType {
@@ -564,7 +1069,7 @@ type DeployWalletReply struct {
 
 func (x *DeployWalletReply) Reset() {
 	*x = DeployWalletReply{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[6]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -576,7 +1081,7 @@ func (x *DeployWalletReply) String() string {
 func (*DeployWalletReply) ProtoMessage() {}
 
 func (x *DeployWalletReply) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[6]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -589,7 +1094,7 @@ func (x *DeployWalletReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeployWalletReply.ProtoReflect.Descriptor instead.
 func (*DeployWalletReply) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{6}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *DeployWalletReply) GetWalletAddress() string {
@@ -630,7 +1135,7 @@ type GetTransactionStatusRequest struct {
 
 func (x *GetTransactionStatusRequest) Reset() {
 	*x = GetTransactionStatusRequest{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[7]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -642,7 +1147,7 @@ func (x *GetTransactionStatusRequest) String() string {
 func (*GetTransactionStatusRequest) ProtoMessage() {}
 
 func (x *GetTransactionStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[7]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -655,7 +1160,7 @@ func (x *GetTransactionStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTransactionStatusRequest.ProtoReflect.Descriptor instead.
 func (*GetTransactionStatusRequest) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{7}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *GetTransactionStatusRequest) GetTaskId() string {
@@ -667,22 +1172,28 @@ func (x *GetTransactionStatusRequest) GetTaskId() string {
 
 // TransactionStatus 交易状态
 type TransactionStatus struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	TxHash        string                 `protobuf:"bytes,2,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
-	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                     // PENDING, MINED, FAILED, REPLACED
-	GasPrice      string                 `protobuf:"bytes,4,opt,name=gas_price,json=gasPrice,proto3" json:"gas_price,omitempty"` // Gas 价格（字符串，支持大整数）
-	BlockNumber   int64                  `protobuf:"varint,5,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
-	GasUsed       int64                  `protobuf:"varint,6,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     int64                  `protobuf:"varint,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	TaskId               string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	TxHash               string                 `protobuf:"bytes,2,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	Status               string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                     // QUEUED, PROCESSING, PENDING, MINED, CONFIRMED, FAILED, REPLACED
+	GasPrice             string                 `protobuf:"bytes,4,opt,name=gas_price,json=gasPrice,proto3" json:"gas_price,omitempty"` // Gas 价格（字符串，支持大整数）
+	BlockNumber          int64                  `protobuf:"varint,5,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	GasUsed              int64                  `protobuf:"varint,6,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	CreatedAt            int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt            int64                  `protobuf:"varint,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	MaxFeePerGas         string                 `protobuf:"bytes,9,opt,name=max_fee_per_gas,json=maxFeePerGas,proto3" json:"max_fee_per_gas,omitempty"`                            // EIP-1559 maxFeePerGas（wei，字符串）
+	MaxPriorityFeePerGas string                 `protobuf:"bytes,10,opt,name=max_priority_fee_per_gas,json=maxPriorityFeePerGas,proto3" json:"max_priority_fee_per_gas,omitempty"` // EIP-1559 maxPriorityFeePerGas（wei，字符串）
+	ErrorMessage         string                 `protobuf:"bytes,11,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`                               // 错误信息（[错误分类] 错误消息，例如模拟执行的回滚原因）
+	ChainId              int64                  `protobuf:"varint,12,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`                                             // 交易所在链 ID
+	SimulatedCallResults []*CallResult          `protobuf:"bytes,13,rep,name=simulated_call_results,json=simulatedCallResults,proto3" json:"simulated_call_results,omitempty"`     // Multicall3 原子批量交易提交时各调用的模拟执行结果（不是链上执行结果，allowFailure 调用上链时可能失败）
+	SafeCalls            []*SafeCall            `protobuf:"bytes,14,rep,name=safe_calls,json=safeCalls,proto3" json:"safe_calls,omitempty"`                                        // Safe MultiSend 交易解码后的各调用（按执行顺序）
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *TransactionStatus) Reset() {
 	*x = TransactionStatus{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[8]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -694,7 +1205,7 @@ func (x *TransactionStatus) String() string {
 func (*TransactionStatus) ProtoMessage() {}
 
 func (x *TransactionStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[8]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -707,7 +1218,7 @@ func (x *TransactionStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TransactionStatus.ProtoReflect.Descriptor instead.
 func (*TransactionStatus) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{8}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *TransactionStatus) GetTaskId() string {
@@ -766,6 +1277,48 @@ func (x *TransactionStatus) GetUpdatedAt() int64 {
 	return 0
 }
 
+func (x *TransactionStatus) GetMaxFeePerGas() string {
+	if x != nil {
+		return x.MaxFeePerGas
+	}
+	return ""
+}
+
+func (x *TransactionStatus) GetMaxPriorityFeePerGas() string {
+	if x != nil {
+		return x.MaxPriorityFeePerGas
+	}
+	return ""
+}
+
+func (x *TransactionStatus) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *TransactionStatus) GetChainId() int64 {
+	if x != nil {
+		return x.ChainId
+	}
+	return 0
+}
+
+func (x *TransactionStatus) GetSimulatedCallResults() []*CallResult {
+	if x != nil {
+		return x.SimulatedCallResults
+	}
+	return nil
+}
+
+func (x *TransactionStatus) GetSafeCalls() []*SafeCall {
+	if x != nil {
+		return x.SafeCalls
+	}
+	return nil
+}
+
 // GetTransactionStatusReply 查询交易状态响应
 type GetTransactionStatusReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -776,7 +1329,7 @@ type GetTransactionStatusReply struct {
 
 func (x *GetTransactionStatusReply) Reset() {
 	*x = GetTransactionStatusReply{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[9]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -788,7 +1341,7 @@ func (x *GetTransactionStatusReply) String() string {
 func (*GetTransactionStatusReply) ProtoMessage() {}
 
 func (x *GetTransactionStatusReply) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[9]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -801,7 +1354,7 @@ func (x *GetTransactionStatusReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTransactionStatusReply.ProtoReflect.Descriptor instead.
 func (*GetTransactionStatusReply) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{9}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *GetTransactionStatusReply) GetStatus() *TransactionStatus {
@@ -823,7 +1376,7 @@ type GetBuilderFeeStatsRequest struct {
 
 func (x *GetBuilderFeeStatsRequest) Reset() {
 	*x = GetBuilderFeeStatsRequest{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[10]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -835,7 +1388,7 @@ func (x *GetBuilderFeeStatsRequest) String() string {
 func (*GetBuilderFeeStatsRequest) ProtoMessage() {}
 
 func (x *GetBuilderFeeStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[10]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -848,7 +1401,7 @@ func (x *GetBuilderFeeStatsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBuilderFeeStatsRequest.ProtoReflect.Descriptor instead.
 func (*GetBuilderFeeStatsRequest) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{10}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetBuilderFeeStatsRequest) GetApiKey() string {
@@ -884,7 +1437,7 @@ type FeeStatsByType struct {
 
 func (x *FeeStatsByType) Reset() {
 	*x = FeeStatsByType{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[11]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -896,7 +1449,7 @@ func (x *FeeStatsByType) String() string {
 func (*FeeStatsByType) ProtoMessage() {}
 
 func (x *FeeStatsByType) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[11]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -909,7 +1462,7 @@ func (x *FeeStatsByType) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FeeStatsByType.ProtoReflect.Descriptor instead.
 func (*FeeStatsByType) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{11}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *FeeStatsByType) GetCount() int64 {
@@ -946,7 +1499,7 @@ type GetBuilderFeeStatsReply struct {
 
 func (x *GetBuilderFeeStatsReply) Reset() {
 	*x = GetBuilderFeeStatsReply{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[12]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -958,7 +1511,7 @@ func (x *GetBuilderFeeStatsReply) String() string {
 func (*GetBuilderFeeStatsReply) ProtoMessage() {}
 
 func (x *GetBuilderFeeStatsReply) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[12]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -971,7 +1524,7 @@ func (x *GetBuilderFeeStatsReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBuilderFeeStatsReply.ProtoReflect.Descriptor instead.
 func (*GetBuilderFeeStatsReply) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{12}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetBuilderFeeStatsReply) GetTotalTransactions() int64 {
@@ -1012,7 +1565,7 @@ type GetOperatorBalanceRequest struct {
 
 func (x *GetOperatorBalanceRequest) Reset() {
 	*x = GetOperatorBalanceRequest{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[13]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1024,7 +1577,7 @@ func (x *GetOperatorBalanceRequest) String() string {
 func (*GetOperatorBalanceRequest) ProtoMessage() {}
 
 func (x *GetOperatorBalanceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[13]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1037,7 +1590,7 @@ func (x *GetOperatorBalanceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOperatorBalanceRequest.ProtoReflect.Descriptor instead.
 func (*GetOperatorBalanceRequest) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{13}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *GetOperatorBalanceRequest) GetOperatorAddress() string {
@@ -1059,7 +1612,7 @@ type GetOperatorBalanceReply struct {
 
 func (x *GetOperatorBalanceReply) Reset() {
 	*x = GetOperatorBalanceReply{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[14]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1071,7 +1624,7 @@ func (x *GetOperatorBalanceReply) String() string {
 func (*GetOperatorBalanceReply) ProtoMessage() {}
 
 func (x *GetOperatorBalanceReply) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[14]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1084,7 +1637,7 @@ func (x *GetOperatorBalanceReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOperatorBalanceReply.ProtoReflect.Descriptor instead.
 func (*GetOperatorBalanceReply) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{14}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *GetOperatorBalanceReply) GetOperatorAddress() string {
@@ -1137,7 +1690,7 @@ type Order struct {
 
 func (x *Order) Reset() {
 	*x = Order{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[15]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1149,7 +1702,7 @@ func (x *Order) String() string {
 func (*Order) ProtoMessage() {}
 
 func (x *Order) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[15]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1162,7 +1715,7 @@ func (x *Order) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Order.ProtoReflect.Descriptor instead.
 func (*Order) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{15}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *Order) GetId() string {
@@ -1320,7 +1873,7 @@ type SubmitMatchRequest struct {
 
 func (x *SubmitMatchRequest) Reset() {
 	*x = SubmitMatchRequest{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[16]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1332,7 +1885,7 @@ func (x *SubmitMatchRequest) String() string {
 func (*SubmitMatchRequest) ProtoMessage() {}
 
 func (x *SubmitMatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[16]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1345,7 +1898,7 @@ func (x *SubmitMatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubmitMatchRequest.ProtoReflect.Descriptor instead.
 func (*SubmitMatchRequest) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{16}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *SubmitMatchRequest) GetMakerOrder() *Order {
@@ -1402,7 +1955,7 @@ type SubmitMatchReply struct {
 
 func (x *SubmitMatchReply) Reset() {
 	*x = SubmitMatchReply{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[17]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1414,7 +1967,7 @@ func (x *SubmitMatchReply) String() string {
 func (*SubmitMatchReply) ProtoMessage() {}
 
 func (x *SubmitMatchReply) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[17]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1427,7 +1980,7 @@ func (x *SubmitMatchReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubmitMatchReply.ProtoReflect.Descriptor instead.
 func (*SubmitMatchReply) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{17}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *SubmitMatchReply) GetTaskId() string {
@@ -1461,7 +2014,7 @@ type GetTransactionHashByOrderIDRequest struct {
 
 func (x *GetTransactionHashByOrderIDRequest) Reset() {
 	*x = GetTransactionHashByOrderIDRequest{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[18]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1473,7 +2026,7 @@ func (x *GetTransactionHashByOrderIDRequest) String() string {
 func (*GetTransactionHashByOrderIDRequest) ProtoMessage() {}
 
 func (x *GetTransactionHashByOrderIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[18]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1486,7 +2039,7 @@ func (x *GetTransactionHashByOrderIDRequest) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use GetTransactionHashByOrderIDRequest.ProtoReflect.Descriptor instead.
 func (*GetTransactionHashByOrderIDRequest) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{18}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *GetTransactionHashByOrderIDRequest) GetOrderId() string {
@@ -1508,7 +2061,7 @@ type GetTransactionHashByOrderIDReply struct {
 
 func (x *GetTransactionHashByOrderIDReply) Reset() {
 	*x = GetTransactionHashByOrderIDReply{}
-	mi := &file_relayer_v1_relayer_proto_msgTypes[19]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1520,7 +2073,7 @@ func (x *GetTransactionHashByOrderIDReply) String() string {
 func (*GetTransactionHashByOrderIDReply) ProtoMessage() {}
 
 func (x *GetTransactionHashByOrderIDReply) ProtoReflect() protoreflect.Message {
-	mi := &file_relayer_v1_relayer_proto_msgTypes[19]
+	mi := &file_relayer_v1_relayer_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1533,7 +2086,7 @@ func (x *GetTransactionHashByOrderIDReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTransactionHashByOrderIDReply.ProtoReflect.Descriptor instead.
 func (*GetTransactionHashByOrderIDReply) Descriptor() ([]byte, []int) {
-	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{19}
+	return file_relayer_v1_relayer_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *GetTransactionHashByOrderIDReply) GetTransactionHash() string {
@@ -1562,7 +2115,7 @@ var File_relayer_v1_relayer_proto protoreflect.FileDescriptor
 const file_relayer_v1_relayer_proto_rawDesc = "" +
 	"\n" +
 	"\x18relayer/v1/relayer.proto\x12\n" +
-	"relayer.v1\x1a\x1cgoogle/api/annotations.proto\"\xf5\x01\n" +
+	"relayer.v1\x1a\x1cgoogle/api/annotations.proto\"\xff\x02\n" +
 	"\x18SubmitTransactionRequest\x12\x0e\n" +
 	"\x02to\x18\x01 \x01(\tR\x02to\x12\x12\n" +
 	"\x04data\x18\x02 \x01(\tR\x04data\x12\x1c\n" +
@@ -1570,14 +2123,54 @@ const file_relayer_v1_relayer_proto_rawDesc = "" +
 	"\tforwarder\x18\x04 \x01(\tR\tforwarder\x12\x1b\n" +
 	"\tgas_limit\x18\x05 \x01(\x03R\bgasLimit\x12F\n" +
 	"\x10transaction_type\x18\x06 \x01(\x0e2\x1b.relayer.v1.TransactionTypeR\x0ftransactionType\x12\x14\n" +
-	"\x05value\x18\a \x01(\tR\x05value\"e\n" +
+	"\x05value\x18\a \x01(\tR\x05value\x12\x19\n" +
+	"\bchain_id\x18\b \x01(\x03R\achainId\x124\n" +
+	"\asafe_tx\x18\t \x01(\v2\x1b.relayer.v1.SafeTransactionR\x06safeTx\x127\n" +
+	"\bproxy_tx\x18\n" +
+	" \x01(\v2\x1c.relayer.v1.ProxyTransactionR\aproxyTx\"\x8c\x03\n" +
+	"\x0fSafeTransaction\x12!\n" +
+	"\fsafe_address\x18\x01 \x01(\tR\vsafeAddress\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\tR\x04data\x12\x1c\n" +
+	"\toperation\x18\x05 \x01(\rR\toperation\x12\x1e\n" +
+	"\vsafe_tx_gas\x18\x06 \x01(\tR\tsafeTxGas\x12\x19\n" +
+	"\bbase_gas\x18\a \x01(\tR\abaseGas\x12\x1b\n" +
+	"\tgas_price\x18\b \x01(\tR\bgasPrice\x12\x1b\n" +
+	"\tgas_token\x18\t \x01(\tR\bgasToken\x12'\n" +
+	"\x0frefund_receiver\x18\n" +
+	" \x01(\tR\x0erefundReceiver\x12\x14\n" +
+	"\x05nonce\x18\v \x01(\tR\x05nonce\x12\x1e\n" +
+	"\n" +
+	"signatures\x18\f \x01(\tR\n" +
+	"signatures\x12*\n" +
+	"\x05calls\x18\r \x03(\v2\x14.relayer.v1.SafeCallR\x05calls\"\xf8\x01\n" +
+	"\x10ProxyTransaction\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12+\n" +
+	"\x05calls\x18\x02 \x03(\v2\x15.relayer.v1.ProxyCallR\x05calls\x12\x1f\n" +
+	"\vrelayer_fee\x18\x03 \x01(\tR\n" +
+	"relayerFee\x12\x1b\n" +
+	"\tgas_price\x18\x04 \x01(\tR\bgasPrice\x12\x1b\n" +
+	"\tgas_limit\x18\x05 \x01(\tR\bgasLimit\x12\x14\n" +
+	"\x05nonce\x18\x06 \x01(\tR\x05nonce\x12\x14\n" +
+	"\x05relay\x18\a \x01(\tR\x05relay\x12\x1c\n" +
+	"\tsignature\x18\b \x01(\tR\tsignature\"E\n" +
+	"\tProxyCall\x12\x0e\n" +
+	"\x02to\x18\x01 \x01(\tR\x02to\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\tR\x04data\"D\n" +
+	"\bSafeCall\x12\x0e\n" +
+	"\x02to\x18\x01 \x01(\tR\x02to\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\tR\x04data\"e\n" +
 	"\x16SubmitTransactionReply\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x18\n" +
 	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"\x8b\x01\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xa3\x01\n" +
 	"\x1dSubmitBatchTransactionRequest\x12B\n" +
 	"\ftransactions\x18\x01 \x03(\v2\x1e.relayer.v1.TransactionRequestR\ftransactions\x12&\n" +
-	"\x0fbuilder_api_key\x18\x02 \x01(\tR\rbuilderApiKey\"\xef\x01\n" +
+	"\x0fbuilder_api_key\x18\x02 \x01(\tR\rbuilderApiKey\x12\x16\n" +
+	"\x06atomic\x18\x03 \x01(\bR\x06atomic\"\xaf\x02\n" +
 	"\x12TransactionRequest\x12\x0e\n" +
 	"\x02to\x18\x01 \x01(\tR\x02to\x12\x12\n" +
 	"\x04data\x18\x02 \x01(\tR\x04data\x12\x1c\n" +
@@ -1585,11 +2178,22 @@ const file_relayer_v1_relayer_proto_rawDesc = "" +
 	"\tforwarder\x18\x04 \x01(\tR\tforwarder\x12\x1b\n" +
 	"\tgas_limit\x18\x05 \x01(\x03R\bgasLimit\x12F\n" +
 	"\x10transaction_type\x18\x06 \x01(\x0e2\x1b.relayer.v1.TransactionTypeR\x0ftransactionType\x12\x14\n" +
-	"\x05value\x18\a \x01(\tR\x05value\"l\n" +
+	"\x05value\x18\a \x01(\tR\x05value\x12\x19\n" +
+	"\bchain_id\x18\b \x01(\x03R\achainId\x12#\n" +
+	"\rallow_failure\x18\t \x01(\bR\fallowFailure\"\xb7\x01\n" +
 	"\x1bSubmitBatchTransactionReply\x12\x19\n" +
 	"\btask_ids\x18\x01 \x03(\tR\ataskIds\x12\x18\n" +
 	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"f\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x17\n" +
+	"\atask_id\x18\x04 \x01(\tR\x06taskId\x120\n" +
+	"\aresults\x18\x05 \x03(\v2\x16.relayer.v1.CallResultR\aresults\"\x82\x01\n" +
+	"\n" +
+	"CallResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x1f\n" +
+	"\vreturn_data\x18\x03 \x01(\tR\n" +
+	"returnData\x12#\n" +
+	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\"f\n" +
 	"\x13DeployWalletRequest\x127\n" +
 	"\vwallet_type\x18\x01 \x01(\x0e2\x16.relayer.v1.WalletTypeR\n" +
 	"walletType\x12\x16\n" +
@@ -1600,7 +2204,7 @@ const file_relayer_v1_relayer_proto_rawDesc = "" +
 	"\asuccess\x18\x03 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x04 \x01(\tR\amessage\"6\n" +
 	"\x1bGetTransactionStatusRequest\x12\x17\n" +
-	"\atask_id\x18\x01 \x01(\tR\x06taskId\"\xf6\x01\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"\x98\x04\n" +
 	"\x11TransactionStatus\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x17\n" +
 	"\atx_hash\x18\x02 \x01(\tR\x06txHash\x12\x16\n" +
@@ -1611,7 +2215,15 @@ const file_relayer_v1_relayer_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\a \x01(\x03R\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\b \x01(\x03R\tupdatedAt\"R\n" +
+	"updated_at\x18\b \x01(\x03R\tupdatedAt\x12%\n" +
+	"\x0fmax_fee_per_gas\x18\t \x01(\tR\fmaxFeePerGas\x126\n" +
+	"\x18max_priority_fee_per_gas\x18\n" +
+	" \x01(\tR\x14maxPriorityFeePerGas\x12#\n" +
+	"\rerror_message\x18\v \x01(\tR\ferrorMessage\x12\x19\n" +
+	"\bchain_id\x18\f \x01(\x03R\achainId\x12L\n" +
+	"\x16simulated_call_results\x18\r \x03(\v2\x16.relayer.v1.CallResultR\x14simulatedCallResults\x123\n" +
+	"\n" +
+	"safe_calls\x18\x0e \x03(\v2\x14.relayer.v1.SafeCallR\tsafeCalls\"R\n" +
 	"\x19GetTransactionStatusReply\x125\n" +
 	"\x06status\x18\x01 \x01(\v2\x1d.relayer.v1.TransactionStatusR\x06status\"n\n" +
 	"\x19GetBuilderFeeStatsRequest\x12\x17\n" +
@@ -1682,7 +2294,7 @@ const file_relayer_v1_relayer_proto_rawDesc = "" +
 	" GetTransactionHashByOrderIDReply\x12)\n" +
 	"\x10transaction_hash\x18\x01 \x01(\tR\x0ftransactionHash\x12\x18\n" +
 	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage*\xa8\x01\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage*\xc8\x01\n" +
 	"\x0fTransactionType\x12 \n" +
 	"\x1cTRANSACTION_TYPE_UNSPECIFIED\x10\x00\x12\x15\n" +
 	"\x11WALLET_DEPLOYMENT\x10\x01\x12\x12\n" +
@@ -1694,7 +2306,9 @@ const file_relayer_v1_relayer_proto_rawDesc = "" +
 	"\n" +
 	"CLOB_ORDER\x10\x06\x12\n" +
 	"\n" +
-	"\x06CUSTOM\x10\a*>\n" +
+	"\x06CUSTOM\x10\a\x12\r\n" +
+	"\tMULTICALL\x10\b\x12\x0f\n" +
+	"\vPROXY_RELAY\x10\t*>\n" +
 	"\n" +
 	"WalletType\x12\x1b\n" +
 	"\x17WALLET_TYPE_UNSPECIFIED\x10\x00\x12\b\n" +
@@ -1723,63 +2337,75 @@ func file_relayer_v1_relayer_proto_rawDescGZIP() []byte {
 }
 
 var file_relayer_v1_relayer_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_relayer_v1_relayer_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_relayer_v1_relayer_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_relayer_v1_relayer_proto_goTypes = []any{
 	(TransactionType)(0),                       // 0: relayer.v1.TransactionType
 	(WalletType)(0),                            // 1: relayer.v1.WalletType
 	(*SubmitTransactionRequest)(nil),           // 2: relayer.v1.SubmitTransactionRequest
-	(*SubmitTransactionReply)(nil),             // 3: relayer.v1.SubmitTransactionReply
-	(*SubmitBatchTransactionRequest)(nil),      // 4: relayer.v1.SubmitBatchTransactionRequest
-	(*TransactionRequest)(nil),                 // 5: relayer.v1.TransactionRequest
-	(*SubmitBatchTransactionReply)(nil),        // 6: relayer.v1.SubmitBatchTransactionReply
-	(*DeployWalletRequest)(nil),                // 7: relayer.v1.DeployWalletRequest
-	(*DeployWalletReply)(nil),                  // 8: relayer.v1.DeployWalletReply
-	(*GetTransactionStatusRequest)(nil),        // 9: relayer.v1.GetTransactionStatusRequest
-	(*TransactionStatus)(nil),                  // 10: relayer.v1.TransactionStatus
-	(*GetTransactionStatusReply)(nil),          // 11: relayer.v1.GetTransactionStatusReply
-	(*GetBuilderFeeStatsRequest)(nil),          // 12: relayer.v1.GetBuilderFeeStatsRequest
-	(*FeeStatsByType)(nil),                     // 13: relayer.v1.FeeStatsByType
-	(*GetBuilderFeeStatsReply)(nil),            // 14: relayer.v1.GetBuilderFeeStatsReply
-	(*GetOperatorBalanceRequest)(nil),          // 15: relayer.v1.GetOperatorBalanceRequest
-	(*GetOperatorBalanceReply)(nil),            // 16: relayer.v1.GetOperatorBalanceReply
-	(*Order)(nil),                              // 17: relayer.v1.Order
-	(*SubmitMatchRequest)(nil),                 // 18: relayer.v1.SubmitMatchRequest
-	(*SubmitMatchReply)(nil),                   // 19: relayer.v1.SubmitMatchReply
-	(*GetTransactionHashByOrderIDRequest)(nil), // 20: relayer.v1.GetTransactionHashByOrderIDRequest
-	(*GetTransactionHashByOrderIDReply)(nil),   // 21: relayer.v1.GetTransactionHashByOrderIDReply
-	nil,                                        // 22: relayer.v1.GetBuilderFeeStatsReply.ByTypeEntry
+	(*SafeTransaction)(nil),                    // 3: relayer.v1.SafeTransaction
+	(*ProxyTransaction)(nil),                   // 4: relayer.v1.ProxyTransaction
+	(*ProxyCall)(nil),                          // 5: relayer.v1.ProxyCall
+	(*SafeCall)(nil),                           // 6: relayer.v1.SafeCall
+	(*SubmitTransactionReply)(nil),             // 7: relayer.v1.SubmitTransactionReply
+	(*SubmitBatchTransactionRequest)(nil),      // 8: relayer.v1.SubmitBatchTransactionRequest
+	(*TransactionRequest)(nil),                 // 9: relayer.v1.TransactionRequest
+	(*SubmitBatchTransactionReply)(nil),        // 10: relayer.v1.SubmitBatchTransactionReply
+	(*CallResult)(nil),                         // 11: relayer.v1.CallResult
+	(*DeployWalletRequest)(nil),                // 12: relayer.v1.DeployWalletRequest
+	(*DeployWalletReply)(nil),                  // 13: relayer.v1.DeployWalletReply
+	(*GetTransactionStatusRequest)(nil),        // 14: relayer.v1.GetTransactionStatusRequest
+	(*TransactionStatus)(nil),                  // 15: relayer.v1.TransactionStatus
+	(*GetTransactionStatusReply)(nil),          // 16: relayer.v1.GetTransactionStatusReply
+	(*GetBuilderFeeStatsRequest)(nil),          // 17: relayer.v1.GetBuilderFeeStatsRequest
+	(*FeeStatsByType)(nil),                     // 18: relayer.v1.FeeStatsByType
+	(*GetBuilderFeeStatsReply)(nil),            // 19: relayer.v1.GetBuilderFeeStatsReply
+	(*GetOperatorBalanceRequest)(nil),          // 20: relayer.v1.GetOperatorBalanceRequest
+	(*GetOperatorBalanceReply)(nil),            // 21: relayer.v1.GetOperatorBalanceReply
+	(*Order)(nil),                              // 22: relayer.v1.Order
+	(*SubmitMatchRequest)(nil),                 // 23: relayer.v1.SubmitMatchRequest
+	(*SubmitMatchReply)(nil),                   // 24: relayer.v1.SubmitMatchReply
+	(*GetTransactionHashByOrderIDRequest)(nil), // 25: relayer.v1.GetTransactionHashByOrderIDRequest
+	(*GetTransactionHashByOrderIDReply)(nil),   // 26: relayer.v1.GetTransactionHashByOrderIDReply
+	nil,                                        // 27: relayer.v1.GetBuilderFeeStatsReply.ByTypeEntry
 }
 var file_relayer_v1_relayer_proto_depIdxs = []int32{
 	0,  // 0: relayer.v1.SubmitTransactionRequest.transaction_type:type_name -> relayer.v1.TransactionType
-	5,  // 1: relayer.v1.SubmitBatchTransactionRequest.transactions:type_name -> relayer.v1.TransactionRequest
-	0,  // 2: relayer.v1.TransactionRequest.transaction_type:type_name -> relayer.v1.TransactionType
-	1,  // 3: relayer.v1.DeployWalletRequest.wallet_type:type_name -> relayer.v1.WalletType
-	10, // 4: relayer.v1.GetTransactionStatusReply.status:type_name -> relayer.v1.TransactionStatus
-	22, // 5: relayer.v1.GetBuilderFeeStatsReply.by_type:type_name -> relayer.v1.GetBuilderFeeStatsReply.ByTypeEntry
-	17, // 6: relayer.v1.SubmitMatchRequest.maker_order:type_name -> relayer.v1.Order
-	17, // 7: relayer.v1.SubmitMatchRequest.taker_order:type_name -> relayer.v1.Order
-	13, // 8: relayer.v1.GetBuilderFeeStatsReply.ByTypeEntry.value:type_name -> relayer.v1.FeeStatsByType
-	2,  // 9: relayer.v1.Relayer.SubmitTransaction:input_type -> relayer.v1.SubmitTransactionRequest
-	4,  // 10: relayer.v1.Relayer.SubmitBatchTransaction:input_type -> relayer.v1.SubmitBatchTransactionRequest
-	7,  // 11: relayer.v1.Relayer.DeployWallet:input_type -> relayer.v1.DeployWalletRequest
-	9,  // 12: relayer.v1.Relayer.GetTransactionStatus:input_type -> relayer.v1.GetTransactionStatusRequest
-	12, // 13: relayer.v1.Relayer.GetBuilderFeeStats:input_type -> relayer.v1.GetBuilderFeeStatsRequest
-	15, // 14: relayer.v1.Relayer.GetOperatorBalance:input_type -> relayer.v1.GetOperatorBalanceRequest
-	18, // 15: relayer.v1.Relayer.SubmitMatch:input_type -> relayer.v1.SubmitMatchRequest
-	20, // 16: relayer.v1.Relayer.GetTransactionHashByOrderID:input_type -> relayer.v1.GetTransactionHashByOrderIDRequest
-	3,  // 17: relayer.v1.Relayer.SubmitTransaction:output_type -> relayer.v1.SubmitTransactionReply
-	6,  // 18: relayer.v1.Relayer.SubmitBatchTransaction:output_type -> relayer.v1.SubmitBatchTransactionReply
-	8,  // 19: relayer.v1.Relayer.DeployWallet:output_type -> relayer.v1.DeployWalletReply
-	11, // 20: relayer.v1.Relayer.GetTransactionStatus:output_type -> relayer.v1.GetTransactionStatusReply
-	14, // 21: relayer.v1.Relayer.GetBuilderFeeStats:output_type -> relayer.v1.GetBuilderFeeStatsReply
-	16, // 22: relayer.v1.Relayer.GetOperatorBalance:output_type -> relayer.v1.GetOperatorBalanceReply
-	19, // 23: relayer.v1.Relayer.SubmitMatch:output_type -> relayer.v1.SubmitMatchReply
-	21, // 24: relayer.v1.Relayer.GetTransactionHashByOrderID:output_type -> relayer.v1.GetTransactionHashByOrderIDReply
-	17, // [17:25] is the sub-list for method output_type
-	9,  // [9:17] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	3,  // 1: relayer.v1.SubmitTransactionRequest.safe_tx:type_name -> relayer.v1.SafeTransaction
+	4,  // 2: relayer.v1.SubmitTransactionRequest.proxy_tx:type_name -> relayer.v1.ProxyTransaction
+	6,  // 3: relayer.v1.SafeTransaction.calls:type_name -> relayer.v1.SafeCall
+	5,  // 4: relayer.v1.ProxyTransaction.calls:type_name -> relayer.v1.ProxyCall
+	9,  // 5: relayer.v1.SubmitBatchTransactionRequest.transactions:type_name -> relayer.v1.TransactionRequest
+	0,  // 6: relayer.v1.TransactionRequest.transaction_type:type_name -> relayer.v1.TransactionType
+	11, // 7: relayer.v1.SubmitBatchTransactionReply.results:type_name -> relayer.v1.CallResult
+	1,  // 8: relayer.v1.DeployWalletRequest.wallet_type:type_name -> relayer.v1.WalletType
+	11, // 9: relayer.v1.TransactionStatus.simulated_call_results:type_name -> relayer.v1.CallResult
+	6,  // 10: relayer.v1.TransactionStatus.safe_calls:type_name -> relayer.v1.SafeCall
+	15, // 11: relayer.v1.GetTransactionStatusReply.status:type_name -> relayer.v1.TransactionStatus
+	27, // 12: relayer.v1.GetBuilderFeeStatsReply.by_type:type_name -> relayer.v1.GetBuilderFeeStatsReply.ByTypeEntry
+	22, // 13: relayer.v1.SubmitMatchRequest.maker_order:type_name -> relayer.v1.Order
+	22, // 14: relayer.v1.SubmitMatchRequest.taker_order:type_name -> relayer.v1.Order
+	18, // 15: relayer.v1.GetBuilderFeeStatsReply.ByTypeEntry.value:type_name -> relayer.v1.FeeStatsByType
+	2,  // 16: relayer.v1.Relayer.SubmitTransaction:input_type -> relayer.v1.SubmitTransactionRequest
+	8,  // 17: relayer.v1.Relayer.SubmitBatchTransaction:input_type -> relayer.v1.SubmitBatchTransactionRequest
+	12, // 18: relayer.v1.Relayer.DeployWallet:input_type -> relayer.v1.DeployWalletRequest
+	14, // 19: relayer.v1.Relayer.GetTransactionStatus:input_type -> relayer.v1.GetTransactionStatusRequest
+	17, // 20: relayer.v1.Relayer.GetBuilderFeeStats:input_type -> relayer.v1.GetBuilderFeeStatsRequest
+	20, // 21: relayer.v1.Relayer.GetOperatorBalance:input_type -> relayer.v1.GetOperatorBalanceRequest
+	23, // 22: relayer.v1.Relayer.SubmitMatch:input_type -> relayer.v1.SubmitMatchRequest
+	25, // 23: relayer.v1.Relayer.GetTransactionHashByOrderID:input_type -> relayer.v1.GetTransactionHashByOrderIDRequest
+	7,  // 24: relayer.v1.Relayer.SubmitTransaction:output_type -> relayer.v1.SubmitTransactionReply
+	10, // 25: relayer.v1.Relayer.SubmitBatchTransaction:output_type -> relayer.v1.SubmitBatchTransactionReply
+	13, // 26: relayer.v1.Relayer.DeployWallet:output_type -> relayer.v1.DeployWalletReply
+	16, // 27: relayer.v1.Relayer.GetTransactionStatus:output_type -> relayer.v1.GetTransactionStatusReply
+	19, // 28: relayer.v1.Relayer.GetBuilderFeeStats:output_type -> relayer.v1.GetBuilderFeeStatsReply
+	21, // 29: relayer.v1.Relayer.GetOperatorBalance:output_type -> relayer.v1.GetOperatorBalanceReply
+	24, // 30: relayer.v1.Relayer.SubmitMatch:output_type -> relayer.v1.SubmitMatchReply
+	26, // 31: relayer.v1.Relayer.GetTransactionHashByOrderID:output_type -> relayer.v1.GetTransactionHashByOrderIDReply
+	24, // [24:32] is the sub-list for method output_type
+	16, // [16:24] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_relayer_v1_relayer_proto_init() }
@@ -1793,7 +2419,7 @@ func file_relayer_v1_relayer_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_relayer_v1_relayer_proto_rawDesc), len(file_relayer_v1_relayer_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   21,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -71,20 +71,598 @@ func (m *SubmitTransactionRequest) validate(all bool) error {
 
 	// no validation rules for Value
 
+	// no validation rules for ChainId
+
+	if all {
+		switch v := interface{}(m.GetSafeTx()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SubmitTransactionRequestValidationError{
+					field:  "SafeTx",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SubmitTransactionRequestValidationError{
+					field:  "SafeTx",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetSafeTx()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SubmitTransactionRequestValidationError{
+				field:  "SafeTx",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetProxyTx()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SubmitTransactionRequestValidationError{
+					field:  "ProxyTx",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SubmitTransactionRequestValidationError{
+					field:  "ProxyTx",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetProxyTx()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SubmitTransactionRequestValidationError{
+				field:  "ProxyTx",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return SubmitTransactionRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// SubmitTransactionRequestMultiError is an error wrapping multiple validation
+// errors returned by SubmitTransactionRequest.ValidateAll() if the designated
+// constraints aren't met.
+type SubmitTransactionRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SubmitTransactionRequestMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SubmitTransactionRequestMultiError) AllErrors() []error { return m }
+
+// SubmitTransactionRequestValidationError is the validation error returned by
+// SubmitTransactionRequest.Validate if the designated constraints aren't met.
+type SubmitTransactionRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SubmitTransactionRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SubmitTransactionRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SubmitTransactionRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SubmitTransactionRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SubmitTransactionRequestValidationError) ErrorName() string {
+	return "SubmitTransactionRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SubmitTransactionRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSubmitTransactionRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SubmitTransactionRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SubmitTransactionRequestValidationError{}
+
+// Validate checks the field values on SafeTransaction with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *SafeTransaction) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SafeTransaction with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SafeTransactionMultiError, or nil if none found.
+func (m *SafeTransaction) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SafeTransaction) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for SafeAddress
+
+	// no validation rules for To
+
+	// no validation rules for Value
+
+	// no validation rules for Data
+
+	// no validation rules for Operation
+
+	// no validation rules for SafeTxGas
+
+	// no validation rules for BaseGas
+
+	// no validation rules for GasPrice
+
+	// no validation rules for GasToken
+
+	// no validation rules for RefundReceiver
+
+	// no validation rules for Nonce
+
+	// no validation rules for Signatures
+
+	for idx, item := range m.GetCalls() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SafeTransactionValidationError{
+						field:  fmt.Sprintf("Calls[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SafeTransactionValidationError{
+						field:  fmt.Sprintf("Calls[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SafeTransactionValidationError{
+					field:  fmt.Sprintf("Calls[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return SafeTransactionMultiError(errors)
+	}
+
+	return nil
+}
+
+// SafeTransactionMultiError is an error wrapping multiple validation errors
+// returned by SafeTransaction.ValidateAll() if the designated constraints
+// aren't met.
+type SafeTransactionMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SafeTransactionMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SafeTransactionMultiError) AllErrors() []error { return m }
+
+// SafeTransactionValidationError is the validation error returned by
+// SafeTransaction.Validate if the designated constraints aren't met.
+type SafeTransactionValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SafeTransactionValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SafeTransactionValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SafeTransactionValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SafeTransactionValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SafeTransactionValidationError) ErrorName() string { return "SafeTransactionValidationError" }
+
+// Error satisfies the builtin error interface
+func (e SafeTransactionValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSafeTransaction.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SafeTransactionValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SafeTransactionValidationError{}
+
+// Validate checks the field values on ProxyTransaction with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ProxyTransaction) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ProxyTransaction with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ProxyTransactionMultiError, or nil if none found.
+func (m *ProxyTransaction) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ProxyTransaction) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for From
+
+	for idx, item := range m.GetCalls() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ProxyTransactionValidationError{
+						field:  fmt.Sprintf("Calls[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ProxyTransactionValidationError{
+						field:  fmt.Sprintf("Calls[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ProxyTransactionValidationError{
+					field:  fmt.Sprintf("Calls[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for RelayerFee
+
+	// no validation rules for GasPrice
+
+	// no validation rules for GasLimit
+
+	// no validation rules for Nonce
+
+	// no validation rules for Relay
+
+	// no validation rules for Signature
+
+	if len(errors) > 0 {
+		return ProxyTransactionMultiError(errors)
+	}
+
+	return nil
+}
+
+// ProxyTransactionMultiError is an error wrapping multiple validation errors
+// returned by ProxyTransaction.ValidateAll() if the designated constraints
+// aren't met.
+type ProxyTransactionMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ProxyTransactionMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ProxyTransactionMultiError) AllErrors() []error { return m }
+
+// ProxyTransactionValidationError is the validation error returned by
+// ProxyTransaction.Validate if the designated constraints aren't met.
+type ProxyTransactionValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProxyTransactionValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProxyTransactionValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProxyTransactionValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProxyTransactionValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProxyTransactionValidationError) ErrorName() string { return "ProxyTransactionValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ProxyTransactionValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProxyTransaction.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProxyTransactionValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProxyTransactionValidationError{}
+
+// Validate checks the field values on ProxyCall with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *ProxyCall) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ProxyCall with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ProxyCallMultiError, or nil
+// if none found.
+func (m *ProxyCall) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ProxyCall) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for To
+
+	// no validation rules for Value
+
+	// no validation rules for Data
+
+	if len(errors) > 0 {
+		return ProxyCallMultiError(errors)
+	}
+
+	return nil
+}
+
+// ProxyCallMultiError is an error wrapping multiple validation errors returned
+// by ProxyCall.ValidateAll() if the designated constraints aren't met.
+type ProxyCallMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ProxyCallMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ProxyCallMultiError) AllErrors() []error { return m }
+
+// ProxyCallValidationError is the validation error returned by
+// ProxyCall.Validate if the designated constraints aren't met.
+type ProxyCallValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProxyCallValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProxyCallValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProxyCallValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProxyCallValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProxyCallValidationError) ErrorName() string { return "ProxyCallValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ProxyCallValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProxyCall.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProxyCallValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProxyCallValidationError{}
+
+// Validate checks the field values on SafeCall with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *SafeCall) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SafeCall with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in SafeCallMultiError, or nil
+// if none found.
+func (m *SafeCall) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SafeCall) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for To
+
+	// no validation rules for Value
+
+	// no validation rules for Data
+
 	if len(errors) > 0 {
-		return SubmitTransactionRequestMultiError(errors)
+		return SafeCallMultiError(errors)
 	}
 
 	return nil
 }
 
-// SubmitTransactionRequestMultiError is an error wrapping multiple validation
-// errors returned by SubmitTransactionRequest.ValidateAll() if the designated
-// constraints aren't met.
-type SubmitTransactionRequestMultiError []error
+// SafeCallMultiError is an error wrapping multiple validation errors returned
+// by SafeCall.ValidateAll() if the designated constraints aren't met.
+type SafeCallMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m SubmitTransactionRequestMultiError) Error() string {
+func (m SafeCallMultiError) Error() string {
 	msgs := make([]string, 0, len(m))
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -93,11 +671,11 @@ func (m SubmitTransactionRequestMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m SubmitTransactionRequestMultiError) AllErrors() []error { return m }
+func (m SafeCallMultiError) AllErrors() []error { return m }
 
-// SubmitTransactionRequestValidationError is the validation error returned by
-// SubmitTransactionRequest.Validate if the designated constraints aren't met.
-type SubmitTransactionRequestValidationError struct {
+// SafeCallValidationError is the validation error returned by
+// SafeCall.Validate if the designated constraints aren't met.
+type SafeCallValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -105,24 +683,22 @@ type SubmitTransactionRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e SubmitTransactionRequestValidationError) Field() string { return e.field }
+func (e SafeCallValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e SubmitTransactionRequestValidationError) Reason() string { return e.reason }
+func (e SafeCallValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e SubmitTransactionRequestValidationError) Cause() error { return e.cause }
+func (e SafeCallValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e SubmitTransactionRequestValidationError) Key() bool { return e.key }
+func (e SafeCallValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e SubmitTransactionRequestValidationError) ErrorName() string {
-	return "SubmitTransactionRequestValidationError"
-}
+func (e SafeCallValidationError) ErrorName() string { return "SafeCallValidationError" }
 
 // Error satisfies the builtin error interface
-func (e SubmitTransactionRequestValidationError) Error() string {
+func (e SafeCallValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -134,14 +710,14 @@ func (e SubmitTransactionRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sSubmitTransactionRequest.%s: %s%s",
+		"invalid %sSafeCall.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = SubmitTransactionRequestValidationError{}
+var _ error = SafeCallValidationError{}
 
 var _ interface {
 	Field() string
@@ -149,7 +725,7 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = SubmitTransactionRequestValidationError{}
+} = SafeCallValidationError{}
 
 // Validate checks the field values on SubmitTransactionReply with the rules
 // defined in the proto definition for this message. If any rules are
@@ -317,6 +893,8 @@ func (m *SubmitBatchTransactionRequest) validate(all bool) error {
 
 	// no validation rules for BuilderApiKey
 
+	// no validation rules for Atomic
+
 	if len(errors) > 0 {
 		return SubmitBatchTransactionRequestMultiError(errors)
 	}
@@ -434,6 +1012,10 @@ func (m *TransactionRequest) validate(all bool) error {
 
 	// no validation rules for Value
 
+	// no validation rules for ChainId
+
+	// no validation rules for AllowFailure
+
 	if len(errors) > 0 {
 		return TransactionRequestMultiError(errors)
 	}
@@ -540,6 +1122,42 @@ func (m *SubmitBatchTransactionReply) validate(all bool) error {
 
 	// no validation rules for Message
 
+	// no validation rules for TaskId
+
+	for idx, item := range m.GetResults() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SubmitBatchTransactionReplyValidationError{
+						field:  fmt.Sprintf("Results[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SubmitBatchTransactionReplyValidationError{
+						field:  fmt.Sprintf("Results[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SubmitBatchTransactionReplyValidationError{
+					field:  fmt.Sprintf("Results[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
 	if len(errors) > 0 {
 		return SubmitBatchTransactionReplyMultiError(errors)
 	}
@@ -621,6 +1239,113 @@ var _ interface {
 	ErrorName() string
 } = SubmitBatchTransactionReplyValidationError{}
 
+// Validate checks the field values on CallResult with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *CallResult) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CallResult with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in CallResultMultiError, or
+// nil if none found.
+func (m *CallResult) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CallResult) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Index
+
+	// no validation rules for Success
+
+	// no validation rules for ReturnData
+
+	// no validation rules for ErrorMessage
+
+	if len(errors) > 0 {
+		return CallResultMultiError(errors)
+	}
+
+	return nil
+}
+
+// CallResultMultiError is an error wrapping multiple validation errors
+// returned by CallResult.ValidateAll() if the designated constraints aren't met.
+type CallResultMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CallResultMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CallResultMultiError) AllErrors() []error { return m }
+
+// CallResultValidationError is the validation error returned by
+// CallResult.Validate if the designated constraints aren't met.
+type CallResultValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CallResultValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CallResultValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CallResultValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CallResultValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CallResultValidationError) ErrorName() string { return "CallResultValidationError" }
+
+// Error satisfies the builtin error interface
+func (e CallResultValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCallResult.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CallResultValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CallResultValidationError{}
+
 // Validate checks the field values on DeployWalletRequest with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
@@ -978,6 +1703,82 @@ func (m *TransactionStatus) validate(all bool) error {
 
 	// no validation rules for UpdatedAt
 
+	// no validation rules for MaxFeePerGas
+
+	// no validation rules for MaxPriorityFeePerGas
+
+	// no validation rules for ErrorMessage
+
+	// no validation rules for ChainId
+
+	for idx, item := range m.GetSimulatedCallResults() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, TransactionStatusValidationError{
+						field:  fmt.Sprintf("SimulatedCallResults[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, TransactionStatusValidationError{
+						field:  fmt.Sprintf("SimulatedCallResults[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return TransactionStatusValidationError{
+					field:  fmt.Sprintf("SimulatedCallResults[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetSafeCalls() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, TransactionStatusValidationError{
+						field:  fmt.Sprintf("SafeCalls[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, TransactionStatusValidationError{
+						field:  fmt.Sprintf("SafeCalls[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return TransactionStatusValidationError{
+					field:  fmt.Sprintf("SafeCalls[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
 	if len(errors) > 0 {
 		return TransactionStatusMultiError(errors)
 	}
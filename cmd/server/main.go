@@ -40,8 +40,8 @@ func init() {
 	flag.StringVar(&runMode, "mode", "debug", "Run mode (debug, release)")
 }
 
-func newApp(logger log.Logger, hs *http.Server, gs *grpc.Server, monitorRunner *server.MonitorRunner) *kratos.App {
-	app := kratos.New(
+func newApp(logger log.Logger, hs *http.Server, gs *grpc.Server, monitorRunner *server.MonitorRunner, nonceReconciler *server.NonceReconciler, nonceAuditorRunner *server.NonceAuditorRunner, keyVerifier *server.KeyVerifier, relayVerifier *server.RelayVerifier, keyRotationRunner *server.KeyRotationRunner, queueRunner *server.QueueRunner) (*kratos.App, error) {
+	opts := []kratos.Option{
 		kratos.ID(id),
 		kratos.Name(Name),
 		kratos.Version(Version),
@@ -51,7 +51,48 @@ func newApp(logger log.Logger, hs *http.Server, gs *grpc.Server, monitorRunner *
 			gs,
 			hs,
 		),
-	)
+	}
+	// 停止服务后等待提交队列中执行中的任务完成（受 kratos 停止超时限制）
+	if queueRunner != nil {
+		opts = append(opts, kratos.AfterStop(queueRunner.Stop))
+	}
+	app := kratos.New(opts...)
+
+	// 启动前校验 Operator 私钥（解密失败或地址不匹配的 Operator 无法签名，置为 INACTIVE）
+	// 无法完成校验时终止启动，避免把任务分配给无法签名的 Operator
+	if keyVerifier != nil {
+		if err := keyVerifier.Start(context.Background()); err != nil {
+			return nil, fmt.Errorf("failed to verify operator keys: %w", err)
+		}
+	}
+
+	// 启动前校验 Operator 已在 RelayHub 注册为 relay（仅配置了 Proxy Wallet 中继的链）
+	if relayVerifier != nil {
+		if err := relayVerifier.Start(context.Background()); err != nil {
+			return nil, fmt.Errorf("failed to verify relay registrations: %w", err)
+		}
+	}
+
+	// 启动密钥轮换（如果开启，后台执行）
+	if keyRotationRunner != nil {
+		if err := keyRotationRunner.Start(context.Background()); err != nil {
+			logger.Log(log.LevelError, "msg", "failed to start key rotation", "error", err)
+		}
+	}
+
+	// 启动前对账 Operator Nonce（失败不阻塞启动，可按需再次对账）
+	if nonceReconciler != nil {
+		if err := nonceReconciler.Start(context.Background()); err != nil {
+			logger.Log(log.LevelError, "msg", "failed to reconcile operator nonces", "error", err)
+		}
+	}
+
+	// 启动提交队列（在 Nonce 对账之后，恢复上次退出时未处理完的任务）
+	if queueRunner != nil {
+		if err := queueRunner.Start(context.Background()); err != nil {
+			logger.Log(log.LevelError, "msg", "failed to start submission queue", "error", err)
+		}
+	}
 
 	// 启动监控器（如果提供了）
 	if monitorRunner != nil {
@@ -61,7 +102,15 @@ func newApp(logger log.Logger, hs *http.Server, gs *grpc.Server, monitorRunner *
 		}
 	}
 
-	return app
+	// 启动 Nonce 审计器（如果提供了）
+	if nonceAuditorRunner != nil {
+		ctx := context.Background()
+		if err := nonceAuditorRunner.Start(ctx); err != nil {
+			logger.Log(log.LevelError, "msg", "failed to start nonce auditor", "error", err)
+		}
+	}
+
+	return app, nil
 }
 
 func main() {
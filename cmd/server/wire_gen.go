@@ -7,23 +7,37 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/go-kratos/kratos/v2"
 	"github.com/go-kratos/kratos/v2/log"
 	"gorm.io/gorm"
 	"math/big"
+	"os"
+	"prediction-relayer-service/internal/auditor"
 	"prediction-relayer-service/internal/auth"
 	"prediction-relayer-service/internal/biz"
+	"prediction-relayer-service/internal/chain"
 	"prediction-relayer-service/internal/conf"
 	"prediction-relayer-service/internal/data"
 	"prediction-relayer-service/internal/executor"
 	"prediction-relayer-service/internal/fee"
+	"prediction-relayer-service/internal/gas"
+	"prediction-relayer-service/internal/keystore"
 	"prediction-relayer-service/internal/kms"
 	"prediction-relayer-service/internal/monitor"
+	"prediction-relayer-service/internal/multicall"
 	"prediction-relayer-service/internal/nonce"
+	"prediction-relayer-service/internal/queue"
+	"prediction-relayer-service/internal/rotation"
+	"prediction-relayer-service/internal/rpcpool"
+	"prediction-relayer-service/internal/selector"
 	"prediction-relayer-service/internal/server"
 	"prediction-relayer-service/internal/service"
+	"prediction-relayer-service/internal/signer"
+	"prediction-relayer-service/internal/wallet"
 	"strconv"
 	"time"
 )
@@ -60,11 +74,21 @@ func wireApp(c *conf.Bootstrap, logger log.Logger) (*kratos.App, func(), error)
 		return nil, nil, err
 	}
 	builderRepo := data.NewBuilderRepo(dataData)
+	security := c.Security
+	kmsKMS, err := NewKMS(security)
+	if err != nil {
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
 	builder := c.Builder
-	authService := NewAuthService(builderRepo, builder)
+	authService := NewAuthService(builderRepo, kmsKMS, builder)
 	transactionRepo := data.NewTransactionRepo(dataData)
-	chain := c.Chain
-	ethclientClient, cleanup5, err := NewEthClient(chain)
+	transactionAttemptRepo := data.NewTransactionAttemptRepo(dataData)
+	operatorRepo := data.NewOperatorRepo(dataData)
+	provider, cleanup5, err := NewSignerProvider(security, kmsKMS, operatorRepo, logger)
 	if err != nil {
 		cleanup4()
 		cleanup3()
@@ -72,7 +96,9 @@ func wireApp(c *conf.Bootstrap, logger log.Logger) (*kratos.App, func(), error)
 		cleanup()
 		return nil, nil, err
 	}
-	bigInt, err := NewChainID(chain)
+	builderFeeRepo := data.NewBuilderFeeRepo(dataData)
+	tracker := NewFeeTracker(builderFeeRepo)
+	router, cleanup6, err := NewChainRouter(c, db, dataData, transactionAttemptRepo, provider, tracker, rocketMQProducer, logger)
 	if err != nil {
 		cleanup5()
 		cleanup4()
@@ -81,19 +107,30 @@ func wireApp(c *conf.Bootstrap, logger log.Logger) (*kratos.App, func(), error)
 		cleanup()
 		return nil, nil, err
 	}
-	operatorRepo := data.NewOperatorRepo(dataData)
-	manager := NewNonceManager(db, operatorRepo, ethclientClient)
-	executor := NewExecutor(ethclientClient, bigInt, manager, operatorRepo, chain)
-	builderFeeRepo := data.NewBuilderFeeRepo(dataData)
-	tracker := NewFeeTracker(builderFeeRepo)
-	relayerService := biz.NewRelayerService(authService, transactionRepo, executor, tracker)
+	relayerService := biz.NewRelayerService(authService, transactionRepo, router, tracker)
 	serviceRelayerService := service.NewRelayerService(relayerService, authService, logger)
 	httpServer := server.NewHTTPServer(confServer, serviceRelayerService, logger)
 	grpcServer := server.NewGRPCServer(confServer, serviceRelayerService, logger)
-	monitor := NewMonitor(ethclientClient, transactionRepo, executor, logger)
-	monitorRunner := server.NewMonitorRunner(monitor, logger)
-	app := newApp(logger, httpServer, grpcServer, monitorRunner)
+	monitorRunner := server.NewMonitorRunner(router, logger)
+	nonceReconciler := server.NewNonceReconciler(router, logger)
+	nonceAuditorRunner := server.NewNonceAuditorRunner(router, logger)
+	keyVerifier := server.NewKeyVerifier(provider, operatorRepo, logger)
+	relayVerifier := server.NewRelayVerifier(router, operatorRepo, logger)
+	rotator := NewKeyRotator(kmsKMS, operatorRepo, builderRepo, logger)
+	keyRotationRunner := server.NewKeyRotationRunner(rotator, security, logger)
+	queueRunner := server.NewQueueRunner(router, logger)
+	app, err := newApp(logger, httpServer, grpcServer, monitorRunner, nonceReconciler, nonceAuditorRunner, keyVerifier, relayVerifier, keyRotationRunner, queueRunner)
+	if err != nil {
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
 	return app, func() {
+		cleanup6()
 		cleanup5()
 		cleanup4()
 		cleanup3()
@@ -104,18 +141,155 @@ func wireApp(c *conf.Bootstrap, logger log.Logger) (*kratos.App, func(), error)
 
 // wire.go:
 
-// NewEthClient 创建以太坊客户端
-func NewEthClient(c *conf.Chain) (*ethclient.Client, func(), error) {
-	client, err := ethclient.Dial(c.RpcUrl)
+// NewChainRouter 为每条链（chains，未配置时为单链配置 chain）创建独立的执行组件并按 Chain ID 路由
+func NewChainRouter(
+	c *conf.Bootstrap,
+	db *gorm.DB,
+	d *data.Data,
+	attemptRepo data.TransactionAttemptRepo,
+	signers signer.Provider,
+	feeTracker fee.Tracker,
+	producer data.RocketMQProducer,
+	logger log.Logger,
+) (chain.Router, func(), error) {
+	var stacks []*chain.Stack
+	var cleanups []func()
+	cleanup := func() {
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	}
+	for _, cc := range c.ChainList() {
+		stack, stackCleanup, err := newChainStack(c, cc, db, d, attemptRepo, signers, feeTracker, producer, logger)
+		if err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("failed to create chain %s: %w", cc.ChainId, err)
+		}
+		stacks = append(stacks, stack)
+		cleanups = append(cleanups, stackCleanup)
+	}
+	router, err := chain.NewRouter(stacks...)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to connect to ethereum client: %w", err)
+		cleanup()
+		return nil, nil, err
 	}
-	cleanup := func() {
-		if client != nil {
-			client.Close()
+	return router, cleanup, nil
+}
+
+// newChainStack 创建单条链的执行组件（RPC 池、Nonce 管理器、Gas 预言机、执行器、Operator 选择器、队列、监控器、审计器）
+// 交易和 Operator 仓储按 chain_id 隔离，chain.gas 未配置时使用全局 gas 配置
+func newChainStack(
+	c *conf.Bootstrap,
+	cc *conf.Chain,
+	db *gorm.DB,
+	d *data.Data,
+	attemptRepo data.TransactionAttemptRepo,
+	signers signer.Provider,
+	feeTracker fee.Tracker,
+	producer data.RocketMQProducer,
+	logger log.Logger,
+) (*chain.Stack, func(), error) {
+	chainID, err := NewChainID(cc)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger = log.With(logger, "chain_id", chainID.Int64())
+	ethClient, cleanup, err := NewRPCPool(cc, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txRepo := data.NewChainTransactionRepo(d, chainID.Int64())
+	operatorRepo := data.NewChainOperatorRepo(d, chainID.Int64())
+
+	nonceMgr, err := NewNonceManager(cc, db, d, chainID, operatorRepo, ethClient, producer, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	gasConf := c.Gas
+	if cc.Gas != nil {
+		gasConf = cc.Gas
+	}
+	gasOracle, err := NewGasOracle(gasConf, cc, ethClient)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	exec, err := NewExecutor(ethClient, chainID, nonceMgr, operatorRepo, signers, gasOracle, cc)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	operatorSelector, err := NewOperatorSelector(c.Operator, operatorRepo, txRepo, ethClient, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	multicall3 := multicall.DefaultAddress
+	if cc.Multicall3Address != "" {
+		if !common.IsHexAddress(cc.Multicall3Address) {
+			cleanup()
+			return nil, nil, fmt.Errorf("invalid multicall3 address: %s", cc.Multicall3Address)
 		}
+		multicall3 = cc.Multicall3Address
+	}
+	multiSend := wallet.DefaultMultiSendCallOnlyAddress
+	if cc.MultisendCallOnlyAddress != "" {
+		if !common.IsHexAddress(cc.MultisendCallOnlyAddress) {
+			cleanup()
+			return nil, nil, fmt.Errorf("invalid multisend call only address: %s", cc.MultisendCallOnlyAddress)
+		}
+		multiSend = cc.MultisendCallOnlyAddress
+	}
+	// Proxy Wallet 中继需要同时配置 ProxyWalletFactory 和 RelayHub（没有跨链通用的部署地址）
+	var proxyVerifier wallet.ProxyVerifier
+	if cc.ProxyFactoryAddress != "" || cc.RelayHubAddress != "" {
+		if !common.IsHexAddress(cc.ProxyFactoryAddress) || !common.IsHexAddress(cc.RelayHubAddress) {
+			cleanup()
+			return nil, nil, fmt.Errorf("invalid proxy wallet config: proxy_factory_address=%q relay_hub_address=%q", cc.ProxyFactoryAddress, cc.RelayHubAddress)
+		}
+		proxyVerifier = wallet.NewProxyVerifier(ethClient)
+	}
+
+	return &chain.Stack{
+		ChainID:      chainID.Int64(),
+		Executor:     exec,
+		NonceManager: nonceMgr,
+		Selector:     operatorSelector,
+		Queue:        NewQueue(c.Queue, cc, txRepo, operatorRepo, exec, nonceMgr, operatorSelector, logger),
+		Monitor:      NewMonitor(cc, ethClient, txRepo, attemptRepo, exec, feeTracker, logger),
+		Auditor:      NewNonceAuditor(nonceMgr, operatorRepo, txRepo, exec, producer, logger),
+		Multicall3:   multicall3,
+		Safe:         wallet.NewSafeVerifier(ethClient, chainID),
+		MultiSend:    multiSend,
+		Proxy:        proxyVerifier,
+		ProxyFactory: cc.ProxyFactoryAddress,
+		RelayHub:     cc.RelayHubAddress,
+	}, cleanup, nil
+}
+
+// NewRPCPool 创建 RPC 池（chain.rpc_url 为主节点，chain.rpc_urls 为备用节点）
+func NewRPCPool(c *conf.Chain, logger log.Logger) (*rpcpool.Pool, func(), error) {
+	cfg := rpcpool.Config{
+		URLs:                append([]string{c.RpcUrl}, c.RpcUrls...),
+		HealthCheckInterval: 5 * time.Second,
+		CheckTimeout:        3 * time.Second,
+		MaxHeadLag:          5,
+		MaxFailures:         3,
+		LagPenalty:          time.Second,
+	}
+	if c.HealthCheckInterval != nil && c.HealthCheckInterval.AsDuration() > 0 {
+		cfg.HealthCheckInterval = c.HealthCheckInterval.AsDuration()
 	}
-	return client, cleanup, nil
+	if c.MaxHeadLag > 0 {
+		cfg.MaxHeadLag = c.MaxHeadLag
+	}
+	pool, err := rpcpool.NewPool(cfg, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pool, pool.Close, nil
 }
 
 // NewChainID 创建 Chain ID
@@ -130,37 +304,163 @@ func NewChainID(c *conf.Chain) (*big.Int, error) {
 // NewAuthService 创建认证服务
 func NewAuthService(
 	builderRepo data.BuilderRepo,
+	k kms.KMS,
 	c *conf.Builder,
 ) auth.AuthService {
 	timestampWindow := int64(5 * 60 * 1000)
 	if c != nil && c.TimestampWindowMs > 0 {
 		timestampWindow = c.TimestampWindowMs
 	}
-	return auth.NewAuthService(builderRepo, timestampWindow)
+	return auth.NewAuthService(builderRepo, k, timestampWindow)
 }
 
-// NewNonceManager 创建 Nonce 管理器
+// NewNonceManager 创建 Nonce 管理器（根据 chain.nonce_manager 选择实现）
 func NewNonceManager(
+	c *conf.Chain,
 	db *gorm.DB,
+	d *data.Data,
+	chainID *big.Int,
 	operatorRepo data.OperatorRepo,
-	ethClient *ethclient.Client,
-) nonce.Manager {
-	return nonce.NewManager(db, operatorRepo, ethClient)
+	ethClient *rpcpool.Pool,
+	producer data.RocketMQProducer,
+	logger log.Logger,
+) (nonce.Manager, error) {
+	managerType := "db"
+	if c != nil && c.NonceManager != "" {
+		managerType = c.NonceManager
+	}
+	switch managerType {
+	case "db":
+		return nonce.NewManager(db, chainID.Int64(), operatorRepo, ethClient, producer, logger), nil
+	case "redis":
+		if d.Redis() == nil {
+			return nil, fmt.Errorf("redis nonce manager requires data.redis to be configured")
+		}
+		return nonce.NewRedisManager(db, d.Redis(), chainID.Int64(), operatorRepo, ethClient, producer, logger), nil
+	default:
+		return nil, fmt.Errorf("unsupported nonce manager: %s", managerType)
+	}
 }
 
 // NewExecutor 创建交易执行器
 func NewExecutor(
-	ethClient *ethclient.Client,
+	ethClient *rpcpool.Pool,
 	chainID *big.Int,
 	nonceMgr nonce.Manager,
 	operatorRepo data.OperatorRepo,
+	signers signer.Provider,
+	gasOracle gas.GasOracle,
 	c *conf.Chain,
-) executor.Executor {
-	gasMultiplier := int64(110)
-	if c != nil && c.GasPriceMultiplier > 0 {
-		gasMultiplier = c.GasPriceMultiplier
+) (executor.Executor, error) {
+	// 加载自定义错误 ABI，用于解码模拟执行的回滚原因
+	var abis []string
+	if c != nil {
+		for _, path := range c.ErrorAbiFiles {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read error abi file %s: %w", path, err)
+			}
+			abis = append(abis, string(content))
+		}
+	}
+	revertDecoder, err := executor.NewRevertDecoder(abis...)
+	if err != nil {
+		return nil, err
 	}
-	return executor.NewExecutor(ethClient, chainID, nonceMgr, operatorRepo, gasMultiplier)
+	return executor.NewExecutor(ethClient, chainID, nonceMgr, operatorRepo, signers, revertDecoder, gasOracle), nil
+}
+
+// NewGasOracle 创建 Gas 价格预言机
+func NewGasOracle(c *conf.Gas, chain *conf.Chain, ethClient *rpcpool.Pool) (gas.GasOracle, error) {
+	cfg := gas.Config{
+		HistoryBlocks:     20,
+		RewardPercentiles: [3]float64{10, 50, 90},
+		CacheTTL:          3 * time.Second,
+		TipMultiplier:     110,
+		MinPriorityFee:    new(big.Int),
+		MaxFee:            make(map[string]*big.Int),
+	}
+	if chain != nil && chain.GasPriceMultiplier > 0 {
+		cfg.TipMultiplier = chain.GasPriceMultiplier
+	}
+	if c != nil {
+		if c.HistoryBlocks > 0 {
+			cfg.HistoryBlocks = c.HistoryBlocks
+		}
+		if len(c.RewardPercentiles) > 0 {
+			if len(c.RewardPercentiles) != len(cfg.RewardPercentiles) {
+				return nil, fmt.Errorf("gas.reward_percentiles must have %d values (slow, standard, fast)", len(cfg.RewardPercentiles))
+			}
+			copy(cfg.RewardPercentiles[:], c.RewardPercentiles)
+		}
+		if c.CacheTtl != nil && c.CacheTtl.AsDuration() > 0 {
+			cfg.CacheTTL = c.CacheTtl.AsDuration()
+		}
+		cfg.MinPriorityFee = new(big.Int).Mul(big.NewInt(c.MinPriorityFeeGwei), big.NewInt(params.GWei))
+		for txType, gwei := range c.MaxFeeGwei {
+			cfg.MaxFee[txType] = new(big.Int).Mul(big.NewInt(gwei), big.NewInt(params.GWei))
+		}
+	}
+	return gas.NewGasOracle(ethClient, cfg), nil
+}
+
+// NewOperatorSelector 创建 Operator 选择器（根据 operator.selection_strategy 选择策略）
+func NewOperatorSelector(
+	c *conf.Operator,
+	operatorRepo data.OperatorRepo,
+	txRepo data.TransactionRepo,
+	ethClient *rpcpool.Pool,
+	logger log.Logger,
+) (selector.OperatorSelector, error) {
+	strategy := "round_robin"
+	balanceTTL := 15 * time.Second // 默认缓存 15 秒
+	if c != nil {
+		if c.SelectionStrategy != "" {
+			strategy = c.SelectionStrategy
+		}
+		if c.BalanceCacheTtl != nil && c.BalanceCacheTtl.AsDuration() > 0 {
+			balanceTTL = c.BalanceCacheTtl.AsDuration()
+		}
+	}
+	return selector.NewOperatorSelector(strategy, operatorRepo, txRepo, ethClient, balanceTTL, logger)
+}
+
+// NewQueue 创建持久化提交队列（重试次数上限读取 chain.max_retry）
+func NewQueue(
+	c *conf.Queue,
+	chain *conf.Chain,
+	txRepo data.TransactionRepo,
+	operatorRepo data.OperatorRepo,
+	exec executor.Executor,
+	nonceMgr nonce.Manager,
+	operatorSelector selector.OperatorSelector,
+	logger log.Logger,
+) queue.Queue {
+	cfg := queue.Config{
+		WorkersPerOperator: 4,
+		MaxPending:         10000,
+		PollInterval:       time.Second,
+		ProcessingLease:    5 * time.Minute,
+		MaxRetry:           3,
+	}
+	if c != nil {
+		if c.WorkersPerOperator > 0 {
+			cfg.WorkersPerOperator = int(c.WorkersPerOperator)
+		}
+		if c.MaxPending > 0 {
+			cfg.MaxPending = c.MaxPending
+		}
+		if c.PollInterval != nil && c.PollInterval.AsDuration() > 0 {
+			cfg.PollInterval = c.PollInterval.AsDuration()
+		}
+		if c.ProcessingLease != nil && c.ProcessingLease.AsDuration() > 0 {
+			cfg.ProcessingLease = c.ProcessingLease.AsDuration()
+		}
+	}
+	if chain != nil && chain.MaxRetry > 0 {
+		cfg.MaxRetry = int32(chain.MaxRetry)
+	}
+	return queue.NewQueue(cfg, txRepo, operatorRepo, exec, nonceMgr, operatorSelector, logger)
 }
 
 // NewFeeTracker 创建费用追踪器
@@ -170,13 +470,34 @@ func NewFeeTracker(feeRepo data.BuilderFeeRepo) fee.Tracker {
 
 // NewMonitor 创建交易监控器
 func NewMonitor(
-	ethClient *ethclient.Client,
+	c *conf.Chain,
+	ethClient *rpcpool.Pool,
 	txRepo data.TransactionRepo,
+	attemptRepo data.TransactionAttemptRepo,
 	exec executor.Executor,
+	feeTracker fee.Tracker,
 	logger log.Logger,
 ) monitor.Monitor {
 	pendingTimeout := 30 * time.Second
-	return monitor.NewMonitor(ethClient, txRepo, exec, logger, pendingTimeout)
+	confirmations := int64(30)
+	if c.ConfirmationBlocks > 0 {
+		confirmations = c.ConfirmationBlocks
+	}
+	return monitor.NewMonitor(ethClient, txRepo, attemptRepo, exec, feeTracker, logger, pendingTimeout, confirmations, c.WsUrl)
+}
+
+// NewNonceAuditor 创建 Nonce 审计器
+func NewNonceAuditor(
+	nonceMgr nonce.Manager,
+	operatorRepo data.OperatorRepo,
+	txRepo data.TransactionRepo,
+	exec executor.Executor,
+	producer data.RocketMQProducer,
+	logger log.Logger,
+) auditor.NonceAuditor {
+	interval := time.Minute
+	gracePeriod := 2 * time.Minute
+	return auditor.NewNonceAuditor(nonceMgr, operatorRepo, txRepo, exec, producer, logger, interval, gracePeriod)
 }
 
 // NewKMS 创建 KMS 服务
@@ -191,3 +512,53 @@ func NewKMS(c *conf.Security) (kms.KMS, error) {
 	}
 	return kms.NewKMS(kmsType, kmsConfig)
 }
+
+// NewKeyStore 创建 Operator 私钥存储（退出时清零缓存的私钥）
+func NewKeyStore(k kms.KMS, operatorRepo data.OperatorRepo, c *conf.Security, logger log.Logger) (keystore.KeyStore, func()) {
+	ttl := 5 * time.Minute // 默认缓存 5 分钟
+	if c != nil && c.KeyCacheTtl != nil && c.KeyCacheTtl.AsDuration() > 0 {
+		ttl = c.KeyCacheTtl.AsDuration()
+	}
+	ks := keystore.NewKeyStore(k, operatorRepo, ttl, logger)
+	return ks, ks.Purge
+}
+
+// NewKeyRotator 创建主密钥轮换器
+func NewKeyRotator(k kms.KMS, operatorRepo data.OperatorRepo, builderRepo data.BuilderRepo, logger log.Logger) rotation.Rotator {
+	return rotation.NewRotator(k, operatorRepo, builderRepo, logger)
+}
+
+// NewSignerProvider 创建交易签名器提供者（根据 security.signer.type 选择实现）
+func NewSignerProvider(c *conf.Security, k kms.KMS, operatorRepo data.OperatorRepo, logger log.Logger) (signer.Provider, func(), error) {
+	signerType := string(signer.SignerTypeLocal)
+	if c != nil && c.Signer != nil && c.Signer.Type != "" {
+		signerType = c.Signer.Type
+	}
+	switch signerType {
+	case string(signer.SignerTypeLocal):
+		ks, cleanup := NewKeyStore(k, operatorRepo, c, logger)
+		return signer.NewLocalProvider(ks), cleanup, nil
+	case string(signer.SignerTypeRemote):
+		timeout := 10 * time.Second // 默认 10 秒
+		if c.Signer.Timeout != nil && c.Signer.Timeout.AsDuration() > 0 {
+			timeout = c.Signer.Timeout.AsDuration()
+		}
+		return signer.NewRemoteProvider(context.Background(), signer.RemoteConfig{
+			URL:     c.Signer.Url,
+			Headers: c.Signer.Headers,
+			Timeout: timeout,
+		}, operatorRepo)
+	case string(signer.SignerTypeKMS):
+		kmsConfig := c.Signer.KmsConfig
+		if kmsConfig == "" {
+			kmsConfig = c.KmsConfig
+		}
+		client, err := kms.NewAWSKMSFromJSON(kmsConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		return signer.NewKMSProvider(client, operatorRepo), func() {}, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported signer type: %s", signerType)
+	}
+}
@@ -25,8 +25,8 @@ DROP TABLE IF EXISTS `builder`;
 CREATE TABLE `builder` (
   `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键 ID',
   `api_key` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'API Key（唯一标识）',
-  `secret_hash` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'Secret 哈希值（加密存储）',
-  `passphrase_hash` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'Passphrase 哈希值（加密存储）',
+  `secret_hash` varchar(512) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'Secret（KMS 信封加密存储：enc:v1:<key_id>:<alg>:<payload>；历史明文需标记为 plain:<明文> 后由密钥轮换加密）',
+  `passphrase_hash` varchar(512) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'Passphrase（KMS 信封加密存储：enc:v1:<key_id>:<alg>:<payload>；历史明文需标记为 plain:<明文> 后由密钥轮换加密）',
   `name` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL COMMENT 'Builder 名称（可选）',
   `status` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'ACTIVE' COMMENT '状态：ACTIVE（激活）, INACTIVE（未激活）',
   `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
@@ -45,12 +45,13 @@ CREATE TABLE `builder_fee` (
   `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键 ID',
   `builder_api_key` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'Builder API Key',
   `transaction_type` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '交易类型（用于按类型统计）',
-  `transaction_id` varchar(36) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '关联 transaction.task_id',
+  `transaction_id` varchar(36) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '关联 transaction.task_id（每笔交易最多一条费用记录）',
   `gas_used` bigint NOT NULL COMMENT 'Gas 消耗量',
-  `gas_price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'Gas 价格（字符串，支持大整数）',
-  `total_cost` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '总成本（MATIC，字符串格式）',
+  `gas_price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '实际成交 Gas 价格（wei，字符串）',
+  `total_cost` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '总成本（MATIC，18 位精度的十进制字符串）',
   `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
   PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_builder_fee_transaction_id` (`transaction_id`),
   KEY `idx_builder_api_key` (`builder_api_key`),
   KEY `idx_transaction_type` (`transaction_type`),
   KEY `idx_created_at` (`created_at`)
@@ -63,15 +64,17 @@ CREATE TABLE `builder_fee` (
 DROP TABLE IF EXISTS `operator`;
 CREATE TABLE `operator` (
   `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键 ID',
+  `chain_id` bigint NOT NULL DEFAULT '137' COMMENT '链 ID（同一地址在每条链上各有一条记录，Nonce 独立）',
   `address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'Operator 钱包地址',
-  `private_key_encrypted` text COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '私钥（加密存储）',
+  `private_key_encrypted` text COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '私钥（KMS 加密存储，解密后为 hex 私钥；KMS 托管签名的 Operator 为空）',
+  `kms_key_id` varchar(2048) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT 'KMS 托管签名密钥 ID / ARN（ECC_SECG_P256K1，私钥不离开 KMS）',
   `status` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'ACTIVE' COMMENT '状态：ACTIVE（激活）, INACTIVE（未激活）',
   `balance_threshold` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '1000000000000000000' COMMENT '余额告警阈值（wei，默认 1 MATIC）',
-  `current_nonce` bigint NOT NULL DEFAULT '0' COMMENT '当前 nonce（用于交易排序）',
+  `current_nonce` bigint NOT NULL DEFAULT '0' COMMENT '下一个待分配的 nonce（与链上 pending nonce 对账）',
   `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
   `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
   PRIMARY KEY (`id`),
-  UNIQUE KEY `idx_operator_address` (`address`),
+  UNIQUE KEY `idx_operator_chain_address` (`chain_id`,`address`),
   KEY `idx_status` (`status`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Operator 钱包管理表';
 
@@ -83,7 +86,8 @@ DROP TABLE IF EXISTS `transaction`;
 CREATE TABLE `transaction` (
   `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键 ID',
   `task_id` varchar(36) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '任务 ID（UUID）',
-  `tx_hash` varchar(66) COLLATE utf8mb4_unicode_ci DEFAULT NULL COMMENT '交易哈希（0x 开头的 66 字符）',
+  `chain_id` bigint NOT NULL DEFAULT '137' COMMENT '链 ID（137 Polygon、80002 Amoy 等）',
+  `tx_hash` varchar(66) COLLATE utf8mb4_unicode_ci DEFAULT NULL COMMENT '交易哈希（0x 开头的 66 字符，广播前为 NULL）',
   `builder_api_key` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'Builder API Key（用于费用追踪）',
   `from_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '发送方地址（Operator 地址）',
   `to_address` varchar(42) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '接收方地址（目标合约或转发器）',
@@ -96,15 +100,23 @@ CREATE TABLE `transaction` (
   `nonce` bigint NOT NULL COMMENT '交易 nonce',
   `gas_limit` bigint NOT NULL COMMENT 'Gas 限制',
   `gas_price` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'Gas 价格（字符串，支持大整数）',
-  `status` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'PENDING' COMMENT '交易状态：PENDING（待处理）, MINED（已打包）, FAILED（失败）, REPLACED（被替换）',
+  `max_fee_per_gas` varchar(78) COLLATE utf8mb4_unicode_ci DEFAULT NULL COMMENT 'EIP-1559 maxFeePerGas（wei，字符串）',
+  `max_priority_fee_per_gas` varchar(78) COLLATE utf8mb4_unicode_ci DEFAULT NULL COMMENT 'EIP-1559 maxPriorityFeePerGas（wei，字符串）',
+  `status` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'QUEUED' COMMENT '交易状态：QUEUED（排队中）, PROCESSING（worker 处理中）, PENDING（已广播待确认）, MINED（已打包）, CONFIRMED（已达到确认深度）, FAILED（失败）, REPLACED（被替换）',
   `block_number` bigint DEFAULT NULL COMMENT '区块号（交易被打包后才有值）',
+  `block_hash` varchar(66) COLLATE utf8mb4_unicode_ci DEFAULT NULL COMMENT '打包区块哈希（用于检测链重组）',
   `gas_used` bigint DEFAULT NULL COMMENT '实际使用的 Gas（交易被打包后才有值）',
-  `error_message` text COLLATE utf8mb4_unicode_ci COMMENT '错误信息（交易失败时记录）',
+  `effective_gas_price` varchar(78) COLLATE utf8mb4_unicode_ci DEFAULT NULL COMMENT '实际成交 Gas 价格（wei，取自交易回执）',
+  `error_message` text COLLATE utf8mb4_unicode_ci COMMENT '错误信息（[错误分类] 错误消息，失败或重试时记录）',
+  `simulated_call_results` text COLLATE utf8mb4_unicode_ci COMMENT 'Multicall3 原子批量交易提交时各调用的模拟执行结果（JSON，不是链上执行结果）',
+  `retry_count` int NOT NULL DEFAULT '0' COMMENT '已重试次数（不超过 chain.max_retry）',
+  `next_retry_at` datetime(3) DEFAULT NULL COMMENT '退避重试时间（排队任务在此之前不会被认领）',
   `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
   `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
   PRIMARY KEY (`id`),
   UNIQUE KEY `idx_transaction_task_id` (`task_id`),
   UNIQUE KEY `idx_transaction_tx_hash` (`tx_hash`),
+  KEY `idx_chain_id` (`chain_id`),
   KEY `idx_builder_api_key` (`builder_api_key`),
   KEY `idx_from_address` (`from_address`),
   KEY `idx_status` (`status`),
@@ -112,4 +124,26 @@ CREATE TABLE `transaction` (
   KEY `idx_status_created_at` (`updated_at`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='交易记录表';
 
+-- ----------------------------
+-- Table structure for transaction_attempt
+-- 交易广播记录表：记录原始交易及每一次 RBF 替换交易，替换交易上链后通过 task_id 归属到原任务
+-- ----------------------------
+DROP TABLE IF EXISTS `transaction_attempt`;
+CREATE TABLE `transaction_attempt` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键 ID',
+  `task_id` varchar(36) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '关联 transaction.task_id',
+  `tx_hash` varchar(66) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '本次广播的交易哈希',
+  `attempt` int NOT NULL COMMENT '尝试序号（0 为原始交易）',
+  `nonce` bigint NOT NULL COMMENT '交易 nonce',
+  `max_fee_per_gas` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'EIP-1559 maxFeePerGas（wei，字符串）',
+  `max_priority_fee_per_gas` varchar(78) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'EIP-1559 maxPriorityFeePerGas（wei，字符串）',
+  `raw_tx` text COLLATE utf8mb4_unicode_ci NOT NULL COMMENT '已签名交易（hex）',
+  `status` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'BROADCAST' COMMENT '状态：BROADCAST（已广播）, REPLACED（被替换）, MINED（已打包）, FAILED（已打包但执行失败）',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '广播时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_transaction_attempt_tx_hash` (`tx_hash`),
+  KEY `idx_task_id` (`task_id`),
+  KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='交易广播记录表';
+
 SET FOREIGN_KEY_CHECKS = 1;
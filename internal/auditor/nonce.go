@@ -0,0 +1,294 @@
+package auditor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/executor"
+	"prediction-relayer-service/internal/nonce"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/google/uuid"
+)
+
+const (
+	// gapFillTransactionType 自转账填补交易的交易类型
+	gapFillTransactionType = "NONCE_GAP_FILL"
+	// internalBuilderAPIKey 由 Relayer 自身发起的交易使用的 Builder API Key（用于费用归集）
+	internalBuilderAPIKey = "relayer"
+)
+
+// NonceAuditor Nonce 审计器接口
+// 定期比对 transaction 表中已分配的 Nonce 与链上 Nonce，发现并填补空洞，避免后续交易永久卡住
+type NonceAuditor interface {
+	// Start 启动审计器
+	Start(ctx context.Context) error
+
+	// Stop 停止审计器
+	Stop()
+
+	// Audit 执行一次审计，返回本次填补的空洞
+	Audit(ctx context.Context) ([]*GapReport, error)
+}
+
+// GapReport Nonce 空洞填补报告（通过 RocketMQ 发送，tag 为 NONCE_GAP_FILLED）
+type GapReport struct {
+	Operator string `json:"operator"`
+	Nonce    uint64 `json:"nonce"`
+	FilledBy string `json:"filled_by"` // TASK（复用排队任务）, SELF_TRANSFER（0 值自转账）
+	TaskID   string `json:"task_id"`
+	TxHash   string `json:"tx_hash"`
+	FilledAt int64  `json:"filled_at"`
+}
+
+// nonceAuditor Nonce 审计器实现
+type nonceAuditor struct {
+	nonceMgr     nonce.Manager
+	operatorRepo data.OperatorRepo
+	txRepo       data.TransactionRepo
+	executor     executor.Executor
+	producer     data.RocketMQProducer // 可选，用于发送填补报告
+	logger       log.Logger
+	interval     time.Duration // 审计间隔
+	gracePeriod  time.Duration // 宽限期：广播/创建时间在此之内的交易不参与判断，避免与正在执行的交易竞争
+	stopCh       chan struct{}
+}
+
+// NewNonceAuditor 创建 Nonce 审计器
+func NewNonceAuditor(
+	nonceMgr nonce.Manager,
+	operatorRepo data.OperatorRepo,
+	txRepo data.TransactionRepo,
+	exec executor.Executor,
+	producer data.RocketMQProducer,
+	logger log.Logger,
+	interval time.Duration,
+	gracePeriod time.Duration,
+) NonceAuditor {
+	return &nonceAuditor{
+		nonceMgr:     nonceMgr,
+		operatorRepo: operatorRepo,
+		txRepo:       txRepo,
+		executor:     exec,
+		producer:     producer,
+		logger:       logger,
+		interval:     interval,
+		gracePeriod:  gracePeriod,
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// Start 启动审计器
+func (a *nonceAuditor) Start(ctx context.Context) error {
+	a.logger.Log(log.LevelInfo, "msg", "starting nonce auditor")
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-a.stopCh:
+			return nil
+		case <-ticker.C:
+			if _, err := a.Audit(ctx); err != nil {
+				a.logger.Log(log.LevelError, "msg", "failed to audit operator nonces", "error", err)
+			}
+		}
+	}
+}
+
+// Stop 停止审计器
+func (a *nonceAuditor) Stop() {
+	close(a.stopCh)
+}
+
+// Audit 执行一次审计，返回本次填补的空洞
+func (a *nonceAuditor) Audit(ctx context.Context) ([]*GapReport, error) {
+	operators, err := a.operatorRepo.GetActiveOperators(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operators: %w", err)
+	}
+
+	var reports []*GapReport
+	var errs []error
+	for _, op := range operators {
+		filled, err := a.auditOperator(ctx, op)
+		reports = append(reports, filled...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("operator %s: %w", op.Address, err))
+		}
+	}
+
+	return reports, errors.Join(errs...)
+}
+
+// auditOperator 审计单个 Operator
+func (a *nonceAuditor) auditOperator(ctx context.Context, op *data.Operator) ([]*GapReport, error) {
+	// 1. 链上 pending Nonce 是交易池中连续可执行序列之后的第一个 Nonce，即第一个可能的空洞
+	chainNonce, err := a.nonceMgr.GetPendingNonce(ctx, op.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 获取已分配且已广播、但未确认的交易
+	txs, err := a.txRepo.GetBroadcastByOperator(ctx, op.Address, int64(chainNonce))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broadcast transactions: %w", err)
+	}
+
+	// 3. 只处理被后续交易卡住的空洞：以宽限期之前广播的最大 Nonce 作为上界
+	cutoff := time.Now().Add(-a.gracePeriod)
+	allocated := make(map[uint64]bool, len(txs))
+	var upper uint64
+	stuck := false
+	for _, tx := range txs {
+		n := uint64(tx.Nonce)
+		allocated[n] = true
+		if tx.UpdatedAt.Before(cutoff) && n >= upper {
+			upper = n
+			stuck = true
+		}
+	}
+	if !stuck {
+		return nil, nil
+	}
+
+	var gaps []uint64
+	for n := chainNonce; n < upper; n++ {
+		if !allocated[n] {
+			gaps = append(gaps, n)
+		}
+	}
+	if len(gaps) == 0 {
+		return nil, nil
+	}
+
+	a.logger.Log(log.LevelWarn, "msg", "nonce gaps detected", "operator", op.Address, "chain_nonce", chainNonce, "gaps", gaps)
+
+	// 4. 逐个填补：优先复用长时间未广播的排队任务，否则发送 0 值自转账
+	queued, err := a.txRepo.ClaimQueued(ctx, op.Address, cutoff, len(gaps))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim queued transactions: %w", err)
+	}
+
+	var reports []*GapReport
+	var errs []error
+	for i, n := range gaps {
+		var report *GapReport
+		if i < len(queued) {
+			report, err = a.fillWithTask(ctx, op, queued[i], n)
+		} else {
+			report, err = a.fillWithSelfTransfer(ctx, op, n)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("nonce %d: %w", n, err))
+			continue
+		}
+
+		a.logger.Log(log.LevelInfo, "msg", "nonce gap filled", "operator", op.Address, "nonce", n, "filled_by", report.FilledBy, "task_id", report.TaskID, "tx_hash", report.TxHash)
+		a.emitReport(ctx, report)
+		reports = append(reports, report)
+	}
+
+	return reports, errors.Join(errs...)
+}
+
+// fillWithTask 使用排队中的任务填补空洞
+func (a *nonceAuditor) fillWithTask(ctx context.Context, op *data.Operator, tx *data.Transaction, n uint64) (*GapReport, error) {
+	result, err := a.executor.ExecuteWithNonce(ctx, tx, op, n)
+	if err != nil {
+		// 放回队列，由队列 worker 按正常流程重新执行
+		if rerr := a.txRepo.Requeue(ctx, tx.TaskID); rerr != nil {
+			a.logger.Log(log.LevelError, "msg", "failed to requeue task", "task_id", tx.TaskID, "error", rerr)
+		}
+		return nil, fmt.Errorf("failed to execute queued task %s: %w", tx.TaskID, err)
+	}
+	if result.SendError != nil {
+		a.logger.Log(log.LevelWarn, "msg", "gap fill broadcast is ambiguous", "task_id", tx.TaskID, "tx_hash", result.TxHash, "error", result.SendError)
+	}
+
+	if err := a.txRepo.RecordExecution(ctx, newAttempt(tx.TaskID, result), int64(result.GasLimit)); err != nil {
+		return nil, fmt.Errorf("failed to record execution: %w", err)
+	}
+
+	return &GapReport{
+		Operator: op.Address,
+		Nonce:    n,
+		FilledBy: "TASK",
+		TaskID:   tx.TaskID,
+		TxHash:   result.TxHash,
+		FilledAt: time.Now().Unix(),
+	}, nil
+}
+
+// fillWithSelfTransfer 使用 0 值自转账填补空洞
+// 自转账同样写入 transaction 表，使该 Nonce 在后续审计中视为已分配，并由监控器跟踪确认
+func (a *nonceAuditor) fillWithSelfTransfer(ctx context.Context, op *data.Operator, n uint64) (*GapReport, error) {
+	result, err := a.executor.SendSelfTransfer(ctx, op, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send self transfer: %w", err)
+	}
+	if result.SendError != nil {
+		a.logger.Log(log.LevelWarn, "msg", "gap fill broadcast is ambiguous", "operator", op.Address, "tx_hash", result.TxHash, "error", result.SendError)
+	}
+
+	taskID := uuid.New().String()
+	tx := &data.Transaction{
+		TaskID:               taskID,
+		TxHash:               result.TxHash,
+		BuilderAPIKey:        internalBuilderAPIKey,
+		FromAddress:          op.Address,
+		ToAddress:            op.Address,
+		TargetContract:       op.Address,
+		TransactionType:      gapFillTransactionType,
+		Data:                 "",
+		Value:                "0x0",
+		Nonce:                int64(result.Nonce),
+		GasLimit:             int64(result.GasLimit),
+		GasPrice:             result.MaxFeePerGas.String(),
+		MaxFeePerGas:         result.MaxFeePerGas.String(),
+		MaxPriorityFeePerGas: result.MaxPriorityFeePerGas.String(),
+		Status:               "PENDING",
+	}
+	if err := a.txRepo.CreateWithAttempt(ctx, tx, newAttempt(taskID, result)); err != nil {
+		return nil, fmt.Errorf("failed to record self transfer: %w", err)
+	}
+
+	return &GapReport{
+		Operator: op.Address,
+		Nonce:    n,
+		FilledBy: "SELF_TRANSFER",
+		TaskID:   taskID,
+		TxHash:   result.TxHash,
+		FilledAt: time.Now().Unix(),
+	}, nil
+}
+
+// emitReport 发送空洞填补报告
+func (a *nonceAuditor) emitReport(ctx context.Context, report *GapReport) {
+	if a.producer == nil {
+		return
+	}
+	if err := a.producer.SendMessage(ctx, "", "NONCE_GAP_FILLED", report); err != nil {
+		a.logger.Log(log.LevelError, "msg", "failed to send nonce gap report", "error", err)
+	}
+}
+
+// newAttempt 根据执行结果构建第 0 次广播记录
+func newAttempt(taskID string, result *executor.ExecutionResult) *data.TransactionAttempt {
+	return &data.TransactionAttempt{
+		TaskID:               taskID,
+		TxHash:               result.TxHash,
+		Attempt:              0,
+		Nonce:                int64(result.Nonce),
+		MaxFeePerGas:         result.MaxFeePerGas.String(),
+		MaxPriorityFeePerGas: result.MaxPriorityFeePerGas.String(),
+		RawTx:                result.RawTx,
+		Status:               "BROADCAST",
+	}
+}
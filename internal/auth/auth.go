@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/kms"
 )
 
 // AuthService Builder 认证服务接口
@@ -36,13 +37,15 @@ type AuthRequest struct {
 // authService Builder 认证服务实现
 type authService struct {
 	builderRepo     data.BuilderRepo
-	timestampWindow int64 // 时间戳验证窗口（毫秒）
+	kms             kms.KMS // 解密 Builder 的 Secret 和 Passphrase
+	timestampWindow int64   // 时间戳验证窗口（毫秒）
 }
 
 // NewAuthService 创建认证服务
-func NewAuthService(builderRepo data.BuilderRepo, timestampWindow int64) AuthService {
+func NewAuthService(builderRepo data.BuilderRepo, k kms.KMS, timestampWindow int64) AuthService {
 	return &authService{
 		builderRepo:     builderRepo,
+		kms:             k,
 		timestampWindow: timestampWindow,
 	}
 }
@@ -92,19 +95,20 @@ func (s *authService) ValidateBuilderAuth(ctx context.Context, req *AuthRequest)
 		return nil, fmt.Errorf("builder status is not active: %s", builder.Status)
 	}
 
-	// 5. 验证 Passphrase（需要解密后比较）
-	// TODO: 实现 Passphrase 解密和验证
-	// 这里简化处理，实际应该使用加密库解密 passphrase_hash 后比较
-	if req.Passphrase != builder.PassphraseHash {
-		// 注意：实际应该解密后比较，这里仅作示例
+	// 5. 验证 Passphrase（解密后比较）
+	passphrase, err := s.decryptCredential(ctx, builder.PassphraseHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt passphrase: %w", err)
+	}
+	if !hmac.Equal([]byte(req.Passphrase), []byte(passphrase)) {
 		return nil, fmt.Errorf("invalid passphrase")
 	}
 
-	// 6. 验证 HMAC 签名
-	// 需要解密 secret_hash 获取原始 secret
-	// TODO: 实现 Secret 解密
-	// 这里简化处理，假设 secret_hash 就是 secret（实际应该解密）
-	secret := builder.SecretHash // 实际应该解密
+	// 6. 验证 HMAC 签名（解密 Secret 获取原始 secret）
+	secret, err := s.decryptCredential(ctx, builder.SecretHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret: %w", err)
+	}
 
 	expectedSignature := s.BuildHMACSignature(secret, timestamp, req.Method, req.Path, req.Body)
 	if !hmac.Equal([]byte(expectedSignature), []byte(req.Signature)) {
@@ -114,6 +118,15 @@ func (s *authService) ValidateBuilderAuth(ctx context.Context, req *AuthRequest)
 	return builder, nil
 }
 
+// decryptCredential 解密 Builder 凭证
+// 只有显式标记为历史明文（plain: 前缀）的凭证按明文处理，其余无法解密时一律拒绝
+func (s *authService) decryptCredential(ctx context.Context, value string) (string, error) {
+	if plain, ok := kms.ParseLegacyPlaintext(value); ok {
+		return plain, nil
+	}
+	return s.kms.Decrypt(ctx, value)
+}
+
 // BuildHMACSignature 构建 HMAC 签名
 // 参考：https://docs.polymarket.com/developers/builders/relayer-client
 // signature = HMAC-SHA256(secret, timestamp + method + path + body)
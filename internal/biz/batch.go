@@ -0,0 +1,219 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"prediction-relayer-service/internal/chain"
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/executor"
+	"prediction-relayer-service/internal/multicall"
+	"prediction-relayer-service/internal/selector"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/google/uuid"
+)
+
+// CallResult Multicall3 子调用结果（由 aggregate3 返回数据解码）
+type CallResult struct {
+	Index        int32  `json:"index"`
+	Success      bool   `json:"success"`
+	ReturnData   string `json:"return_data"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// submitAtomicBatch 原子批量提交：打包为一笔 Multicall3 aggregate3 交易（单个任务 ID、单个 Nonce）
+// 子调用的 msg.sender 为 Multicall3 合约，适用于通过签名授权而非校验 msg.sender 的调用
+func (s *relayerService) submitAtomicBatch(ctx context.Context, builderAPIKey string, txReqs []*SubmitTransactionRequest) (*SubmitBatchTransactionReply, error) {
+	// 1. 校验并编码子调用：所有调用须在同一条链上，且不携带 value（aggregate3 不转发 value）
+	// 整批作为一笔交易执行，子调用的签名、转发器、交易类型、Safe / Proxy 请求无法生效，设置时拒绝而不是忽略
+	chainID := txReqs[0].ChainID
+	calls := make([]multicall.Call, 0, len(txReqs))
+	for i, txReq := range txReqs {
+		if txReq.ChainID != chainID {
+			return nil, errors.BadRequest("INVALID_BATCH", fmt.Sprintf("call %d: all calls in an atomic batch must target the same chain", i))
+		}
+		if field := unsupportedAtomicField(txReq); field != "" {
+			return nil, errors.BadRequest("INVALID_BATCH", fmt.Sprintf("call %d: %s is not supported in an atomic batch", i, field))
+		}
+		if !common.IsHexAddress(txReq.To) {
+			return nil, errors.BadRequest("INVALID_BATCH", fmt.Sprintf("call %d: invalid target address %q", i, txReq.To))
+		}
+		value, ok := parseValue(txReq.Value)
+		if !ok {
+			return nil, errors.BadRequest("INVALID_BATCH", fmt.Sprintf("call %d: invalid value %q", i, txReq.Value))
+		}
+		if value.Sign() != 0 {
+			return nil, errors.BadRequest("INVALID_BATCH", fmt.Sprintf("call %d: calls in an atomic batch cannot carry value", i))
+		}
+		calls = append(calls, multicall.Call{
+			Target:       common.HexToAddress(txReq.To),
+			AllowFailure: txReq.AllowFailure,
+			CallData:     common.FromHex(txReq.Data),
+		})
+	}
+	callData, err := multicall.PackAggregate3(calls)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 路由到目标链并选择 Operator
+	stack, err := s.chains.Get(chainID)
+	if err != nil {
+		return nil, err
+	}
+	operator, err := stack.Selector.Select(ctx, &selector.SelectRequest{BuilderAPIKey: builderAPIKey})
+	if err != nil {
+		return nil, fmt.Errorf("failed to select operator: %w", err)
+	}
+
+	// 3. 创建交易记录（Gas Limit 由估算得出）
+	taskID := uuid.New().String()
+	tx := &data.Transaction{
+		TaskID:          taskID,
+		ChainID:         stack.ChainID,
+		BuilderAPIKey:   builderAPIKey,
+		FromAddress:     operator.Address,
+		ToAddress:       stack.Multicall3,
+		TargetContract:  stack.Multicall3,
+		TransactionType: "MULTICALL",
+		Data:            hexutil.Encode(callData),
+		Value:           "0x0",
+		GasPrice:        "0",
+		Status:          "QUEUED",
+	}
+
+	// 4. 模拟执行并解码各调用结果，整批会回滚时直接拒绝
+	// 模拟因 RPC 错误未能完成时仍然入队（不返回调用结果），worker 广播前会再次模拟
+	results, revertErr := s.simulateAtomicBatch(ctx, stack, tx, operator, calls)
+	if len(results) > 0 {
+		encoded, err := json.Marshal(results)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal call results: %w", err)
+		}
+		tx.SimulatedCallResults = string(encoded)
+	}
+	if revertErr != nil {
+		tx.Status = "FAILED"
+		tx.ErrorMessage = executor.FormatError(executor.CategoryExecutionReverted, revertErr)
+		if err := s.txRepo.Create(ctx, tx); err != nil {
+			return nil, fmt.Errorf("failed to create transaction: %w", err)
+		}
+		return &SubmitBatchTransactionReply{
+			TaskIDs: []string{taskID},
+			TaskID:  taskID,
+			Success: false,
+			Message: tx.ErrorMessage,
+			Results: results,
+		}, nil
+	}
+
+	// 5. 写入目标链的持久化队列
+	if err := stack.Queue.Enqueue(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	return &SubmitBatchTransactionReply{
+		TaskIDs: []string{taskID},
+		TaskID:  taskID,
+		Success: true,
+		Message: fmt.Sprintf("Submitted atomic batch of %d calls", len(calls)),
+		Results: results,
+	}, nil
+}
+
+// simulateAtomicBatch 模拟执行 aggregate3，返回各调用结果
+// 整批回滚（allowFailure 为 false 的调用失败）时允许所有调用失败再模拟一次，定位失败的调用及其回滚原因
+func (s *relayerService) simulateAtomicBatch(
+	ctx context.Context,
+	stack *chain.Stack,
+	tx *data.Transaction,
+	operator *data.Operator,
+	calls []multicall.Call,
+) ([]*CallResult, *executor.RevertError) {
+	returnData, err := stack.Executor.SimulateCall(ctx, tx, operator)
+	if err == nil {
+		results, _ := s.decodeCallResults(stack, returnData)
+		return results, nil
+	}
+	var revertErr *executor.RevertError
+	if !errors.As(err, &revertErr) {
+		return nil, nil
+	}
+
+	probeCalls := make([]multicall.Call, len(calls))
+	for i, call := range calls {
+		probeCalls[i] = call
+		probeCalls[i].AllowFailure = true
+	}
+	probeData, err := multicall.PackAggregate3(probeCalls)
+	if err != nil {
+		return nil, revertErr
+	}
+	probe := *tx
+	probe.Data = hexutil.Encode(probeData)
+	returnData, err = stack.Executor.SimulateCall(ctx, &probe, operator)
+	if err != nil {
+		return nil, revertErr
+	}
+	results, err := s.decodeCallResults(stack, returnData)
+	if err != nil {
+		return nil, revertErr
+	}
+
+	// 回滚原因替换为第一个不允许失败的失败调用的原因
+	for _, result := range results {
+		if !result.Success && !calls[result.Index].AllowFailure {
+			return results, &executor.RevertError{
+				Reason: fmt.Sprintf("call %d: %s", result.Index, result.ErrorMessage),
+				Data:   revertErr.Data,
+			}
+		}
+	}
+	return results, revertErr
+}
+
+// decodeCallResults 解码 aggregate3 返回数据，失败调用的回滚数据解码为回滚原因
+func (s *relayerService) decodeCallResults(stack *chain.Stack, returnData []byte) ([]*CallResult, error) {
+	decoded, err := multicall.UnpackAggregate3(returnData)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*CallResult, len(decoded))
+	for i, r := range decoded {
+		result := &CallResult{
+			Index:      int32(i),
+			Success:    r.Success,
+			ReturnData: hexutil.Encode(r.ReturnData),
+		}
+		if !r.Success {
+			result.ErrorMessage = stack.Executor.DecodeRevert(r.ReturnData)
+			if result.ErrorMessage == "" {
+				result.ErrorMessage = "execution reverted"
+			}
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// unsupportedAtomicField 原子批量子调用中设置了的不支持字段（未设置时返回空）
+func unsupportedAtomicField(txReq *SubmitTransactionRequest) string {
+	switch {
+	case txReq.Signature != "":
+		return "signature"
+	case txReq.Forwarder != "":
+		return "forwarder"
+	case txReq.TransactionType != "" && txReq.TransactionType != "TRANSACTION_TYPE_UNSPECIFIED":
+		return "transaction_type"
+	case txReq.Safe != nil:
+		return "safe_tx"
+	case txReq.Proxy != nil:
+		return "proxy_tx"
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"prediction-relayer-service/internal/chain"
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/wallet"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// ProxyTransaction Proxy Wallet 中继请求（GSN RelayHub relayCall 字段 + owner 签名）
+type ProxyTransaction struct {
+	From       string // Proxy Wallet owner
+	Calls      []*ProxyCall
+	RelayerFee string // 十进制或 0x 十六进制，下同
+	GasPrice   string
+	GasLimit   string
+	Nonce      string
+	Relay      string // 签名绑定的 relay 地址（发送交易的 Operator）
+	Signature  string // owner 对中继请求哈希的 eth_sign 签名（hex）
+}
+
+// ProxyCall Proxy Wallet 调用
+type ProxyCall struct {
+	To    string
+	Value string
+	Data  string
+}
+
+// applyProxyTransaction 校验 owner 签名后将交易改写为经 RelayHub 中继的 ProxyWalletFactory.proxy 调用
+// Factory 以 owner 身份执行调用，owner 的 Proxy Wallet 不存在时先部署
+func (s *relayerService) applyProxyTransaction(ctx context.Context, stack *chain.Stack, tx *data.Transaction, operator *data.Operator, req *ProxyTransaction) error {
+	if stack.Proxy == nil {
+		return errors.BadRequest("PROXY_NOT_SUPPORTED", fmt.Sprintf("proxy wallet relaying is not configured on chain %d", stack.ChainID))
+	}
+	relayReq, signature, err := parseProxyTransaction(req, common.HexToAddress(stack.ProxyFactory), common.HexToAddress(stack.RelayHub))
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(req.Relay, operator.Address) {
+		return errors.BadRequest("INVALID_PROXY_TX", fmt.Sprintf("relay %s is not an available operator", req.Relay))
+	}
+	if err := stack.Proxy.Verify(ctx, relayReq, signature); err != nil {
+		return err
+	}
+
+	callData, err := wallet.PackRelayCall(relayReq, signature)
+	if err != nil {
+		return err
+	}
+	tx.ToAddress = stack.RelayHub
+	tx.TargetContract = stack.ProxyFactory
+	tx.TransactionType = "PROXY_RELAY"
+	tx.Data = hexutil.Encode(callData)
+	tx.Value = "0x0"
+	tx.Signature = hexutil.Encode(signature)
+	return nil
+}
+
+// parseProxyTransaction 解析中继请求字段并编码 proxy(calls) 调用数据（字段无效时返回 400 INVALID_PROXY_TX）
+func parseProxyTransaction(req *ProxyTransaction, factory, relayHub common.Address) (*wallet.RelayRequest, []byte, error) {
+	invalid := func(field string) error {
+		return errors.BadRequest("INVALID_PROXY_TX", fmt.Sprintf("invalid proxy_tx.%s", field))
+	}
+
+	if !common.IsHexAddress(req.From) {
+		return nil, nil, invalid("from")
+	}
+	if !common.IsHexAddress(req.Relay) {
+		return nil, nil, invalid("relay")
+	}
+	if len(req.Calls) == 0 {
+		return nil, nil, invalid("calls")
+	}
+
+	numbers := make(map[string]*big.Int, 4)
+	for field, value := range map[string]string{
+		"relayer_fee": req.RelayerFee,
+		"gas_price":   req.GasPrice,
+		"gas_limit":   req.GasLimit,
+		"nonce":       req.Nonce,
+	} {
+		n, ok := math.ParseBig256(value)
+		if !ok {
+			return nil, nil, invalid(field)
+		}
+		numbers[field] = n
+	}
+
+	calls := make([]wallet.ProxyCall, 0, len(req.Calls))
+	for i, call := range req.Calls {
+		if !common.IsHexAddress(call.To) {
+			return nil, nil, invalid(fmt.Sprintf("calls[%d].to", i))
+		}
+		value, ok := math.ParseBig256(call.Value)
+		if !ok {
+			return nil, nil, invalid(fmt.Sprintf("calls[%d].value", i))
+		}
+		data, err := hexutil.Decode(orEmptyHex(call.Data))
+		if err != nil {
+			return nil, nil, invalid(fmt.Sprintf("calls[%d].data", i))
+		}
+		calls = append(calls, wallet.ProxyCall{
+			TypeCode: wallet.ProxyCallTypeCall,
+			To:       common.HexToAddress(call.To),
+			Value:    value,
+			Data:     data,
+		})
+	}
+	callData, err := wallet.PackProxy(calls)
+	if err != nil {
+		return nil, nil, err
+	}
+	signature, err := hexutil.Decode(orEmptyHex(req.Signature))
+	if err != nil || len(signature) == 0 {
+		return nil, nil, invalid("signature")
+	}
+
+	return &wallet.RelayRequest{
+		From:     common.HexToAddress(req.From),
+		To:       factory,
+		Data:     callData,
+		TxFee:    numbers["relayer_fee"],
+		GasPrice: numbers["gas_price"],
+		GasLimit: numbers["gas_limit"],
+		Nonce:    numbers["nonce"],
+		RelayHub: relayHub,
+		Relay:    common.HexToAddress(req.Relay),
+	}, signature, nil
+}
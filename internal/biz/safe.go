@@ -0,0 +1,194 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"prediction-relayer-service/internal/chain"
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/wallet"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// SafeTransaction Gnosis Safe 交易（EIP-712 SafeTx 字段 + owner 签名）
+type SafeTransaction struct {
+	SafeAddress    string
+	To             string
+	Value          string // 十进制或 0x 十六进制，下同
+	Data           string
+	Operation      uint32
+	SafeTxGas      string
+	BaseGas        string
+	GasPrice       string
+	GasToken       string
+	RefundReceiver string
+	Nonce          string
+	Signatures     string      // 按 owner 地址升序拼接的 65 字节签名（hex）
+	Calls          []*SafeCall // 批量调用：设置时编码为 MultiSendCallOnly.multiSend 的 DELEGATECALL（忽略 To / Data / Operation）
+}
+
+// SafeCall Safe MultiSend 子调用
+type SafeCall struct {
+	To    string
+	Value string
+	Data  string
+}
+
+// applySafeTransaction 按链上 owners 和 threshold 校验 SafeTx 签名，通过后将交易改写为对 Safe 的 execTransaction 调用
+func (s *relayerService) applySafeTransaction(ctx context.Context, stack *chain.Stack, tx *data.Transaction, req *SafeTransaction) error {
+	safe, safeTx, signatures, err := parseSafeTransaction(req, common.HexToAddress(stack.MultiSend))
+	if err != nil {
+		return err
+	}
+	if err := stack.Safe.Verify(ctx, safe, safeTx, signatures); err != nil {
+		return err
+	}
+
+	callData, err := wallet.PackExecTransaction(safeTx, signatures)
+	if err != nil {
+		return err
+	}
+	tx.ToAddress = safe.Hex()
+	tx.TargetContract = safeTx.To.Hex()
+	tx.Data = hexutil.Encode(callData)
+	tx.Value = "0x0"
+	tx.Signature = hexutil.Encode(signatures)
+	return nil
+}
+
+// parseSafeTransaction 解析 Safe 交易字段（字段无效时返回 400 INVALID_SAFE_TX）
+// 设置了批量调用时，SafeTx 为对 multiSend 合约的 DELEGATECALL，调用数据由批量调用按顺序编码
+func parseSafeTransaction(req *SafeTransaction, multiSend common.Address) (common.Address, *wallet.SafeTx, []byte, error) {
+	invalid := func(field string) error {
+		return errors.BadRequest("INVALID_SAFE_TX", fmt.Sprintf("invalid safe_tx.%s", field))
+	}
+
+	if !common.IsHexAddress(req.SafeAddress) {
+		return common.Address{}, nil, nil, invalid("safe_address")
+	}
+	if len(req.Calls) == 0 {
+		if !common.IsHexAddress(req.To) {
+			return common.Address{}, nil, nil, invalid("to")
+		}
+		if req.Operation > uint32(wallet.OperationDelegateCall) {
+			return common.Address{}, nil, nil, invalid("operation")
+		}
+	}
+	optionalAddress := func(s string) (common.Address, bool) {
+		if s == "" {
+			return common.Address{}, true
+		}
+		return common.HexToAddress(s), common.IsHexAddress(s)
+	}
+	gasToken, ok := optionalAddress(req.GasToken)
+	if !ok {
+		return common.Address{}, nil, nil, invalid("gas_token")
+	}
+	refundReceiver, ok := optionalAddress(req.RefundReceiver)
+	if !ok {
+		return common.Address{}, nil, nil, invalid("refund_receiver")
+	}
+
+	numbers := make(map[string]*big.Int, 6)
+	for field, value := range map[string]string{
+		"value":       req.Value,
+		"safe_tx_gas": req.SafeTxGas,
+		"base_gas":    req.BaseGas,
+		"gas_price":   req.GasPrice,
+		"nonce":       req.Nonce,
+	} {
+		n, ok := math.ParseBig256(value)
+		if !ok {
+			return common.Address{}, nil, nil, invalid(field)
+		}
+		numbers[field] = n
+	}
+
+	to, operation := common.HexToAddress(req.To), uint8(req.Operation)
+	var callData []byte
+	if len(req.Calls) > 0 {
+		calls, err := parseSafeCalls(req.Calls)
+		if err != nil {
+			return common.Address{}, nil, nil, err
+		}
+		if callData, err = wallet.PackMultiSend(calls); err != nil {
+			return common.Address{}, nil, nil, err
+		}
+		to, operation = multiSend, wallet.OperationDelegateCall
+	} else {
+		var err error
+		if callData, err = hexutil.Decode(orEmptyHex(req.Data)); err != nil {
+			return common.Address{}, nil, nil, invalid("data")
+		}
+	}
+	signatures, err := hexutil.Decode(orEmptyHex(req.Signatures))
+	if err != nil || len(signatures) == 0 {
+		return common.Address{}, nil, nil, invalid("signatures")
+	}
+
+	return common.HexToAddress(req.SafeAddress), &wallet.SafeTx{
+		To:             to,
+		Value:          numbers["value"],
+		Data:           callData,
+		Operation:      operation,
+		SafeTxGas:      numbers["safe_tx_gas"],
+		BaseGas:        numbers["base_gas"],
+		GasPrice:       numbers["gas_price"],
+		GasToken:       gasToken,
+		RefundReceiver: refundReceiver,
+		Nonce:          numbers["nonce"],
+	}, signatures, nil
+}
+
+// parseSafeCalls 解析 MultiSend 子调用
+func parseSafeCalls(calls []*SafeCall) ([]wallet.MultiSendCall, error) {
+	parsed := make([]wallet.MultiSendCall, 0, len(calls))
+	for i, call := range calls {
+		invalid := func(field string) error {
+			return errors.BadRequest("INVALID_SAFE_TX", fmt.Sprintf("invalid safe_tx.calls[%d].%s", i, field))
+		}
+		if !common.IsHexAddress(call.To) {
+			return nil, invalid("to")
+		}
+		value, ok := math.ParseBig256(call.Value)
+		if !ok {
+			return nil, invalid("value")
+		}
+		data, err := hexutil.Decode(orEmptyHex(call.Data))
+		if err != nil {
+			return nil, invalid("data")
+		}
+		parsed = append(parsed, wallet.MultiSendCall{To: common.HexToAddress(call.To), Value: value, Data: data})
+	}
+	return parsed, nil
+}
+
+// decodeSafeCalls 从交易数据中解码 Safe MultiSend 子调用（不是经 multiSend 执行的 Safe 交易时返回 nil）
+func decodeSafeCalls(txData string, multiSend common.Address) []*SafeCall {
+	calls, ok := wallet.DecodeSafeMultiSend(common.FromHex(txData), multiSend)
+	if !ok {
+		return nil
+	}
+	decoded := make([]*SafeCall, 0, len(calls))
+	for _, call := range calls {
+		decoded = append(decoded, &SafeCall{
+			To:    call.To.Hex(),
+			Value: call.Value.String(),
+			Data:  hexutil.Encode(call.Data),
+		})
+	}
+	return decoded
+}
+
+// orEmptyHex 空字符串按空字节处理（hexutil.Decode 不接受空字符串）
+func orEmptyHex(s string) string {
+	if s == "" {
+		return "0x"
+	}
+	return s
+}
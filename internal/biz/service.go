@@ -4,13 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"strings"
 	"time"
 
 	"prediction-relayer-service/internal/auth"
+	"prediction-relayer-service/internal/chain"
 	"prediction-relayer-service/internal/data"
 	"prediction-relayer-service/internal/executor"
 	"prediction-relayer-service/internal/fee"
+	"prediction-relayer-service/internal/queue"
+	"prediction-relayer-service/internal/selector"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/go-kratos/kratos/v2/errors"
 	"github.com/google/uuid"
 )
 
@@ -37,6 +46,7 @@ type RelayerService interface {
 
 // SubmitTransactionRequest 提交交易请求
 type SubmitTransactionRequest struct {
+	ChainID         int64 // 目标链（0 表示默认链）
 	To              string
 	Data            string
 	Signature       string
@@ -44,6 +54,9 @@ type SubmitTransactionRequest struct {
 	GasLimit        int64
 	TransactionType string
 	Value           string
+	AllowFailure    bool              // 原子批量中允许该调用失败
+	Safe            *SafeTransaction  // Safe 交易（设置时忽略 To / Data / Value）
+	Proxy           *ProxyTransaction // Proxy Wallet 中继请求（设置时忽略 To / Data / Value）
 	AuthRequest     *auth.AuthRequest
 }
 
@@ -58,6 +71,7 @@ type SubmitTransactionReply struct {
 type SubmitBatchTransactionRequest struct {
 	Transactions  []*SubmitTransactionRequest
 	BuilderAPIKey string
+	Atomic        bool // 原子模式：打包为一笔 Multicall3 aggregate3 交易
 }
 
 // SubmitBatchTransactionReply 批量提交交易响应
@@ -65,18 +79,26 @@ type SubmitBatchTransactionReply struct {
 	TaskIDs []string
 	Success bool
 	Message string
+	TaskID  string        // 原子模式下整批交易的任务 ID
+	Results []*CallResult // 原子模式下各调用的模拟执行结果
 }
 
 // TransactionStatus 交易状态
 type TransactionStatus struct {
-	TaskID      string
-	TxHash      string
-	Status      string
-	GasPrice    string
-	BlockNumber int64
-	GasUsed     int64
-	CreatedAt   int64
-	UpdatedAt   int64
+	TaskID               string
+	ChainID              int64
+	TxHash               string
+	Status               string
+	GasPrice             string
+	MaxFeePerGas         string
+	MaxPriorityFeePerGas string
+	BlockNumber          int64
+	GasUsed              int64
+	ErrorMessage         string
+	SimulatedCallResults []*CallResult // 原子批量交易提交时的模拟执行结果（不是链上执行结果）
+	SafeCalls            []*SafeCall   // Safe MultiSend 交易解码后的各调用
+	CreatedAt            int64
+	UpdatedAt            int64
 }
 
 // BuilderFeeStats Builder 费用统计
@@ -98,7 +120,7 @@ type FeeStatsByType struct {
 type relayerService struct {
 	authService auth.AuthService
 	txRepo      data.TransactionRepo
-	executor    executor.Executor
+	chains      chain.Router
 	feeTracker  fee.Tracker
 }
 
@@ -106,13 +128,13 @@ type relayerService struct {
 func NewRelayerService(
 	authService auth.AuthService,
 	txRepo data.TransactionRepo,
-	exec executor.Executor,
+	chains chain.Router,
 	feeTracker fee.Tracker,
 ) RelayerService {
 	return &relayerService{
 		authService: authService,
 		txRepo:      txRepo,
-		executor:    exec,
+		chains:      chains,
 		feeTracker:  feeTracker,
 	}
 }
@@ -125,9 +147,30 @@ func (s *relayerService) SubmitTransaction(ctx context.Context, req *SubmitTrans
 		return nil, fmt.Errorf("authentication failed: %w", err)
 	}
 
-	// 2. 选择 Operator
-	operator, err := s.executor.SelectOperator(ctx)
+	// safe_tx、proxy_tx 和 to / data 只能设置一种，避免一种改写被另一种静默覆盖
+	if err := checkSinglePayload(req); err != nil {
+		return nil, err
+	}
+	value, ok := parseValue(req.Value)
+	if !ok {
+		return nil, errors.BadRequest("INVALID_REQUEST", fmt.Sprintf("invalid value %q", req.Value))
+	}
+
+	// 2. 按 Chain ID 路由到目标链，从该链的 Operator 池中选择 Operator
+	stack, err := s.chains.Get(req.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	// Proxy Wallet 中继请求的签名绑定了 relay 地址，只能由该 Operator 发送（不可用时属于请求错误）
+	selectReq := &selector.SelectRequest{BuilderAPIKey: builder.APIKey}
+	if req.Proxy != nil {
+		selectReq.Operator = req.Proxy.Relay
+	}
+	operator, err := stack.Selector.Select(ctx, selectReq)
 	if err != nil {
+		if req.Proxy != nil && errors.Is(err, selector.ErrNoOperatorAvailable) {
+			return nil, errors.BadRequest("INVALID_PROXY_TX", fmt.Sprintf("relay %s is not an available operator: %v", req.Proxy.Relay, err))
+		}
 		return nil, fmt.Errorf("failed to select operator: %w", err)
 	}
 
@@ -135,40 +178,56 @@ func (s *relayerService) SubmitTransaction(ctx context.Context, req *SubmitTrans
 	taskID := uuid.New().String()
 	tx := &data.Transaction{
 		TaskID:          taskID,
+		ChainID:         stack.ChainID,
 		BuilderAPIKey:   builder.APIKey,
 		FromAddress:     operator.Address,
 		ToAddress:       req.To,
 		TargetContract:  req.To,
 		TransactionType: req.TransactionType,
 		Data:            req.Data,
-		Value:           req.Value,
+		Value:           hexutil.EncodeBig(value),
 		Signature:       req.Signature,
 		Forwarder:       req.Forwarder,
 		GasLimit:        req.GasLimit,
-		GasPrice:        "0", // 将在执行时设置
-		Status:          "PENDING",
+		GasPrice:        "0", // 将在执行时与 nonce、费用参数一起设置
+		Status:          "QUEUED",
 	}
 
-	// 4. 保存交易记录
-	if err := s.txRepo.Create(ctx, tx); err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	// Safe 交易：校验 owner 签名后改写为对 Safe 的 execTransaction 调用
+	if req.Safe != nil {
+		if err := s.applySafeTransaction(ctx, stack, tx, req.Safe); err != nil {
+			return nil, err
+		}
 	}
-
-	// 5. 执行交易（异步）
-	go func() {
-		ctx := context.Background()
-		result, err := s.executor.Execute(ctx, tx, operator)
-		if err != nil {
-			// 更新状态为失败
-			s.txRepo.UpdateStatus(ctx, taskID, "FAILED")
-			return
+	// Proxy Wallet 中继请求：校验 owner 签名后改写为经 RelayHub 中继的 ProxyWalletFactory.proxy 调用
+	if req.Proxy != nil {
+		if err := s.applyProxyTransaction(ctx, stack, tx, operator, req.Proxy); err != nil {
+			return nil, err
 		}
+	}
 
-		// 更新交易哈希
-		if err := s.txRepo.UpdateTxHash(ctx, taskID, result.TxHash); err != nil {
-			// 记录错误但不影响主流程
+	// 4. 以所选 Operator 身份模拟执行，会回滚的交易直接拒绝并记录回滚原因
+	// 模拟因 RPC 错误未能完成时仍然入队，worker 广播前会再次模拟
+	if err := stack.Executor.Simulate(ctx, tx, operator); err != nil {
+		var revertErr *executor.RevertError
+		if errors.As(err, &revertErr) {
+			tx.Status = "FAILED"
+			tx.ErrorMessage = executor.FormatError(executor.CategoryExecutionReverted, revertErr)
+			if err := s.txRepo.Create(ctx, tx); err != nil {
+				return nil, fmt.Errorf("failed to create transaction: %w", err)
+			}
+			return &SubmitTransactionReply{
+				TaskID:  taskID,
+				Success: false,
+				Message: tx.ErrorMessage,
+			}, nil
 		}
-	}()
+	}
+
+	// 5. 写入目标链的持久化队列，由 Operator 的 worker 池异步执行（队列已满时返回 RESOURCE_EXHAUSTED）
+	if err := stack.Queue.Enqueue(ctx, tx); err != nil {
+		return nil, err
+	}
 
 	return &SubmitTransactionReply{
 		TaskID:  taskID,
@@ -177,6 +236,36 @@ func (s *relayerService) SubmitTransaction(ctx context.Context, req *SubmitTrans
 	}, nil
 }
 
+// checkSinglePayload 校验 safe_tx、proxy_tx 和 to / data 最多设置一种（否则返回 400 INVALID_REQUEST）
+func checkSinglePayload(req *SubmitTransactionRequest) error {
+	var set []string
+	if req.Safe != nil {
+		set = append(set, "safe_tx")
+	}
+	if req.Proxy != nil {
+		set = append(set, "proxy_tx")
+	}
+	if req.To != "" || req.Data != "" {
+		set = append(set, "to/data")
+	}
+	if len(set) > 1 {
+		return errors.BadRequest("INVALID_REQUEST", fmt.Sprintf("only one of safe_tx, proxy_tx and to/data may be set, got %s", strings.Join(set, ", ")))
+	}
+	return nil
+}
+
+// parseValue 解析交易金额（十进制或 0x 十六进制，为空时为 0，不能为负数）
+func parseValue(value string) (*big.Int, bool) {
+	if value == "" {
+		return new(big.Int), true
+	}
+	n, ok := math.ParseBig256(value)
+	if !ok || n.Sign() < 0 {
+		return nil, false
+	}
+	return n, true
+}
+
 // SubmitBatchTransaction 提交批量交易
 func (s *relayerService) SubmitBatchTransaction(ctx context.Context, req *SubmitBatchTransactionRequest) (*SubmitBatchTransactionReply, error) {
 	// 1. 验证 Builder 认证（使用第一个交易的认证信息）
@@ -184,16 +273,28 @@ func (s *relayerService) SubmitBatchTransaction(ctx context.Context, req *Submit
 		return nil, fmt.Errorf("no transactions provided")
 	}
 
-	_, err := s.authService.ValidateBuilderAuth(ctx, req.Transactions[0].AuthRequest)
+	builder, err := s.authService.ValidateBuilderAuth(ctx, req.Transactions[0].AuthRequest)
 	if err != nil {
 		return nil, fmt.Errorf("authentication failed: %w", err)
 	}
 
+	// 原子模式：整批打包为一笔 Multicall3 交易
+	if req.Atomic {
+		return s.submitAtomicBatch(ctx, builder.APIKey, req.Transactions)
+	}
+
 	// 2. 批量处理交易
 	taskIDs := make([]string, 0, len(req.Transactions))
 	for _, txReq := range req.Transactions {
 		reply, err := s.SubmitTransaction(ctx, txReq)
 		if err != nil {
+			// 队列已满时后续交易同样会被拒绝（一笔都未提交时直接返回 RESOURCE_EXHAUSTED）
+			if errors.Is(err, queue.ErrQueueFull) {
+				if len(taskIDs) == 0 {
+					return nil, err
+				}
+				break
+			}
 			// 记录错误但继续处理其他交易
 			continue
 		}
@@ -218,12 +319,16 @@ func (s *relayerService) GetTransactionStatus(ctx context.Context, taskID string
 	}
 
 	status := &TransactionStatus{
-		TaskID:    tx.TaskID,
-		TxHash:    tx.TxHash,
-		Status:    tx.Status,
-		GasPrice:  tx.GasPrice,
-		CreatedAt: tx.CreatedAt.Unix(),
-		UpdatedAt: tx.UpdatedAt.Unix(),
+		TaskID:               tx.TaskID,
+		ChainID:              tx.ChainID,
+		TxHash:               tx.TxHash,
+		Status:               tx.Status,
+		GasPrice:             tx.GasPrice,
+		MaxFeePerGas:         tx.MaxFeePerGas,
+		MaxPriorityFeePerGas: tx.MaxPriorityFeePerGas,
+		ErrorMessage:         tx.ErrorMessage,
+		CreatedAt:            tx.CreatedAt.Unix(),
+		UpdatedAt:            tx.UpdatedAt.Unix(),
 	}
 
 	if tx.BlockNumber != nil {
@@ -232,27 +337,40 @@ func (s *relayerService) GetTransactionStatus(ctx context.Context, taskID string
 	if tx.GasUsed != nil {
 		status.GasUsed = *tx.GasUsed
 	}
+	if stack, err := s.chains.Get(tx.ChainID); err == nil {
+		status.SafeCalls = decodeSafeCalls(tx.Data, common.HexToAddress(stack.MultiSend))
+	}
+	if tx.SimulatedCallResults != "" {
+		if err := json.Unmarshal([]byte(tx.SimulatedCallResults), &status.SimulatedCallResults); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal call results: %w", err)
+		}
+	}
 
 	return status, nil
 }
 
 // GetBuilderFeeStats 获取 Builder 费用统计
 func (s *relayerService) GetBuilderFeeStats(ctx context.Context, apiKey string, startTime, endTime time.Time) (*BuilderFeeStats, error) {
-	// 从费用仓库获取统计
-	stats, err := s.txRepo.GetByBuilderAPIKey(ctx, apiKey, startTime, endTime)
+	// 从费用记录获取统计（费用记录在交易上链后根据回执写入）
+	stats, err := s.feeTracker.GetStats(ctx, apiKey, startTime, endTime)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get fee stats: %w", err)
+		return nil, err
 	}
 
 	// 转换为响应格式
 	result := &BuilderFeeStats{
-		TotalTransactions: int64(len(stats)),
-		TotalGasUsed:      "0",
-		TotalCost:         "0",
-		ByType:            make(map[string]*FeeStatsByType),
+		TotalTransactions: stats.TotalTransactions,
+		TotalGasUsed:      stats.TotalGasUsed,
+		TotalCost:         stats.TotalCost,
+		ByType:            make(map[string]*FeeStatsByType, len(stats.ByType)),
+	}
+	for txType, byType := range stats.ByType {
+		result.ByType[txType] = &FeeStatsByType{
+			Count:   byType.Count,
+			GasUsed: byType.GasUsed,
+			Cost:    byType.Cost,
+		}
 	}
-
-	// TODO: 计算总 Gas 和总成本（需要大整数运算）
 
 	return result, nil
 }
@@ -0,0 +1,81 @@
+package chain
+
+import (
+	"fmt"
+
+	"prediction-relayer-service/internal/auditor"
+	"prediction-relayer-service/internal/executor"
+	"prediction-relayer-service/internal/monitor"
+	"prediction-relayer-service/internal/nonce"
+	"prediction-relayer-service/internal/queue"
+	"prediction-relayer-service/internal/selector"
+	"prediction-relayer-service/internal/wallet"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// Stack 单条链的执行组件
+// 每条链独立的 RPC 连接、执行器、Nonce 管理器、Operator 池（operator.chain_id）、提交队列、监控器和 Nonce 审计器
+type Stack struct {
+	ChainID      int64
+	Executor     executor.Executor
+	NonceManager nonce.Manager
+	Selector     selector.OperatorSelector
+	Queue        queue.Queue
+	Monitor      monitor.Monitor
+	Auditor      auditor.NonceAuditor
+	Multicall3   string // Multicall3 合约地址（原子批量提交）
+	Safe         wallet.SafeVerifier
+	MultiSend    string               // Safe MultiSendCallOnly 合约地址（Safe 批量调用）
+	Proxy        wallet.ProxyVerifier // 未配置 ProxyWalletFactory 和 RelayHub 时为 nil
+	ProxyFactory string               // ProxyWalletFactory 合约地址
+	RelayHub     string               // GSN RelayHub 合约地址
+}
+
+// Router 按 Chain ID 路由到对应链的执行组件
+type Router interface {
+	// Get 获取链的执行组件（chainID 为 0 时返回默认链，未配置的链返回 400 UNSUPPORTED_CHAIN）
+	Get(chainID int64) (*Stack, error)
+
+	// All 获取所有链的执行组件（按配置顺序，第一条为默认链）
+	All() []*Stack
+}
+
+// router Router 实现
+type router struct {
+	stacks []*Stack
+	byID   map[int64]*Stack
+}
+
+// NewRouter 创建链路由（第一条链为默认链）
+func NewRouter(stacks ...*Stack) (Router, error) {
+	if len(stacks) == 0 {
+		return nil, fmt.Errorf("at least one chain must be configured")
+	}
+
+	r := &router{stacks: stacks, byID: make(map[int64]*Stack, len(stacks))}
+	for _, s := range stacks {
+		if _, ok := r.byID[s.ChainID]; ok {
+			return nil, fmt.Errorf("duplicate chain id: %d", s.ChainID)
+		}
+		r.byID[s.ChainID] = s
+	}
+	return r, nil
+}
+
+// Get 获取链的执行组件
+func (r *router) Get(chainID int64) (*Stack, error) {
+	if chainID == 0 {
+		return r.stacks[0], nil
+	}
+	s, ok := r.byID[chainID]
+	if !ok {
+		return nil, errors.BadRequest("UNSUPPORTED_CHAIN", fmt.Sprintf("chain %d is not supported", chainID))
+	}
+	return s, nil
+}
+
+// All 获取所有链的执行组件
+func (r *router) All() []*Stack {
+	return r.stacks
+}
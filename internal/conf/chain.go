@@ -0,0 +1,13 @@
+package conf
+
+// ChainList 返回需要中继的链配置（第一条为默认链）
+// 配置了 chains 时使用 chains，否则使用单链配置 chain
+func (b *Bootstrap) ChainList() []*Chain {
+	if len(b.Chains) > 0 {
+		return b.Chains
+	}
+	if b.Chain != nil {
+		return []*Chain{b.Chain}
+	}
+	return nil
+}
@@ -26,10 +26,13 @@ type Bootstrap struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Server        *Server                `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
 	Data          *Data                  `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
-	Chain         *Chain                 `protobuf:"bytes,3,opt,name=chain,proto3" json:"chain,omitempty"`
+	Chain         *Chain                 `protobuf:"bytes,3,opt,name=chain,proto3" json:"chain,omitempty"` // 单链配置（未配置 chains 时使用）
 	Operator      *Operator              `protobuf:"bytes,4,opt,name=operator,proto3" json:"operator,omitempty"`
 	Builder       *Builder               `protobuf:"bytes,5,opt,name=builder,proto3" json:"builder,omitempty"`
 	Security      *Security              `protobuf:"bytes,6,opt,name=security,proto3" json:"security,omitempty"`
+	Queue         *Queue                 `protobuf:"bytes,7,opt,name=queue,proto3" json:"queue,omitempty"`
+	Gas           *Gas                   `protobuf:"bytes,8,opt,name=gas,proto3" json:"gas,omitempty"`
+	Chains        []*Chain               `protobuf:"bytes,9,rep,name=chains,proto3" json:"chains,omitempty"` // 多链配置（第一条为默认链），每条链独立的执行器、Nonce 管理器、Operator 池和监控器
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -106,6 +109,27 @@ func (x *Bootstrap) GetSecurity() *Security {
 	return nil
 }
 
+func (x *Bootstrap) GetQueue() *Queue {
+	if x != nil {
+		return x.Queue
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetGas() *Gas {
+	if x != nil {
+		return x.Gas
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetChains() []*Chain {
+	if x != nil {
+		return x.Chains
+	}
+	return nil
+}
+
 type Server struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Http          *Server_HTTP           `protobuf:"bytes,1,opt,name=http,proto3" json:"http,omitempty"`
@@ -219,13 +243,25 @@ func (x *Data) GetRocketmq() *Data_RocketMQ {
 }
 
 type Chain struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	RpcUrl             string                 `protobuf:"bytes,1,opt,name=rpc_url,json=rpcUrl,proto3" json:"rpc_url,omitempty"`
-	ChainId            string                 `protobuf:"bytes,2,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
-	GasPriceMultiplier int64                  `protobuf:"varint,3,opt,name=gas_price_multiplier,json=gasPriceMultiplier,proto3" json:"gas_price_multiplier,omitempty"` // Percentage multiplier (e.g., 110 = 110%)
-	MaxRetry           int64                  `protobuf:"varint,4,opt,name=max_retry,json=maxRetry,proto3" json:"max_retry,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	RpcUrl                   string                 `protobuf:"bytes,1,opt,name=rpc_url,json=rpcUrl,proto3" json:"rpc_url,omitempty"`
+	ChainId                  string                 `protobuf:"bytes,2,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	GasPriceMultiplier       int64                  `protobuf:"varint,3,opt,name=gas_price_multiplier,json=gasPriceMultiplier,proto3" json:"gas_price_multiplier,omitempty"`                     // Percentage multiplier applied to the EIP-1559 tip (e.g., 110 = 110%)
+	MaxRetry                 int64                  `protobuf:"varint,4,opt,name=max_retry,json=maxRetry,proto3" json:"max_retry,omitempty"`                                                     // 广播 / 估算失败后单个任务的最大重试次数（按错误分类恢复）
+	NonceManager             string                 `protobuf:"bytes,5,opt,name=nonce_manager,json=nonceManager,proto3" json:"nonce_manager,omitempty"`                                          // Nonce 管理器实现：db（默认，单副本）或 redis（多副本部署）
+	ErrorAbiFiles            []string               `protobuf:"bytes,6,rep,name=error_abi_files,json=errorAbiFiles,proto3" json:"error_abi_files,omitempty"`                                     // 合约 ABI 文件（JSON），其中的自定义错误用于解码模拟执行的回滚原因
+	ConfirmationBlocks       int64                  `protobuf:"varint,7,opt,name=confirmation_blocks,json=confirmationBlocks,proto3" json:"confirmation_blocks,omitempty"`                       // 确认深度：打包区块之后再出 N 个区块，交易由 MINED 转为 CONFIRMED（默认 30）
+	WsUrl                    string                 `protobuf:"bytes,8,opt,name=ws_url,json=wsUrl,proto3" json:"ws_url,omitempty"`                                                               // WebSocket RPC 地址（可选）：配置后监控器订阅新区块头实时检查回执，订阅断开时退回轮询
+	Gas                      *Gas                   `protobuf:"bytes,9,opt,name=gas,proto3" json:"gas,omitempty"`                                                                                // 本链的 Gas 预言机配置（可选，未配置时使用顶层 gas）
+	RpcUrls                  []string               `protobuf:"bytes,10,rep,name=rpc_urls,json=rpcUrls,proto3" json:"rpc_urls,omitempty"`                                                        // 备用 RPC 地址：与 rpc_url 组成 RPC 池，按延迟和区块高度滞后健康评分并自动故障转移，广播发往所有健康节点
+	HealthCheckInterval      *durationpb.Duration   `protobuf:"bytes,11,opt,name=health_check_interval,json=healthCheckInterval,proto3" json:"health_check_interval,omitempty"`                  // RPC 池健康检查间隔（默认 5 秒）
+	MaxHeadLag               uint64                 `protobuf:"varint,12,opt,name=max_head_lag,json=maxHeadLag,proto3" json:"max_head_lag,omitempty"`                                            // 区块高度落后最高节点超过 N 个区块的 RPC 节点视为不健康（默认 5）
+	Multicall3Address        string                 `protobuf:"bytes,13,opt,name=multicall3_address,json=multicall3Address,proto3" json:"multicall3_address,omitempty"`                          // Multicall3 合约地址（原子批量提交使用，默认 0xcA11bde05977b3631167028862bE2a173976CA11）
+	MultisendCallOnlyAddress string                 `protobuf:"bytes,14,opt,name=multisend_call_only_address,json=multisendCallOnlyAddress,proto3" json:"multisend_call_only_address,omitempty"` // Safe MultiSendCallOnly 合约地址（Safe 批量调用使用，默认 v1.3.0 标准部署 0x40A2aCCbd92BCA938b02010E17A5b8929b49130D）
+	ProxyFactoryAddress      string                 `protobuf:"bytes,15,opt,name=proxy_factory_address,json=proxyFactoryAddress,proto3" json:"proxy_factory_address,omitempty"`                  // ProxyWalletFactory 合约地址（Proxy Wallet 中继使用，与 relay_hub_address 同时配置后启用）
+	RelayHubAddress          string                 `protobuf:"bytes,16,opt,name=relay_hub_address,json=relayHubAddress,proto3" json:"relay_hub_address,omitempty"`                              // GSN RelayHub 合约地址（Operator 须已在 RelayHub 注册为 relay）
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
 }
 
 func (x *Chain) Reset() {
@@ -286,12 +322,98 @@ func (x *Chain) GetMaxRetry() int64 {
 	return 0
 }
 
+func (x *Chain) GetNonceManager() string {
+	if x != nil {
+		return x.NonceManager
+	}
+	return ""
+}
+
+func (x *Chain) GetErrorAbiFiles() []string {
+	if x != nil {
+		return x.ErrorAbiFiles
+	}
+	return nil
+}
+
+func (x *Chain) GetConfirmationBlocks() int64 {
+	if x != nil {
+		return x.ConfirmationBlocks
+	}
+	return 0
+}
+
+func (x *Chain) GetWsUrl() string {
+	if x != nil {
+		return x.WsUrl
+	}
+	return ""
+}
+
+func (x *Chain) GetGas() *Gas {
+	if x != nil {
+		return x.Gas
+	}
+	return nil
+}
+
+func (x *Chain) GetRpcUrls() []string {
+	if x != nil {
+		return x.RpcUrls
+	}
+	return nil
+}
+
+func (x *Chain) GetHealthCheckInterval() *durationpb.Duration {
+	if x != nil {
+		return x.HealthCheckInterval
+	}
+	return nil
+}
+
+func (x *Chain) GetMaxHeadLag() uint64 {
+	if x != nil {
+		return x.MaxHeadLag
+	}
+	return 0
+}
+
+func (x *Chain) GetMulticall3Address() string {
+	if x != nil {
+		return x.Multicall3Address
+	}
+	return ""
+}
+
+func (x *Chain) GetMultisendCallOnlyAddress() string {
+	if x != nil {
+		return x.MultisendCallOnlyAddress
+	}
+	return ""
+}
+
+func (x *Chain) GetProxyFactoryAddress() string {
+	if x != nil {
+		return x.ProxyFactoryAddress
+	}
+	return ""
+}
+
+func (x *Chain) GetRelayHubAddress() string {
+	if x != nil {
+		return x.RelayHubAddress
+	}
+	return ""
+}
+
 type Operator struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Wallets       []*OperatorWallet      `protobuf:"bytes,1,rep,name=wallets,proto3" json:"wallets,omitempty"`                                     // Operator 钱包池
-	MinBalanceWei int64                  `protobuf:"varint,2,opt,name=min_balance_wei,json=minBalanceWei,proto3" json:"min_balance_wei,omitempty"` // 余额告警阈值
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Wallets           []*OperatorWallet      `protobuf:"bytes,1,rep,name=wallets,proto3" json:"wallets,omitempty"`                                              // Operator 钱包池
+	MinBalanceWei     int64                  `protobuf:"varint,2,opt,name=min_balance_wei,json=minBalanceWei,proto3" json:"min_balance_wei,omitempty"`          // 余额告警阈值
+	SelectionStrategy string                 `protobuf:"bytes,3,opt,name=selection_strategy,json=selectionStrategy,proto3" json:"selection_strategy,omitempty"` // Operator 选择策略（round_robin, least_in_flight, balance_weighted, builder_sticky）
+	BalanceCacheTtl   *durationpb.Duration   `protobuf:"bytes,4,opt,name=balance_cache_ttl,json=balanceCacheTtl,proto3" json:"balance_cache_ttl,omitempty"`     // 选择时 Operator 余额的缓存时间（默认 15 秒）
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *Operator) Reset() {
@@ -338,6 +460,20 @@ func (x *Operator) GetMinBalanceWei() int64 {
 	return 0
 }
 
+func (x *Operator) GetSelectionStrategy() string {
+	if x != nil {
+		return x.SelectionStrategy
+	}
+	return ""
+}
+
+func (x *Operator) GetBalanceCacheTtl() *durationpb.Duration {
+	if x != nil {
+		return x.BalanceCacheTtl
+	}
+	return nil
+}
+
 type OperatorWallet struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
@@ -451,13 +587,16 @@ func (x *Builder) GetEnableAuth() bool {
 }
 
 type Security struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	ContractWhitelist  []string               `protobuf:"bytes,1,rep,name=contract_whitelist,json=contractWhitelist,proto3" json:"contract_whitelist,omitempty"`         // 合约地址白名单
-	RateLimitPerMinute int64                  `protobuf:"varint,2,opt,name=rate_limit_per_minute,json=rateLimitPerMinute,proto3" json:"rate_limit_per_minute,omitempty"` // 每个 Builder 的速率限制（每分钟）
-	KmsType            string                 `protobuf:"bytes,3,opt,name=kms_type,json=kmsType,proto3" json:"kms_type,omitempty"`                                       // KMS 类型（aws-kms, vault, local）
-	KmsConfig          string                 `protobuf:"bytes,4,opt,name=kms_config,json=kmsConfig,proto3" json:"kms_config,omitempty"`                                 // KMS 配置（JSON 字符串）
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	ContractWhitelist   []string               `protobuf:"bytes,1,rep,name=contract_whitelist,json=contractWhitelist,proto3" json:"contract_whitelist,omitempty"`            // 合约地址白名单
+	RateLimitPerMinute  int64                  `protobuf:"varint,2,opt,name=rate_limit_per_minute,json=rateLimitPerMinute,proto3" json:"rate_limit_per_minute,omitempty"`    // 每个 Builder 的速率限制（每分钟）
+	KmsType             string                 `protobuf:"bytes,3,opt,name=kms_type,json=kmsType,proto3" json:"kms_type,omitempty"`                                          // KMS 类型（aws-kms, vault, local）
+	KmsConfig           string                 `protobuf:"bytes,4,opt,name=kms_config,json=kmsConfig,proto3" json:"kms_config,omitempty"`                                    // KMS 配置（JSON 字符串；local 类型支持多密钥 {"active_key_id","keys","legacy_key_id"}）
+	KeyCacheTtl         *durationpb.Duration   `protobuf:"bytes,5,opt,name=key_cache_ttl,json=keyCacheTtl,proto3" json:"key_cache_ttl,omitempty"`                            // 解密后 Operator 私钥的内存缓存时间（默认 5 分钟，0s 表示使用默认值）
+	Signer              *Signer                `protobuf:"bytes,6,opt,name=signer,proto3" json:"signer,omitempty"`                                                           // 交易签名器配置
+	RotateKeysOnStartup bool                   `protobuf:"varint,7,opt,name=rotate_keys_on_startup,json=rotateKeysOnStartup,proto3" json:"rotate_keys_on_startup,omitempty"` // 启动时将 Operator 私钥和 Builder 凭证重新加密到激活密钥下
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
 func (x *Security) Reset() {
@@ -518,6 +657,247 @@ func (x *Security) GetKmsConfig() string {
 	return ""
 }
 
+func (x *Security) GetKeyCacheTtl() *durationpb.Duration {
+	if x != nil {
+		return x.KeyCacheTtl
+	}
+	return nil
+}
+
+func (x *Security) GetSigner() *Signer {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+func (x *Security) GetRotateKeysOnStartup() bool {
+	if x != nil {
+		return x.RotateKeysOnStartup
+	}
+	return false
+}
+
+type Signer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`                                                                                 // 签名器类型（local: KMS 解密本地私钥, remote: 外部签名服务, aws-kms: KMS 托管密钥签名）
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`                                                                                   // 外部签名服务 JSON-RPC 地址（Clef / Web3Signer）
+	Headers       map[string]string      `protobuf:"bytes,3,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 外部签名服务附加 HTTP 头（例如鉴权 Token）
+	Timeout       *durationpb.Duration   `protobuf:"bytes,4,opt,name=timeout,proto3" json:"timeout,omitempty"`                                                                           // 外部签名服务请求超时
+	KmsConfig     string                 `protobuf:"bytes,5,opt,name=kms_config,json=kmsConfig,proto3" json:"kms_config,omitempty"`                                                      // aws-kms 类型的 AWS KMS 配置（JSON，为空时使用 security.kms_config）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Signer) Reset() {
+	*x = Signer{}
+	mi := &file_config_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Signer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Signer) ProtoMessage() {}
+
+func (x *Signer) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Signer.ProtoReflect.Descriptor instead.
+func (*Signer) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Signer) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Signer) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Signer) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *Signer) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Signer) GetKmsConfig() string {
+	if x != nil {
+		return x.KmsConfig
+	}
+	return ""
+}
+
+type Queue struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	WorkersPerOperator int32                  `protobuf:"varint,1,opt,name=workers_per_operator,json=workersPerOperator,proto3" json:"workers_per_operator,omitempty"` // 每个 Operator 的并发 worker 数（默认 4）
+	MaxPending         int64                  `protobuf:"varint,2,opt,name=max_pending,json=maxPending,proto3" json:"max_pending,omitempty"`                           // 排队中（QUEUED/PROCESSING）任务上限，超出时拒绝提交（默认 10000）
+	PollInterval       *durationpb.Duration   `protobuf:"bytes,3,opt,name=poll_interval,json=pollInterval,proto3" json:"poll_interval,omitempty"`                      // 空闲 worker 轮询间隔（默认 1 秒）
+	ProcessingLease    *durationpb.Duration   `protobuf:"bytes,4,opt,name=processing_lease,json=processingLease,proto3" json:"processing_lease,omitempty"`             // PROCESSING 任务租约，超时未广播的任务重新入队（默认 5 分钟）
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Queue) Reset() {
+	*x = Queue{}
+	mi := &file_config_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Queue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Queue) ProtoMessage() {}
+
+func (x *Queue) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Queue.ProtoReflect.Descriptor instead.
+func (*Queue) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Queue) GetWorkersPerOperator() int32 {
+	if x != nil {
+		return x.WorkersPerOperator
+	}
+	return 0
+}
+
+func (x *Queue) GetMaxPending() int64 {
+	if x != nil {
+		return x.MaxPending
+	}
+	return 0
+}
+
+func (x *Queue) GetPollInterval() *durationpb.Duration {
+	if x != nil {
+		return x.PollInterval
+	}
+	return nil
+}
+
+func (x *Queue) GetProcessingLease() *durationpb.Duration {
+	if x != nil {
+		return x.ProcessingLease
+	}
+	return nil
+}
+
+type Gas struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	HistoryBlocks      uint64                 `protobuf:"varint,1,opt,name=history_blocks,json=historyBlocks,proto3" json:"history_blocks,omitempty"`                                                                    // eth_feeHistory 采样区块数（默认 20）
+	RewardPercentiles  []float64              `protobuf:"fixed64,2,rep,packed,name=reward_percentiles,json=rewardPercentiles,proto3" json:"reward_percentiles,omitempty"`                                                // slow / standard / fast 档位的小费百分位（默认 [10, 50, 90]）
+	CacheTtl           *durationpb.Duration   `protobuf:"bytes,3,opt,name=cache_ttl,json=cacheTtl,proto3" json:"cache_ttl,omitempty"`                                                                                    // 费用建议缓存时间（默认 3 秒）
+	MinPriorityFeeGwei int64                  `protobuf:"varint,4,opt,name=min_priority_fee_gwei,json=minPriorityFeeGwei,proto3" json:"min_priority_fee_gwei,omitempty"`                                                 // 小费下限（gwei，Polygon 节点拒绝低于 25 gwei 的小费）
+	MaxFeeGwei         map[string]int64       `protobuf:"bytes,5,rep,name=max_fee_gwei,json=maxFeeGwei,proto3" json:"max_fee_gwei,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // 按交易类型的 maxFeePerGas 上限（gwei），key 为 TransactionType，"*" 为默认上限
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Gas) Reset() {
+	*x = Gas{}
+	mi := &file_config_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Gas) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Gas) ProtoMessage() {}
+
+func (x *Gas) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Gas.ProtoReflect.Descriptor instead.
+func (*Gas) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Gas) GetHistoryBlocks() uint64 {
+	if x != nil {
+		return x.HistoryBlocks
+	}
+	return 0
+}
+
+func (x *Gas) GetRewardPercentiles() []float64 {
+	if x != nil {
+		return x.RewardPercentiles
+	}
+	return nil
+}
+
+func (x *Gas) GetCacheTtl() *durationpb.Duration {
+	if x != nil {
+		return x.CacheTtl
+	}
+	return nil
+}
+
+func (x *Gas) GetMinPriorityFeeGwei() int64 {
+	if x != nil {
+		return x.MinPriorityFeeGwei
+	}
+	return 0
+}
+
+func (x *Gas) GetMaxFeeGwei() map[string]int64 {
+	if x != nil {
+		return x.MaxFeeGwei
+	}
+	return nil
+}
+
 type Server_HTTP struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
@@ -529,7 +909,7 @@ type Server_HTTP struct {
 
 func (x *Server_HTTP) Reset() {
 	*x = Server_HTTP{}
-	mi := &file_config_proto_msgTypes[8]
+	mi := &file_config_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -541,7 +921,7 @@ func (x *Server_HTTP) String() string {
 func (*Server_HTTP) ProtoMessage() {}
 
 func (x *Server_HTTP) ProtoReflect() protoreflect.Message {
-	mi := &file_config_proto_msgTypes[8]
+	mi := &file_config_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -589,7 +969,7 @@ type Server_GRPC struct {
 
 func (x *Server_GRPC) Reset() {
 	*x = Server_GRPC{}
-	mi := &file_config_proto_msgTypes[9]
+	mi := &file_config_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -601,7 +981,7 @@ func (x *Server_GRPC) String() string {
 func (*Server_GRPC) ProtoMessage() {}
 
 func (x *Server_GRPC) ProtoReflect() protoreflect.Message {
-	mi := &file_config_proto_msgTypes[9]
+	mi := &file_config_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -648,7 +1028,7 @@ type Data_Database struct {
 
 func (x *Data_Database) Reset() {
 	*x = Data_Database{}
-	mi := &file_config_proto_msgTypes[10]
+	mi := &file_config_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -660,7 +1040,7 @@ func (x *Data_Database) String() string {
 func (*Data_Database) ProtoMessage() {}
 
 func (x *Data_Database) ProtoReflect() protoreflect.Message {
-	mi := &file_config_proto_msgTypes[10]
+	mi := &file_config_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -702,7 +1082,7 @@ type Data_Redis struct {
 
 func (x *Data_Redis) Reset() {
 	*x = Data_Redis{}
-	mi := &file_config_proto_msgTypes[11]
+	mi := &file_config_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -714,7 +1094,7 @@ func (x *Data_Redis) String() string {
 func (*Data_Redis) ProtoMessage() {}
 
 func (x *Data_Redis) ProtoReflect() protoreflect.Message {
-	mi := &file_config_proto_msgTypes[11]
+	mi := &file_config_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -770,7 +1150,7 @@ type Data_RocketMQ struct {
 
 func (x *Data_RocketMQ) Reset() {
 	*x = Data_RocketMQ{}
-	mi := &file_config_proto_msgTypes[12]
+	mi := &file_config_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -782,7 +1162,7 @@ func (x *Data_RocketMQ) String() string {
 func (*Data_RocketMQ) ProtoMessage() {}
 
 func (x *Data_RocketMQ) ProtoReflect() protoreflect.Message {
-	mi := &file_config_proto_msgTypes[12]
+	mi := &file_config_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -831,14 +1211,17 @@ var File_config_proto protoreflect.FileDescriptor
 const file_config_proto_rawDesc = "" +
 	"\n" +
 	"\fconfig.proto\x12\n" +
-	"kratos.api\x1a\x1egoogle/protobuf/duration.proto\"\x99\x02\n" +
+	"kratos.api\x1a\x1egoogle/protobuf/duration.proto\"\x90\x03\n" +
 	"\tBootstrap\x12*\n" +
 	"\x06server\x18\x01 \x01(\v2\x12.kratos.api.ServerR\x06server\x12$\n" +
 	"\x04data\x18\x02 \x01(\v2\x10.kratos.api.DataR\x04data\x12'\n" +
 	"\x05chain\x18\x03 \x01(\v2\x11.kratos.api.ChainR\x05chain\x120\n" +
 	"\boperator\x18\x04 \x01(\v2\x14.kratos.api.OperatorR\boperator\x12-\n" +
 	"\abuilder\x18\x05 \x01(\v2\x13.kratos.api.BuilderR\abuilder\x120\n" +
-	"\bsecurity\x18\x06 \x01(\v2\x14.kratos.api.SecurityR\bsecurity\"\xb8\x02\n" +
+	"\bsecurity\x18\x06 \x01(\v2\x14.kratos.api.SecurityR\bsecurity\x12'\n" +
+	"\x05queue\x18\a \x01(\v2\x11.kratos.api.QueueR\x05queue\x12!\n" +
+	"\x03gas\x18\b \x01(\v2\x0f.kratos.api.GasR\x03gas\x12)\n" +
+	"\x06chains\x18\t \x03(\v2\x11.kratos.api.ChainR\x06chains\"\xb8\x02\n" +
 	"\x06Server\x12+\n" +
 	"\x04http\x18\x01 \x01(\v2\x17.kratos.api.Server.HTTPR\x04http\x12+\n" +
 	"\x04grpc\x18\x02 \x01(\v2\x17.kratos.api.Server.GRPCR\x04grpc\x1ai\n" +
@@ -868,15 +1251,31 @@ const file_config_proto_rawDesc = "" +
 	"\x0eproducer_group\x18\x02 \x01(\tR\rproducerGroup\x12\x14\n" +
 	"\x05topic\x18\x03 \x01(\tR\x05topic\x12\x1f\n" +
 	"\vretry_times\x18\x04 \x01(\x05R\n" +
-	"retryTimes\"\x8a\x01\n" +
+	"retryTimes\"\x9c\x05\n" +
 	"\x05Chain\x12\x17\n" +
 	"\arpc_url\x18\x01 \x01(\tR\x06rpcUrl\x12\x19\n" +
 	"\bchain_id\x18\x02 \x01(\tR\achainId\x120\n" +
 	"\x14gas_price_multiplier\x18\x03 \x01(\x03R\x12gasPriceMultiplier\x12\x1b\n" +
-	"\tmax_retry\x18\x04 \x01(\x03R\bmaxRetry\"h\n" +
+	"\tmax_retry\x18\x04 \x01(\x03R\bmaxRetry\x12#\n" +
+	"\rnonce_manager\x18\x05 \x01(\tR\fnonceManager\x12&\n" +
+	"\x0ferror_abi_files\x18\x06 \x03(\tR\rerrorAbiFiles\x12/\n" +
+	"\x13confirmation_blocks\x18\a \x01(\x03R\x12confirmationBlocks\x12\x15\n" +
+	"\x06ws_url\x18\b \x01(\tR\x05wsUrl\x12!\n" +
+	"\x03gas\x18\t \x01(\v2\x0f.kratos.api.GasR\x03gas\x12\x19\n" +
+	"\brpc_urls\x18\n" +
+	" \x03(\tR\arpcUrls\x12M\n" +
+	"\x15health_check_interval\x18\v \x01(\v2\x19.google.protobuf.DurationR\x13healthCheckInterval\x12 \n" +
+	"\fmax_head_lag\x18\f \x01(\x04R\n" +
+	"maxHeadLag\x12-\n" +
+	"\x12multicall3_address\x18\r \x01(\tR\x11multicall3Address\x12=\n" +
+	"\x1bmultisend_call_only_address\x18\x0e \x01(\tR\x18multisendCallOnlyAddress\x122\n" +
+	"\x15proxy_factory_address\x18\x0f \x01(\tR\x13proxyFactoryAddress\x12*\n" +
+	"\x11relay_hub_address\x18\x10 \x01(\tR\x0frelayHubAddress\"\xde\x01\n" +
 	"\bOperator\x124\n" +
 	"\awallets\x18\x01 \x03(\v2\x1a.kratos.api.OperatorWalletR\awallets\x12&\n" +
-	"\x0fmin_balance_wei\x18\x02 \x01(\x03R\rminBalanceWei\"c\n" +
+	"\x0fmin_balance_wei\x18\x02 \x01(\x03R\rminBalanceWei\x12-\n" +
+	"\x12selection_strategy\x18\x03 \x01(\tR\x11selectionStrategy\x12E\n" +
+	"\x11balance_cache_ttl\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x0fbalanceCacheTtl\"c\n" +
 	"\x0eOperatorWallet\x12\x18\n" +
 	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x1f\n" +
 	"\vprivate_key\x18\x02 \x01(\tR\n" +
@@ -885,13 +1284,42 @@ const file_config_proto_rawDesc = "" +
 	"\aBuilder\x12.\n" +
 	"\x13timestamp_window_ms\x18\x01 \x01(\x03R\x11timestampWindowMs\x12\x1f\n" +
 	"\venable_auth\x18\x02 \x01(\bR\n" +
-	"enableAuth\"\xa6\x01\n" +
+	"enableAuth\"\xc6\x02\n" +
 	"\bSecurity\x12-\n" +
 	"\x12contract_whitelist\x18\x01 \x03(\tR\x11contractWhitelist\x121\n" +
 	"\x15rate_limit_per_minute\x18\x02 \x01(\x03R\x12rateLimitPerMinute\x12\x19\n" +
 	"\bkms_type\x18\x03 \x01(\tR\akmsType\x12\x1d\n" +
 	"\n" +
-	"kms_config\x18\x04 \x01(\tR\tkmsConfigB/Z-prediction-relayer-service/internal/conf;confb\x06proto3"
+	"kms_config\x18\x04 \x01(\tR\tkmsConfig\x12=\n" +
+	"\rkey_cache_ttl\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\vkeyCacheTtl\x12*\n" +
+	"\x06signer\x18\x06 \x01(\v2\x12.kratos.api.SignerR\x06signer\x123\n" +
+	"\x16rotate_keys_on_startup\x18\a \x01(\bR\x13rotateKeysOnStartup\"\xf9\x01\n" +
+	"\x06Signer\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x129\n" +
+	"\aheaders\x18\x03 \x03(\v2\x1f.kratos.api.Signer.HeadersEntryR\aheaders\x123\n" +
+	"\atimeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12\x1d\n" +
+	"\n" +
+	"kms_config\x18\x05 \x01(\tR\tkmsConfig\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe0\x01\n" +
+	"\x05Queue\x120\n" +
+	"\x14workers_per_operator\x18\x01 \x01(\x05R\x12workersPerOperator\x12\x1f\n" +
+	"\vmax_pending\x18\x02 \x01(\x03R\n" +
+	"maxPending\x12>\n" +
+	"\rpoll_interval\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\fpollInterval\x12D\n" +
+	"\x10processing_lease\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x0fprocessingLease\"\xc8\x02\n" +
+	"\x03Gas\x12%\n" +
+	"\x0ehistory_blocks\x18\x01 \x01(\x04R\rhistoryBlocks\x12-\n" +
+	"\x12reward_percentiles\x18\x02 \x03(\x01R\x11rewardPercentiles\x126\n" +
+	"\tcache_ttl\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\bcacheTtl\x121\n" +
+	"\x15min_priority_fee_gwei\x18\x04 \x01(\x03R\x12minPriorityFeeGwei\x12A\n" +
+	"\fmax_fee_gwei\x18\x05 \x03(\v2\x1f.kratos.api.Gas.MaxFeeGweiEntryR\n" +
+	"maxFeeGwei\x1a=\n" +
+	"\x0fMaxFeeGweiEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01B/Z-prediction-relayer-service/internal/conf;confb\x06proto3"
 
 var (
 	file_config_proto_rawDescOnce sync.Once
@@ -905,7 +1333,7 @@ func file_config_proto_rawDescGZIP() []byte {
 	return file_config_proto_rawDescData
 }
 
-var file_config_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_config_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_config_proto_goTypes = []any{
 	(*Bootstrap)(nil),           // 0: kratos.api.Bootstrap
 	(*Server)(nil),              // 1: kratos.api.Server
@@ -915,12 +1343,17 @@ var file_config_proto_goTypes = []any{
 	(*OperatorWallet)(nil),      // 5: kratos.api.OperatorWallet
 	(*Builder)(nil),             // 6: kratos.api.Builder
 	(*Security)(nil),            // 7: kratos.api.Security
-	(*Server_HTTP)(nil),         // 8: kratos.api.Server.HTTP
-	(*Server_GRPC)(nil),         // 9: kratos.api.Server.GRPC
-	(*Data_Database)(nil),       // 10: kratos.api.Data.Database
-	(*Data_Redis)(nil),          // 11: kratos.api.Data.Redis
-	(*Data_RocketMQ)(nil),       // 12: kratos.api.Data.RocketMQ
-	(*durationpb.Duration)(nil), // 13: google.protobuf.Duration
+	(*Signer)(nil),              // 8: kratos.api.Signer
+	(*Queue)(nil),               // 9: kratos.api.Queue
+	(*Gas)(nil),                 // 10: kratos.api.Gas
+	(*Server_HTTP)(nil),         // 11: kratos.api.Server.HTTP
+	(*Server_GRPC)(nil),         // 12: kratos.api.Server.GRPC
+	(*Data_Database)(nil),       // 13: kratos.api.Data.Database
+	(*Data_Redis)(nil),          // 14: kratos.api.Data.Redis
+	(*Data_RocketMQ)(nil),       // 15: kratos.api.Data.RocketMQ
+	nil,                         // 16: kratos.api.Signer.HeadersEntry
+	nil,                         // 17: kratos.api.Gas.MaxFeeGweiEntry
+	(*durationpb.Duration)(nil), // 18: google.protobuf.Duration
 }
 var file_config_proto_depIdxs = []int32{
 	1,  // 0: kratos.api.Bootstrap.server:type_name -> kratos.api.Server
@@ -929,21 +1362,35 @@ var file_config_proto_depIdxs = []int32{
 	4,  // 3: kratos.api.Bootstrap.operator:type_name -> kratos.api.Operator
 	6,  // 4: kratos.api.Bootstrap.builder:type_name -> kratos.api.Builder
 	7,  // 5: kratos.api.Bootstrap.security:type_name -> kratos.api.Security
-	8,  // 6: kratos.api.Server.http:type_name -> kratos.api.Server.HTTP
-	9,  // 7: kratos.api.Server.grpc:type_name -> kratos.api.Server.GRPC
-	10, // 8: kratos.api.Data.database:type_name -> kratos.api.Data.Database
-	11, // 9: kratos.api.Data.redis:type_name -> kratos.api.Data.Redis
-	12, // 10: kratos.api.Data.rocketmq:type_name -> kratos.api.Data.RocketMQ
-	5,  // 11: kratos.api.Operator.wallets:type_name -> kratos.api.OperatorWallet
-	13, // 12: kratos.api.Server.HTTP.timeout:type_name -> google.protobuf.Duration
-	13, // 13: kratos.api.Server.GRPC.timeout:type_name -> google.protobuf.Duration
-	13, // 14: kratos.api.Data.Redis.read_timeout:type_name -> google.protobuf.Duration
-	13, // 15: kratos.api.Data.Redis.write_timeout:type_name -> google.protobuf.Duration
-	16, // [16:16] is the sub-list for method output_type
-	16, // [16:16] is the sub-list for method input_type
-	16, // [16:16] is the sub-list for extension type_name
-	16, // [16:16] is the sub-list for extension extendee
-	0,  // [0:16] is the sub-list for field type_name
+	9,  // 6: kratos.api.Bootstrap.queue:type_name -> kratos.api.Queue
+	10, // 7: kratos.api.Bootstrap.gas:type_name -> kratos.api.Gas
+	3,  // 8: kratos.api.Bootstrap.chains:type_name -> kratos.api.Chain
+	11, // 9: kratos.api.Server.http:type_name -> kratos.api.Server.HTTP
+	12, // 10: kratos.api.Server.grpc:type_name -> kratos.api.Server.GRPC
+	13, // 11: kratos.api.Data.database:type_name -> kratos.api.Data.Database
+	14, // 12: kratos.api.Data.redis:type_name -> kratos.api.Data.Redis
+	15, // 13: kratos.api.Data.rocketmq:type_name -> kratos.api.Data.RocketMQ
+	10, // 14: kratos.api.Chain.gas:type_name -> kratos.api.Gas
+	18, // 15: kratos.api.Chain.health_check_interval:type_name -> google.protobuf.Duration
+	5,  // 16: kratos.api.Operator.wallets:type_name -> kratos.api.OperatorWallet
+	18, // 17: kratos.api.Operator.balance_cache_ttl:type_name -> google.protobuf.Duration
+	18, // 18: kratos.api.Security.key_cache_ttl:type_name -> google.protobuf.Duration
+	8,  // 19: kratos.api.Security.signer:type_name -> kratos.api.Signer
+	16, // 20: kratos.api.Signer.headers:type_name -> kratos.api.Signer.HeadersEntry
+	18, // 21: kratos.api.Signer.timeout:type_name -> google.protobuf.Duration
+	18, // 22: kratos.api.Queue.poll_interval:type_name -> google.protobuf.Duration
+	18, // 23: kratos.api.Queue.processing_lease:type_name -> google.protobuf.Duration
+	18, // 24: kratos.api.Gas.cache_ttl:type_name -> google.protobuf.Duration
+	17, // 25: kratos.api.Gas.max_fee_gwei:type_name -> kratos.api.Gas.MaxFeeGweiEntry
+	18, // 26: kratos.api.Server.HTTP.timeout:type_name -> google.protobuf.Duration
+	18, // 27: kratos.api.Server.GRPC.timeout:type_name -> google.protobuf.Duration
+	18, // 28: kratos.api.Data.Redis.read_timeout:type_name -> google.protobuf.Duration
+	18, // 29: kratos.api.Data.Redis.write_timeout:type_name -> google.protobuf.Duration
+	30, // [30:30] is the sub-list for method output_type
+	30, // [30:30] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_config_proto_init() }
@@ -957,7 +1404,7 @@ func file_config_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_config_proto_rawDesc), len(file_config_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -1,6 +1,9 @@
 package conf
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+)
 
 // Validate validates the configuration
 func (b *Bootstrap) Validate() error {
@@ -19,6 +22,24 @@ func (b *Bootstrap) Validate() error {
 	if b.Data.Database == nil || b.Data.Database.Source == "" {
 		return fmt.Errorf("data.database.source is required")
 	}
+	chains := b.ChainList()
+	if len(chains) == 0 {
+		return fmt.Errorf("chain or chains configuration is required")
+	}
+	seen := make(map[int64]bool, len(chains))
+	for i, c := range chains {
+		chainID, err := strconv.ParseInt(c.ChainId, 10, 64)
+		if err != nil || chainID <= 0 {
+			return fmt.Errorf("chains[%d].chain_id is invalid: %q", i, c.ChainId)
+		}
+		if seen[chainID] {
+			return fmt.Errorf("chains[%d].chain_id is duplicated: %d", i, chainID)
+		}
+		seen[chainID] = true
+		if c.RpcUrl == "" {
+			return fmt.Errorf("chains[%d].rpc_url is required", i)
+		}
+	}
 	return nil
 }
 
@@ -6,27 +6,35 @@ import (
 
 // Transaction 交易记录
 type Transaction struct {
-	ID              uint64    `gorm:"primaryKey;autoIncrement"`                                     // 主键 ID
-	TaskID          string    `gorm:"type:varchar(36);uniqueIndex;not null"`                        // 任务 ID（UUID）
-	TxHash          string    `gorm:"type:varchar(66);uniqueIndex"`                                 // 交易哈希（0x 开头的 66 字符）
-	BuilderAPIKey   string    `gorm:"type:varchar(255);not null;index:idx_builder_api_key"`         // Builder API Key（用于费用追踪）
-	FromAddress     string    `gorm:"type:varchar(42);not null;index:idx_from_address"`             // 发送方地址（Operator 地址）
-	ToAddress       string    `gorm:"type:varchar(42);not null"`                                    // 接收方地址（目标合约或转发器）
-	TargetContract  string    `gorm:"type:varchar(42);not null"`                                    // 目标合约地址
-	TransactionType string    `gorm:"type:varchar(50);not null"`                                    // 交易类型（WALLET_DEPLOYMENT, TOKEN_APPROVAL, CTF_SPLIT 等）
-	Data            string    `gorm:"type:text;not null"`                                           // 交易数据（hex 编码的函数调用数据）
-	Value           string    `gorm:"type:varchar(78);not null;default:'0x0'"`                      // 交易金额（hex 格式，通常为 "0x0"）
-	Signature       string    `gorm:"type:text"`                                                    // 用户签名（消息签名，不是交易签名）
-	Forwarder       string    `gorm:"type:varchar(42)"`                                             // 转发器合约地址（可选）
-	Nonce           int64     `gorm:"type:bigint;not null"`                                         // 交易 nonce
-	GasLimit        int64     `gorm:"type:bigint;not null"`                                         // Gas 限制
-	GasPrice        string    `gorm:"type:varchar(78);not null"`                                    // Gas 价格（字符串，支持大整数）
-	Status          string    `gorm:"type:varchar(20);not null;default:'PENDING';index:idx_status"` // 交易状态（PENDING, MINED, FAILED, REPLACED）
-	BlockNumber     *int64    `gorm:"type:bigint"`                                                  // 区块号（交易被打包后才有值）
-	GasUsed         *int64    `gorm:"type:bigint"`                                                  // 实际使用的 Gas（交易被打包后才有值）
-	ErrorMessage    string    `gorm:"type:text"`                                                    // 错误信息（交易失败时记录）
-	CreatedAt       time.Time `gorm:"autoCreateTime;index:idx_created_at"`                          // 创建时间
-	UpdatedAt       time.Time `gorm:"autoUpdateTime;index:idx_status_created_at,priority:2"`        // 更新时间
+	ID                   uint64     `gorm:"primaryKey;autoIncrement"`                                    // 主键 ID
+	TaskID               string     `gorm:"type:varchar(36);uniqueIndex;not null"`                       // 任务 ID（UUID）
+	ChainID              int64      `gorm:"type:bigint;not null;default:137;index:idx_chain_id"`         // 链 ID（137 Polygon、80002 Amoy 等）
+	TxHash               string     `gorm:"type:varchar(66);uniqueIndex;default:null"`                   // 交易哈希（0x 开头的 66 字符，广播前为 NULL）
+	BuilderAPIKey        string     `gorm:"type:varchar(255);not null;index:idx_builder_api_key"`        // Builder API Key（用于费用追踪）
+	FromAddress          string     `gorm:"type:varchar(42);not null;index:idx_from_address"`            // 发送方地址（Operator 地址）
+	ToAddress            string     `gorm:"type:varchar(42);not null"`                                   // 接收方地址（目标合约或转发器）
+	TargetContract       string     `gorm:"type:varchar(42);not null"`                                   // 目标合约地址
+	TransactionType      string     `gorm:"type:varchar(50);not null"`                                   // 交易类型（WALLET_DEPLOYMENT, TOKEN_APPROVAL, CTF_SPLIT 等）
+	Data                 string     `gorm:"type:text;not null"`                                          // 交易数据（hex 编码的函数调用数据）
+	Value                string     `gorm:"type:varchar(78);not null;default:'0x0'"`                     // 交易金额（hex 格式，通常为 "0x0"）
+	Signature            string     `gorm:"type:text"`                                                   // 用户签名（消息签名，不是交易签名）
+	Forwarder            string     `gorm:"type:varchar(42)"`                                            // 转发器合约地址（可选）
+	Nonce                int64      `gorm:"type:bigint;not null"`                                        // 交易 nonce
+	GasLimit             int64      `gorm:"type:bigint;not null"`                                        // Gas 限制
+	GasPrice             string     `gorm:"type:varchar(78);not null"`                                   // Gas 价格（字符串，支持大整数）
+	MaxFeePerGas         string     `gorm:"type:varchar(78)"`                                            // EIP-1559 maxFeePerGas（wei，字符串）
+	MaxPriorityFeePerGas string     `gorm:"type:varchar(78)"`                                            // EIP-1559 maxPriorityFeePerGas（wei，字符串）
+	Status               string     `gorm:"type:varchar(20);not null;default:'QUEUED';index:idx_status"` // 交易状态（QUEUED, PROCESSING, PENDING, MINED, CONFIRMED, FAILED, REPLACED）
+	BlockNumber          *int64     `gorm:"type:bigint"`                                                 // 区块号（交易被打包后才有值）
+	BlockHash            string     `gorm:"type:varchar(66)"`                                            // 打包区块哈希（用于检测链重组）
+	GasUsed              *int64     `gorm:"type:bigint"`                                                 // 实际使用的 Gas（交易被打包后才有值）
+	EffectiveGasPrice    string     `gorm:"type:varchar(78)"`                                            // 实际成交 Gas 价格（wei，取自交易回执）
+	ErrorMessage         string     `gorm:"type:text"`                                                   // 错误信息（[错误分类] 错误消息，失败或重试时记录）
+	SimulatedCallResults string     `gorm:"type:text"`                                                   // Multicall3 原子批量交易提交时各调用的模拟执行结果（JSON，不是链上执行结果）
+	RetryCount           int32      `gorm:"type:int;not null;default:0"`                                 // 已重试次数（不超过 chain.max_retry）
+	NextRetryAt          *time.Time `gorm:"type:datetime(3)"`                                            // 退避重试时间（排队任务在此之前不会被认领）
+	CreatedAt            time.Time  `gorm:"autoCreateTime;index:idx_created_at"`                         // 创建时间
+	UpdatedAt            time.Time  `gorm:"autoUpdateTime;index:idx_status_created_at,priority:2"`       // 更新时间
 }
 
 // TableName 指定表名
@@ -34,12 +42,32 @@ func (Transaction) TableName() string {
 	return "transaction"
 }
 
+// TransactionAttempt 交易广播记录（原始交易及每一次 RBF 替换交易）
+// 同一任务的所有尝试共享同一个 Nonce，任一尝试上链后都归属到原任务 ID
+type TransactionAttempt struct {
+	ID                   uint64    `gorm:"primaryKey;autoIncrement"`                                       // 主键 ID
+	TaskID               string    `gorm:"type:varchar(36);not null;index:idx_task_id"`                    // 关联 transaction.task_id
+	TxHash               string    `gorm:"type:varchar(66);uniqueIndex;not null"`                          // 本次广播的交易哈希
+	Attempt              int32     `gorm:"type:int;not null"`                                              // 尝试序号（0 为原始交易）
+	Nonce                int64     `gorm:"type:bigint;not null"`                                           // 交易 nonce
+	MaxFeePerGas         string    `gorm:"type:varchar(78);not null"`                                      // EIP-1559 maxFeePerGas（wei，字符串）
+	MaxPriorityFeePerGas string    `gorm:"type:varchar(78);not null"`                                      // EIP-1559 maxPriorityFeePerGas（wei，字符串）
+	RawTx                string    `gorm:"type:text;not null"`                                             // 已签名交易（hex）
+	Status               string    `gorm:"type:varchar(20);not null;default:'BROADCAST';index:idx_status"` // 状态（BROADCAST, REPLACED, MINED, FAILED）
+	CreatedAt            time.Time `gorm:"autoCreateTime"`                                                 // 广播时间
+}
+
+// TableName 指定表名
+func (TransactionAttempt) TableName() string {
+	return "transaction_attempt"
+}
+
 // Builder Builder 认证信息
 type Builder struct {
 	ID             uint64    `gorm:"primaryKey;autoIncrement"`                                    // 主键 ID
 	APIKey         string    `gorm:"type:varchar(255);uniqueIndex;not null"`                      // API Key（唯一标识）
-	SecretHash     string    `gorm:"type:varchar(255);not null"`                                  // Secret 哈希值（加密存储）
-	PassphraseHash string    `gorm:"type:varchar(255);not null"`                                  // Passphrase 哈希值（加密存储）
+	SecretHash     string    `gorm:"type:varchar(512);not null"`                                  // Secret（KMS 信封加密存储）
+	PassphraseHash string    `gorm:"type:varchar(512);not null"`                                  // Passphrase（KMS 信封加密存储）
 	Name           string    `gorm:"type:varchar(255)"`                                           // Builder 名称（可选）
 	Status         string    `gorm:"type:varchar(20);not null;default:'ACTIVE';index:idx_status"` // 状态（ACTIVE, INACTIVE）
 	CreatedAt      time.Time `gorm:"autoCreateTime"`                                              // 创建时间
@@ -56,10 +84,10 @@ type BuilderFee struct {
 	ID              uint64    `gorm:"primaryKey;autoIncrement"`                             // 主键 ID
 	BuilderAPIKey   string    `gorm:"type:varchar(255);not null;index:idx_builder_api_key"` // Builder API Key
 	TransactionType string    `gorm:"type:varchar(50);not null;index:idx_transaction_type"` // 交易类型（用于按类型统计）
-	TransactionID   string    `gorm:"type:varchar(36);not null"`                            // 关联 transaction.task_id
+	TransactionID   string    `gorm:"type:varchar(36);not null;uniqueIndex"`                // 关联 transaction.task_id（每笔交易最多一条费用记录）
 	GasUsed         int64     `gorm:"type:bigint;not null"`                                 // Gas 消耗量
-	GasPrice        string    `gorm:"type:varchar(78);not null"`                            // Gas 价格（字符串，支持大整数）
-	TotalCost       string    `gorm:"type:varchar(78);not null"`                            // 总成本（MATIC，字符串格式）
+	GasPrice        string    `gorm:"type:varchar(78);not null"`                            // 实际成交 Gas 价格（wei，字符串）
+	TotalCost       string    `gorm:"type:varchar(78);not null"`                            // 总成本（MATIC，18 位精度的十进制字符串）
 	CreatedAt       time.Time `gorm:"autoCreateTime;index:idx_created_at"`                  // 创建时间
 }
 
@@ -70,14 +98,16 @@ func (BuilderFee) TableName() string {
 
 // Operator Operator 钱包管理
 type Operator struct {
-	ID                  uint64    `gorm:"primaryKey;autoIncrement"`                                    // 主键 ID
-	Address             string    `gorm:"type:varchar(42);uniqueIndex;not null"`                       // Operator 钱包地址
-	PrivateKeyEncrypted string    `gorm:"type:text;not null"`                                          // 私钥（加密存储）
-	Status              string    `gorm:"type:varchar(20);not null;default:'ACTIVE';index:idx_status"` // 状态（ACTIVE, INACTIVE）
-	BalanceThreshold    string    `gorm:"type:varchar(78);not null;default:'1000000000000000000'"`     // 余额告警阈值（wei，默认 1 MATIC）
-	CurrentNonce        int64     `gorm:"type:bigint;not null;default:0"`                              // 当前 nonce（用于交易排序）
-	CreatedAt           time.Time `gorm:"autoCreateTime"`                                              // 创建时间
-	UpdatedAt           time.Time `gorm:"autoUpdateTime"`                                              // 更新时间
+	ID                  uint64    `gorm:"primaryKey;autoIncrement"`                                                           // 主键 ID
+	ChainID             int64     `gorm:"type:bigint;not null;default:137;uniqueIndex:idx_operator_chain_address,priority:1"` // 链 ID（同一地址在每条链上各有一条记录，Nonce 独立）
+	Address             string    `gorm:"type:varchar(42);not null;uniqueIndex:idx_operator_chain_address,priority:2"`        // Operator 钱包地址
+	PrivateKeyEncrypted string    `gorm:"type:text;not null"`                                                                 // 私钥（加密存储）
+	KmsKeyID            string    `gorm:"type:varchar(2048);not null;default:''"`                                             // KMS 托管签名密钥 ID（私钥不离开 KMS）
+	Status              string    `gorm:"type:varchar(20);not null;default:'ACTIVE';index:idx_status"`                        // 状态（ACTIVE, INACTIVE）
+	BalanceThreshold    string    `gorm:"type:varchar(78);not null;default:'1000000000000000000'"`                            // 余额告警阈值（wei，默认 1 MATIC）
+	CurrentNonce        int64     `gorm:"type:bigint;not null;default:0"`                                                     // 下一个待分配的 nonce（与链上 pending nonce 对账）
+	CreatedAt           time.Time `gorm:"autoCreateTime"`                                                                     // 创建时间
+	UpdatedAt           time.Time `gorm:"autoUpdateTime"`                                                                     // 更新时间
 }
 
 // TableName 指定表名
@@ -11,6 +11,7 @@ var ProviderSet = wire.NewSet(
 	NewRedis,
 	NewRocketMQ,
 	NewTransactionRepo,
+	NewTransactionAttemptRepo,
 	NewBuilderRepo,
 	NewBuilderFeeRepo,
 	NewOperatorRepo,
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TransactionRepo 交易仓库接口
@@ -16,17 +19,76 @@ type TransactionRepo interface {
 	GetByTxHash(ctx context.Context, txHash string) (*Transaction, error)
 	UpdateStatus(ctx context.Context, taskID string, status string) error
 	UpdateTxHash(ctx context.Context, taskID string, txHash string) error
+	// RecordExecution 原子地记录一次成功广播：交易哈希、nonce、gas limit、费用参数及第 0 次广播记录，任务状态置为 PENDING
+	RecordExecution(ctx context.Context, attempt *TransactionAttempt, gasLimit int64) error
 	UpdateGasUsed(ctx context.Context, taskID string, gasUsed int64, blockNumber int64) error
 	GetPendingTransactions(ctx context.Context, limit int) ([]*Transaction, error)
+	// GetMinedTransactions 获取已打包、尚未达到确认深度的交易（按区块号升序）
+	// 包括 MINED 交易，以及打包在 minFailedBlock 及之后区块中的 FAILED 交易（执行失败的回执同样可能被链重组撤销）
+	GetMinedTransactions(ctx context.Context, minFailedBlock int64, limit int) ([]*Transaction, error)
+	// MarkConfirmed 将仍打包在 blockHash 区块中的 MINED 交易置为 CONFIRMED，返回本次是否更新
+	MarkConfirmed(ctx context.Context, taskID string, blockHash string) (bool, error)
+	// CountPendingByOperator 统计每个 Operator 排队中及已广播未确认的交易数（key 为小写 from_address）
+	CountPendingByOperator(ctx context.Context) (map[string]int64, error)
+	// GetBroadcastByOperator 获取 Operator 已广播、未确认且 nonce >= minNonce 的交易（按 nonce 升序）
+	GetBroadcastByOperator(ctx context.Context, fromAddress string, minNonce int64) ([]*Transaction, error)
+	// ClaimQueued 认领 Operator 创建时间早于 before 且已到重试时间的 QUEUED 任务（SELECT ... FOR UPDATE SKIP LOCKED，按 ID 升序），认领后置为 PROCESSING
+	ClaimQueued(ctx context.Context, fromAddress string, before time.Time, limit int) ([]*Transaction, error)
+	// ClaimOrphaned 认领发送方不在 activeAddresses 中（Operator 已停用或删除）的 QUEUED 任务（不受重试时间限制），认领后置为 PROCESSING
+	ClaimOrphaned(ctx context.Context, activeAddresses []string, limit int) ([]*Transaction, error)
+	// Requeue 将已认领但未广播的任务放回队列（PROCESSING -> QUEUED）
+	Requeue(ctx context.Context, taskID string) error
+	// ScheduleRetry 将已认领的任务退避后重新排队（可同时切换 Operator），记录重试次数和错误信息
+	ScheduleRetry(ctx context.Context, taskID string, fromAddress string, retryCount int32, errorMessage string, notBefore time.Time) error
+	// MarkFailed 标记未广播的任务（QUEUED、PROCESSING）失败，记录重试次数和错误信息；已广播的任务不受影响
+	MarkFailed(ctx context.Context, taskID string, retryCount int32, errorMessage string) error
+	// RequeueExpired 将租约过期（updated_at 早于 before）且未广播的 PROCESSING 任务放回队列，返回放回的任务数
+	RequeueExpired(ctx context.Context, before time.Time) (int64, error)
+	// CountQueued 统计排队中（QUEUED、PROCESSING）的任务数
+	CountQueued(ctx context.Context) (int64, error)
+	// CreateWithAttempt 原子地创建已广播的交易记录及其第 0 次广播记录
+	CreateWithAttempt(ctx context.Context, tx *Transaction, attempt *TransactionAttempt) error
 	GetByBuilderAPIKey(ctx context.Context, apiKey string, startTime, endTime time.Time) ([]*Transaction, error)
 	GetByOrderID(ctx context.Context, orderID string) (*Transaction, error) // 根据订单 ID 查询交易（通过 Signature 字段中的 JSON 数据）
 }
 
+// TransactionAttemptRepo 交易广播记录仓库接口
+type TransactionAttemptRepo interface {
+	Create(ctx context.Context, attempt *TransactionAttempt) error
+	GetByTaskID(ctx context.Context, taskID string) ([]*TransactionAttempt, error)
+	// GetByTaskIDs 批量获取多个任务的广播记录（按任务、尝试序号升序）
+	GetByTaskIDs(ctx context.Context, taskIDs []string) ([]*TransactionAttempt, error)
+	GetByTxHash(ctx context.Context, txHash string) (*TransactionAttempt, error)
+	// Replace 记录一次 RBF 替换：旧尝试标记为 REPLACED，写入新尝试，并将任务指向新交易哈希
+	Replace(ctx context.Context, attempt *TransactionAttempt) error
+	// RecordReceipt 记录已上链尝试的回执：该尝试为 MINED / FAILED，其余为 REPLACED，任务写入回执数据
+	// 仅处理仍为 PENDING 的任务，返回本次是否写入（重复处理同一回执时返回 false）
+	RecordReceipt(ctx context.Context, taskID string, receipt *TransactionReceipt) (bool, error)
+	// RollbackReceipt 打包区块被链重组移出规范链时撤销回执：任务回退为 PENDING 并清空回执数据和失败原因，上链尝试回退为 BROADCAST
+	// 仅处理仍为 MINED / FAILED 且打包在 blockHash 区块中的任务，返回本次是否回退
+	RollbackReceipt(ctx context.Context, taskID string, blockHash string) (bool, error)
+}
+
+// TransactionReceipt 交易回执处理结果
+type TransactionReceipt struct {
+	TxHash            string // 上链的交易哈希（原始交易或某次替换交易）
+	Status            string // MINED（执行成功）或 FAILED（执行失败，status = 0）
+	BlockNumber       int64
+	BlockHash         string
+	GasUsed           int64
+	EffectiveGasPrice string // 实际成交 Gas 价格（wei）
+	ErrorMessage      string // 失败原因（仅 FAILED）
+}
+
 // BuilderRepo Builder 仓库接口
 type BuilderRepo interface {
 	Create(ctx context.Context, builder *Builder) error
 	GetByAPIKey(ctx context.Context, apiKey string) (*Builder, error)
 	UpdateStatus(ctx context.Context, apiKey string, status string) error
+	// GetAll 获取所有 Builder（包括未激活的，用于密钥轮换）
+	GetAll(ctx context.Context) ([]*Builder, error)
+	// UpdateSecrets CAS 更新 Builder 的加密凭证（仅当密文仍为 old 时更新），返回是否更新成功
+	UpdateSecrets(ctx context.Context, apiKey string, oldSecret, oldPassphrase, newSecret, newPassphrase string) (bool, error)
 }
 
 // BuilderFeeRepo Builder 费用仓库接口
@@ -57,19 +119,40 @@ type OperatorRepo interface {
 	GetActiveOperators(ctx context.Context) ([]*Operator, error)
 	UpdateNonce(ctx context.Context, address string, nonce int64) error
 	UpdateStatus(ctx context.Context, address string, status string) error
+	// GetAll 获取所有 Operator（包括未激活的，用于密钥轮换）
+	GetAll(ctx context.Context) ([]*Operator, error)
+	// UpdatePrivateKey CAS 更新 Operator 的加密私钥（仅当密文仍为 old 时更新），返回是否更新成功
+	UpdatePrivateKey(ctx context.Context, address string, oldEncrypted, newEncrypted string) (bool, error)
 }
 
 // transactionRepo 交易仓库实现
 type transactionRepo struct {
-	data *Data
+	data    *Data
+	chainID int64 // 限定的链 ID（为 0 时不限定，用于按任务 ID 查询等跨链操作）
 }
 
-// NewTransactionRepo 创建交易仓库
+// NewTransactionRepo 创建交易仓库（不限定链）
 func NewTransactionRepo(data *Data) TransactionRepo {
 	return &transactionRepo{data: data}
 }
 
+// NewChainTransactionRepo 创建限定链的交易仓库：队列认领、待确认扫描、按 Operator 统计等只处理该链的交易
+func NewChainTransactionRepo(data *Data, chainID int64) TransactionRepo {
+	return &transactionRepo{data: data, chainID: chainID}
+}
+
+// chainScope 限定链 ID 的查询条件
+func (r *transactionRepo) chainScope(db *gorm.DB) *gorm.DB {
+	if r.chainID == 0 {
+		return db
+	}
+	return db.Where("chain_id = ?", r.chainID)
+}
+
 func (r *transactionRepo) Create(ctx context.Context, tx *Transaction) error {
+	if tx.ChainID == 0 {
+		tx.ChainID = r.chainID
+	}
 	return r.data.db.WithContext(ctx).Create(tx).Error
 }
 
@@ -111,6 +194,26 @@ func (r *transactionRepo) UpdateTxHash(ctx context.Context, taskID string, txHas
 		Update("tx_hash", txHash).Error
 }
 
+func (r *transactionRepo) RecordExecution(ctx context.Context, attempt *TransactionAttempt, gasLimit int64) error {
+	return r.data.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// gas_price 同步为 maxFeePerGas，即本笔交易愿意支付的上限
+		if err := tx.Model(&Transaction{}).
+			Where("task_id = ?", attempt.TaskID).
+			Updates(map[string]interface{}{
+				"tx_hash":                  attempt.TxHash,
+				"nonce":                    attempt.Nonce,
+				"gas_limit":                gasLimit,
+				"gas_price":                attempt.MaxFeePerGas,
+				"max_fee_per_gas":          attempt.MaxFeePerGas,
+				"max_priority_fee_per_gas": attempt.MaxPriorityFeePerGas,
+				"status":                   "PENDING",
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Create(attempt).Error
+	})
+}
+
 func (r *transactionRepo) UpdateGasUsed(ctx context.Context, taskID string, gasUsed int64, blockNumber int64) error {
 	return r.data.db.WithContext(ctx).
 		Model(&Transaction{}).
@@ -124,7 +227,7 @@ func (r *transactionRepo) UpdateGasUsed(ctx context.Context, taskID string, gasU
 
 func (r *transactionRepo) GetPendingTransactions(ctx context.Context, limit int) ([]*Transaction, error) {
 	var txs []*Transaction
-	err := r.data.db.WithContext(ctx).
+	err := r.data.db.WithContext(ctx).Scopes(r.chainScope).
 		Where("status = ?", "PENDING").
 		Order("created_at ASC").
 		Limit(limit).
@@ -132,6 +235,185 @@ func (r *transactionRepo) GetPendingTransactions(ctx context.Context, limit int)
 	return txs, err
 }
 
+func (r *transactionRepo) GetMinedTransactions(ctx context.Context, minFailedBlock int64, limit int) ([]*Transaction, error) {
+	var txs []*Transaction
+	err := r.data.db.WithContext(ctx).Scopes(r.chainScope).
+		Where("(status = ? OR (status = ? AND block_number >= ?))", "MINED", "FAILED", minFailedBlock).
+		Order("block_number ASC").
+		Limit(limit).
+		Find(&txs).Error
+	return txs, err
+}
+
+func (r *transactionRepo) MarkConfirmed(ctx context.Context, taskID string, blockHash string) (bool, error) {
+	result := r.data.db.WithContext(ctx).
+		Model(&Transaction{}).
+		Where("task_id = ? AND status = ? AND block_hash = ?", taskID, "MINED", blockHash).
+		Update("status", "CONFIRMED")
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *transactionRepo) CountPendingByOperator(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		FromAddress string
+		Count       int64
+	}
+	err := r.data.db.WithContext(ctx).Scopes(r.chainScope).
+		Model(&Transaction{}).
+		Select("from_address, COUNT(*) AS count").
+		Where("status IN ?", []string{"QUEUED", "PROCESSING", "PENDING"}).
+		Group("from_address").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[strings.ToLower(row.FromAddress)] += row.Count
+	}
+	return counts, nil
+}
+
+func (r *transactionRepo) GetBroadcastByOperator(ctx context.Context, fromAddress string, minNonce int64) ([]*Transaction, error) {
+	var txs []*Transaction
+	err := r.data.db.WithContext(ctx).Scopes(r.chainScope).
+		Where("from_address = ? AND status = ? AND tx_hash <> '' AND nonce >= ?", fromAddress, "PENDING", minNonce).
+		Order("nonce ASC").
+		Find(&txs).Error
+	return txs, err
+}
+
+func (r *transactionRepo) ClaimQueued(ctx context.Context, fromAddress string, before time.Time, limit int) ([]*Transaction, error) {
+	var txs []*Transaction
+	err := r.data.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		// SKIP LOCKED：多个 worker（包括其他副本）并发认领时互不阻塞，也不会认领到同一行
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Scopes(r.chainScope).
+			Where("from_address = ? AND status = ? AND created_at < ?", fromAddress, "QUEUED", before).
+			Where("next_retry_at IS NULL OR next_retry_at <= ?", time.Now()).
+			Order("id ASC").
+			Limit(limit).
+			Find(&txs).Error; err != nil {
+			return err
+		}
+		if len(txs) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, len(txs))
+		for i, tx := range txs {
+			ids[i] = tx.ID
+		}
+		return db.Model(&Transaction{}).
+			Where("id IN ?", ids).
+			Update("status", "PROCESSING").Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tx := range txs {
+		tx.Status = "PROCESSING"
+	}
+	return txs, nil
+}
+
+func (r *transactionRepo) ClaimOrphaned(ctx context.Context, activeAddresses []string, limit int) ([]*Transaction, error) {
+	var txs []*Transaction
+	err := r.data.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		query := db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Scopes(r.chainScope).
+			Where("status = ?", "QUEUED")
+		// 空列表时 NOT IN 会渲染为 NOT IN (NULL)，不匹配任何行
+		if len(activeAddresses) > 0 {
+			query = query.Where("from_address NOT IN ?", activeAddresses)
+		}
+		if err := query.Order("id ASC").Limit(limit).Find(&txs).Error; err != nil {
+			return err
+		}
+		if len(txs) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, len(txs))
+		for i, tx := range txs {
+			ids[i] = tx.ID
+		}
+		return db.Model(&Transaction{}).
+			Where("id IN ?", ids).
+			Update("status", "PROCESSING").Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tx := range txs {
+		tx.Status = "PROCESSING"
+	}
+	return txs, nil
+}
+
+func (r *transactionRepo) Requeue(ctx context.Context, taskID string) error {
+	return r.data.db.WithContext(ctx).
+		Model(&Transaction{}).
+		Where("task_id = ? AND status = ?", taskID, "PROCESSING").
+		Update("status", "QUEUED").Error
+}
+
+func (r *transactionRepo) ScheduleRetry(ctx context.Context, taskID string, fromAddress string, retryCount int32, errorMessage string, notBefore time.Time) error {
+	return r.data.db.WithContext(ctx).
+		Model(&Transaction{}).
+		Where("task_id = ? AND status = ?", taskID, "PROCESSING").
+		Updates(map[string]interface{}{
+			"status":        "QUEUED",
+			"from_address":  fromAddress,
+			"retry_count":   retryCount,
+			"error_message": errorMessage,
+			"next_retry_at": notBefore,
+		}).Error
+}
+
+func (r *transactionRepo) MarkFailed(ctx context.Context, taskID string, retryCount int32, errorMessage string) error {
+	return r.data.db.WithContext(ctx).
+		Model(&Transaction{}).
+		Where("task_id = ? AND status IN ?", taskID, []string{"QUEUED", "PROCESSING"}).
+		Updates(map[string]interface{}{
+			"status":        "FAILED",
+			"retry_count":   retryCount,
+			"error_message": errorMessage,
+		}).Error
+}
+
+func (r *transactionRepo) RequeueExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.data.db.WithContext(ctx).Scopes(r.chainScope).
+		Model(&Transaction{}).
+		Where("status = ? AND (tx_hash IS NULL OR tx_hash = '') AND updated_at < ?", "PROCESSING", before).
+		Update("status", "QUEUED")
+	return result.RowsAffected, result.Error
+}
+
+func (r *transactionRepo) CountQueued(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.data.db.WithContext(ctx).Scopes(r.chainScope).
+		Model(&Transaction{}).
+		Where("status IN ?", []string{"QUEUED", "PROCESSING"}).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *transactionRepo) CreateWithAttempt(ctx context.Context, tx *Transaction, attempt *TransactionAttempt) error {
+	if tx.ChainID == 0 {
+		tx.ChainID = r.chainID
+	}
+	return r.data.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		if err := db.Create(tx).Error; err != nil {
+			return err
+		}
+		return db.Create(attempt).Error
+	})
+}
+
 func (r *transactionRepo) GetByBuilderAPIKey(ctx context.Context, apiKey string, startTime, endTime time.Time) ([]*Transaction, error) {
 	var txs []*Transaction
 	err := r.data.db.WithContext(ctx).
@@ -163,6 +445,141 @@ func (r *transactionRepo) GetByOrderID(ctx context.Context, orderID string) (*Tr
 	return &tx, nil
 }
 
+// transactionAttemptRepo 交易广播记录仓库实现
+type transactionAttemptRepo struct {
+	data *Data
+}
+
+// NewTransactionAttemptRepo 创建交易广播记录仓库
+func NewTransactionAttemptRepo(data *Data) TransactionAttemptRepo {
+	return &transactionAttemptRepo{data: data}
+}
+
+func (r *transactionAttemptRepo) Create(ctx context.Context, attempt *TransactionAttempt) error {
+	return r.data.db.WithContext(ctx).Create(attempt).Error
+}
+
+func (r *transactionAttemptRepo) GetByTaskID(ctx context.Context, taskID string) ([]*TransactionAttempt, error) {
+	var attempts []*TransactionAttempt
+	err := r.data.db.WithContext(ctx).
+		Where("task_id = ?", taskID).
+		Order("attempt ASC").
+		Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *transactionAttemptRepo) GetByTaskIDs(ctx context.Context, taskIDs []string) ([]*TransactionAttempt, error) {
+	var attempts []*TransactionAttempt
+	if len(taskIDs) == 0 {
+		return attempts, nil
+	}
+	err := r.data.db.WithContext(ctx).
+		Where("task_id IN ?", taskIDs).
+		Order("task_id ASC, attempt ASC").
+		Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *transactionAttemptRepo) GetByTxHash(ctx context.Context, txHash string) (*TransactionAttempt, error) {
+	var attempt TransactionAttempt
+	err := r.data.db.WithContext(ctx).Where("tx_hash = ?", txHash).First(&attempt).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *transactionAttemptRepo) Replace(ctx context.Context, attempt *TransactionAttempt) error {
+	return r.data.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&TransactionAttempt{}).
+			Where("task_id = ? AND status = ?", attempt.TaskID, "BROADCAST").
+			Update("status", "REPLACED").Error; err != nil {
+			return err
+		}
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Transaction{}).
+			Where("task_id = ?", attempt.TaskID).
+			Updates(map[string]interface{}{
+				"tx_hash":                  attempt.TxHash,
+				"gas_price":                attempt.MaxFeePerGas,
+				"max_fee_per_gas":          attempt.MaxFeePerGas,
+				"max_priority_fee_per_gas": attempt.MaxPriorityFeePerGas,
+			}).Error
+	})
+}
+
+func (r *transactionAttemptRepo) RecordReceipt(ctx context.Context, taskID string, receipt *TransactionReceipt) (bool, error) {
+	applied := false
+	err := r.data.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Transaction{}).
+			Where("task_id = ? AND status = ?", taskID, "PENDING").
+			Updates(map[string]interface{}{
+				"tx_hash":             receipt.TxHash,
+				"status":              receipt.Status,
+				"block_number":        receipt.BlockNumber,
+				"block_hash":          receipt.BlockHash,
+				"gas_used":            receipt.GasUsed,
+				"effective_gas_price": receipt.EffectiveGasPrice,
+				"error_message":       receipt.ErrorMessage,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		applied = true
+
+		if err := tx.Model(&TransactionAttempt{}).
+			Where("task_id = ? AND tx_hash <> ?", taskID, receipt.TxHash).
+			Update("status", "REPLACED").Error; err != nil {
+			return err
+		}
+		return tx.Model(&TransactionAttempt{}).
+			Where("task_id = ? AND tx_hash = ?", taskID, receipt.TxHash).
+			Update("status", receipt.Status).Error
+	})
+	return applied, err
+}
+
+func (r *transactionAttemptRepo) RollbackReceipt(ctx context.Context, taskID string, blockHash string) (bool, error) {
+	applied := false
+	err := r.data.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var mined Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("task_id = ? AND status IN ? AND block_hash = ?", taskID, []string{"MINED", "FAILED"}, blockHash).
+			Take(&mined).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		applied = true
+
+		if err := tx.Model(&Transaction{}).
+			Where("id = ?", mined.ID).
+			Updates(map[string]interface{}{
+				"status":              "PENDING",
+				"block_number":        nil,
+				"block_hash":          nil,
+				"gas_used":            nil,
+				"effective_gas_price": nil,
+				"error_message":       "",
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&TransactionAttempt{}).
+			Where("task_id = ? AND tx_hash = ?", taskID, mined.TxHash).
+			Update("status", "BROADCAST").Error
+	})
+	return applied, err
+}
+
 // builderRepo Builder 仓库实现
 type builderRepo struct {
 	data *Data
@@ -196,6 +613,23 @@ func (r *builderRepo) UpdateStatus(ctx context.Context, apiKey string, status st
 		Update("status", status).Error
 }
 
+func (r *builderRepo) GetAll(ctx context.Context) ([]*Builder, error) {
+	var builders []*Builder
+	err := r.data.db.WithContext(ctx).Find(&builders).Error
+	return builders, err
+}
+
+func (r *builderRepo) UpdateSecrets(ctx context.Context, apiKey string, oldSecret, oldPassphrase, newSecret, newPassphrase string) (bool, error) {
+	result := r.data.db.WithContext(ctx).
+		Model(&Builder{}).
+		Where("api_key = ? AND secret_hash = ? AND passphrase_hash = ?", apiKey, oldSecret, oldPassphrase).
+		Updates(map[string]interface{}{
+			"secret_hash":     newSecret,
+			"passphrase_hash": newPassphrase,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
 // builderFeeRepo Builder 费用仓库实现
 type builderFeeRepo struct {
 	data *Data
@@ -207,7 +641,10 @@ func NewBuilderFeeRepo(data *Data) BuilderFeeRepo {
 }
 
 func (r *builderFeeRepo) Create(ctx context.Context, fee *BuilderFee) error {
-	return r.data.db.WithContext(ctx).Create(fee).Error
+	// 同一交易重复记录时忽略（transaction_id 唯一）
+	return r.data.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(fee).Error
 }
 
 func (r *builderFeeRepo) GetStatsByBuilder(ctx context.Context, apiKey string, startTime, endTime time.Time) (*BuilderFeeStats, error) {
@@ -219,47 +656,83 @@ func (r *builderFeeRepo) GetStatsByBuilder(ctx context.Context, apiKey string, s
 		return nil, err
 	}
 
+	// Gas 和成本使用大整数 / 有理数累加，避免溢出和精度损失
+	totalGas := new(big.Int)
+	totalCost := new(big.Rat)
+	gasByType := make(map[string]*big.Int)
+	costByType := make(map[string]*big.Rat)
 	stats := &BuilderFeeStats{
 		TotalTransactions: int64(len(fees)),
-		TotalGasUsed:      "0",
-		TotalCost:         "0",
 		ByType:            make(map[string]*FeeStatsByType),
 	}
-
-	// 计算总 Gas 和总成本（需要大整数运算，这里简化处理）
-	// 实际应该使用 big.Int 进行计算
 	for _, fee := range fees {
 		if stats.ByType[fee.TransactionType] == nil {
-			stats.ByType[fee.TransactionType] = &FeeStatsByType{
-				Count:   0,
-				GasUsed: "0",
-				Cost:    "0",
-			}
+			stats.ByType[fee.TransactionType] = &FeeStatsByType{}
+			gasByType[fee.TransactionType] = new(big.Int)
+			costByType[fee.TransactionType] = new(big.Rat)
 		}
 		stats.ByType[fee.TransactionType].Count++
-		// TODO: 使用 big.Int 进行累加
+
+		gas := big.NewInt(fee.GasUsed)
+		totalGas.Add(totalGas, gas)
+		gasByType[fee.TransactionType].Add(gasByType[fee.TransactionType], gas)
+
+		if cost, ok := new(big.Rat).SetString(fee.TotalCost); ok {
+			totalCost.Add(totalCost, cost)
+			costByType[fee.TransactionType].Add(costByType[fee.TransactionType], cost)
+		}
 	}
 
+	stats.TotalGasUsed = totalGas.String()
+	stats.TotalCost = formatDecimal(totalCost)
+	for txType, byType := range stats.ByType {
+		byType.GasUsed = gasByType[txType].String()
+		byType.Cost = formatDecimal(costByType[txType])
+	}
 	return stats, nil
 }
 
+// formatDecimal 格式化为 18 位精度的十进制字符串（去掉末尾的 0）
+func formatDecimal(r *big.Rat) string {
+	s := r.FloatString(18)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
 // operatorRepo Operator 仓库实现
 type operatorRepo struct {
-	data *Data
+	data    *Data
+	chainID int64 // 限定的链 ID（为 0 时不限定，用于密钥轮换、签名器等与链无关的操作）
 }
 
-// NewOperatorRepo 创建 Operator 仓库
+// NewOperatorRepo 创建 Operator 仓库（不限定链）
 func NewOperatorRepo(data *Data) OperatorRepo {
 	return &operatorRepo{data: data}
 }
 
+// NewChainOperatorRepo 创建限定链的 Operator 仓库（该链的 Operator 池及 Nonce）
+func NewChainOperatorRepo(data *Data, chainID int64) OperatorRepo {
+	return &operatorRepo{data: data, chainID: chainID}
+}
+
+// chainScope 限定链 ID 的查询条件
+func (r *operatorRepo) chainScope(db *gorm.DB) *gorm.DB {
+	if r.chainID == 0 {
+		return db
+	}
+	return db.Where("chain_id = ?", r.chainID)
+}
+
 func (r *operatorRepo) Create(ctx context.Context, operator *Operator) error {
+	if operator.ChainID == 0 {
+		operator.ChainID = r.chainID
+	}
 	return r.data.db.WithContext(ctx).Create(operator).Error
 }
 
 func (r *operatorRepo) GetByAddress(ctx context.Context, address string) (*Operator, error) {
 	var operator Operator
-	err := r.data.db.WithContext(ctx).Where("address = ?", address).First(&operator).Error
+	err := r.data.db.WithContext(ctx).Scopes(r.chainScope).Where("address = ?", address).First(&operator).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -271,24 +744,38 @@ func (r *operatorRepo) GetByAddress(ctx context.Context, address string) (*Opera
 
 func (r *operatorRepo) GetActiveOperators(ctx context.Context) ([]*Operator, error) {
 	var operators []*Operator
-	err := r.data.db.WithContext(ctx).
+	err := r.data.db.WithContext(ctx).Scopes(r.chainScope).
 		Where("status = ?", "ACTIVE").
 		Find(&operators).Error
 	return operators, err
 }
 
 func (r *operatorRepo) UpdateNonce(ctx context.Context, address string, nonce int64) error {
-	return r.data.db.WithContext(ctx).
+	return r.data.db.WithContext(ctx).Scopes(r.chainScope).
 		Model(&Operator{}).
 		Where("address = ?", address).
 		Update("current_nonce", nonce).Error
 }
 
 func (r *operatorRepo) UpdateStatus(ctx context.Context, address string, status string) error {
-	return r.data.db.WithContext(ctx).
+	return r.data.db.WithContext(ctx).Scopes(r.chainScope).
 		Model(&Operator{}).
 		Where("address = ?", address).
 		Update("status", status).Error
 }
 
+func (r *operatorRepo) GetAll(ctx context.Context) ([]*Operator, error) {
+	var operators []*Operator
+	err := r.data.db.WithContext(ctx).Scopes(r.chainScope).Find(&operators).Error
+	return operators, err
+}
+
+func (r *operatorRepo) UpdatePrivateKey(ctx context.Context, address string, oldEncrypted, newEncrypted string) (bool, error) {
+	result := r.data.db.WithContext(ctx).Scopes(r.chainScope).
+		Model(&Operator{}).
+		Where("address = ? AND private_key_encrypted = ?", address, oldEncrypted).
+		Update("private_key_encrypted", newEncrypted)
+	return result.RowsAffected > 0, result.Error
+}
+
 
@@ -0,0 +1,150 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"prediction-relayer-service/internal/gas"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrorCategory 广播 / Gas 估算错误分类
+type ErrorCategory string
+
+const (
+	CategoryNonceTooLow            ErrorCategory = "NONCE_TOO_LOW"           // Nonce 已被使用（本地 Nonce 落后于链上）
+	CategoryReplacementUnderpriced ErrorCategory = "REPLACEMENT_UNDERPRICED" // 交易池中已有同 Nonce 交易且出价不低于本次
+	CategoryInsufficientFunds      ErrorCategory = "INSUFFICIENT_FUNDS"      // Operator 余额不足以支付 gas * price + value
+	CategoryIntrinsicGasTooLow     ErrorCategory = "INTRINSIC_GAS_TOO_LOW"   // Gas Limit 低于交易固有 Gas
+	CategoryExecutionReverted      ErrorCategory = "EXECUTION_REVERTED"      // 合约执行回滚（估算或广播时）
+	CategoryRPCTransient           ErrorCategory = "RPC_TRANSIENT"           // 网络错误、超时、限流或节点 5xx
+	CategoryFeeCapExceeded         ErrorCategory = "FEE_CAP_EXCEEDED"        // 当前费用超过交易类型的 maxFeePerGas 上限
+	CategoryUnknown                ErrorCategory = "UNKNOWN"                 // 未识别的错误
+)
+
+// RecoveryAction 错误恢复动作
+type RecoveryAction string
+
+const (
+	ActionResyncNonce    RecoveryAction = "RESYNC_NONCE"    // 与链上对账 Nonce 后立即重试
+	ActionBumpFee        RecoveryAction = "BUMP_FEE"        // 上调费用后立即重试
+	ActionSwitchOperator RecoveryAction = "SWITCH_OPERATOR" // 换一个 Operator 重新排队
+	ActionRetryLater     RecoveryAction = "RETRY_LATER"     // 退避后重新排队
+	ActionWait           RecoveryAction = "WAIT"            // 等待费用回落后重新排队（不计入重试次数）
+	ActionFailFast       RecoveryAction = "FAIL_FAST"       // 重试无意义，直接失败
+)
+
+// Action 错误分类对应的恢复动作
+func (c ErrorCategory) Action() RecoveryAction {
+	switch c {
+	case CategoryNonceTooLow:
+		return ActionResyncNonce
+	case CategoryReplacementUnderpriced:
+		return ActionBumpFee
+	case CategoryInsufficientFunds:
+		return ActionSwitchOperator
+	case CategoryRPCTransient:
+		return ActionRetryLater
+	case CategoryFeeCapExceeded:
+		return ActionWait
+	default:
+		// 回滚和固有 Gas 不足由交易本身决定，重试结果不会改变
+		return ActionFailFast
+	}
+}
+
+// Classify 对 SendTransaction / EstimateGas / 费用计算返回的错误分类
+// 节点错误经 JSON-RPC 传回后只剩错误消息，按 geth 交易池和状态转换的标准错误消息匹配
+func Classify(err error) ErrorCategory {
+	if err == nil {
+		return CategoryUnknown
+	}
+
+	var revertErr *RevertError
+	if errors.As(err, &revertErr) {
+		return CategoryExecutionReverted
+	}
+	if errors.Is(err, gas.ErrFeeCapExceeded) {
+		return CategoryFeeCapExceeded
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "nonce too low"):
+		return CategoryNonceTooLow
+	case strings.Contains(msg, "replacement transaction underpriced"),
+		strings.Contains(msg, "transaction underpriced"):
+		return CategoryReplacementUnderpriced
+	case strings.Contains(msg, "insufficient funds"):
+		return CategoryInsufficientFunds
+	case strings.Contains(msg, "intrinsic gas too low"):
+		return CategoryIntrinsicGasTooLow
+	case strings.Contains(msg, "execution reverted"):
+		return CategoryExecutionReverted
+	}
+
+	if isTransient(err, msg) {
+		return CategoryRPCTransient
+	}
+	return CategoryUnknown
+}
+
+// isTransient 是否为可重试的 RPC 错误
+func isTransient(err error, msg string) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+
+	for _, s := range []string{
+		"connection refused",
+		"connection reset",
+		"timeout",
+		"too many requests",
+		"rate limit",
+		"header not found",
+		"service unavailable",
+		"bad gateway",
+	} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAlreadyKnown 节点是否已持有该交易（重复广播同一笔已签名交易）
+func isAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
+
+// isAmbiguousSend SendTransaction 的错误是否无法确定节点是否已接收交易
+// 节点返回的 JSON-RPC 错误和 4xx 响应是明确拒绝；超时、连接中断、5xx（网关可能已转发）等无法确定
+func isAmbiguousSend(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+// FormatError 格式化写入 transaction.error_message 的错误信息：[CATEGORY] message
+func FormatError(category ErrorCategory, err error) string {
+	return fmt.Sprintf("[%s] %v", category, err)
+}
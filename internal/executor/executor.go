@@ -2,18 +2,22 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
-	"time"
 
 	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/gas"
 	"prediction-relayer-service/internal/nonce"
+	"prediction-relayer-service/internal/signer"
+	"prediction-relayer-service/internal/wallet"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // Executor 交易执行器接口
@@ -21,156 +25,425 @@ type Executor interface {
 	// Execute 执行交易
 	Execute(ctx context.Context, tx *data.Transaction, operator *data.Operator) (*ExecutionResult, error)
 
+	// ExecuteWithFeeBump 执行交易，费用参数按 bumpPercent 上调（例如 115 = 115%，用于 replacement underpriced 后重试）
+	ExecuteWithFeeBump(ctx context.Context, tx *data.Transaction, operator *data.Operator, bumpPercent int64) (*ExecutionResult, error)
+
+	// ExecuteWithNonce 使用指定 Nonce 执行交易（不经过 Nonce 管理器分配，用于填补 Nonce 空洞）
+	ExecuteWithNonce(ctx context.Context, tx *data.Transaction, operator *data.Operator, nonce uint64) (*ExecutionResult, error)
+
+	// SendSelfTransfer 使用指定 Nonce 发送 0 值自转账（用于填补 Nonce 空洞）
+	SendSelfTransfer(ctx context.Context, operator *data.Operator, nonce uint64) (*ExecutionResult, error)
+
 	// EstimateGas 估算 Gas Limit
 	EstimateGas(ctx context.Context, tx *data.Transaction) (uint64, error)
 
-	// SelectOperator 选择可用的 Operator
-	SelectOperator(ctx context.Context) (*data.Operator, error)
+	// Simulate 以 Operator 身份通过 eth_call 模拟执行（pending 状态），会回滚时返回 *RevertError
+	Simulate(ctx context.Context, tx *data.Transaction, operator *data.Operator) error
+
+	// SimulateCall 与 Simulate 相同，同时返回调用的返回数据（例如 Multicall3 aggregate3 的各调用结果）
+	SimulateCall(ctx context.Context, tx *data.Transaction, operator *data.Operator) ([]byte, error)
+
+	// DecodeRevert 解码回滚数据（Error(string)、Panic(uint256) 以及已注册 ABI 中的自定义错误）
+	DecodeRevert(data []byte) string
+
+	// ReplayRevert 在指定区块状态上重放已上链但执行失败的交易，返回解码后的回滚错误（重放未回滚时返回 nil）
+	ReplayRevert(ctx context.Context, tx *data.Transaction, blockNumber *big.Int) (*RevertError, error)
+
+	// ReplaceByFee 以相同 Nonce、更高费用重新签名并广播原交易
+	// bumpPercent 为费用倍数（例如 120 = 120%），低于节点最小替换涨幅时按最小涨幅处理
+	ReplaceByFee(ctx context.Context, tx *data.Transaction, bumpPercent int64) (*ExecutionResult, error)
+
+	// Rebroadcast 重新广播已签名交易（例如打包区块被链重组移出后），节点已持有该交易时视为成功
+	Rebroadcast(ctx context.Context, rawTx string) error
+}
+
+// ChainClient 链上交互接口（*ethclient.Client 和 *rpcpool.Pool 已实现）
+type ChainClient interface {
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
 }
 
+// MinReplacementBumpPercent 节点接受替换交易的最小费用涨幅（geth/bor 默认 10%）
+const MinReplacementBumpPercent = 10
+
 // ExecutionResult 执行结果
 type ExecutionResult struct {
-	TxHash   string
-	GasUsed  uint64
-	BlockNum uint64
+	TxHash               string
+	Nonce                uint64
+	GasLimit             uint64
+	RawTx                string // 已签名交易（hex），用于替换链记录和重新广播
+	GasUsed              uint64
+	BlockNum             uint64
+	MaxFeePerGas         *big.Int // 实际出价的 maxFeePerGas（wei）
+	MaxPriorityFeePerGas *big.Int // 实际出价的 maxPriorityFeePerGas（wei）
+	SendError            error    // 广播结果不确定时的发送错误（节点可能未收到交易，由监控器确认或重新广播）
 }
 
 // executor 交易执行器实现
 type executor struct {
-	ethClient     *ethclient.Client
+	ethClient     ChainClient
 	chainID       *big.Int
 	nonceMgr      nonce.Manager
 	operatorRepo  data.OperatorRepo
-	gasMultiplier int64 // Gas Price 倍数（例如 110 = 110%）
+	signers       signer.Provider
+	revertDecoder *RevertDecoder
+	gasOracle     gas.GasOracle
 }
 
 // NewExecutor 创建交易执行器
 func NewExecutor(
-	ethClient *ethclient.Client,
+	ethClient ChainClient,
 	chainID *big.Int,
 	nonceMgr nonce.Manager,
 	operatorRepo data.OperatorRepo,
-	gasMultiplier int64,
+	signers signer.Provider,
+	revertDecoder *RevertDecoder,
+	gasOracle gas.GasOracle,
 ) Executor {
 	return &executor{
 		ethClient:     ethClient,
 		chainID:       chainID,
 		nonceMgr:      nonceMgr,
 		operatorRepo:  operatorRepo,
-		gasMultiplier: gasMultiplier,
+		signers:       signers,
+		revertDecoder: revertDecoder,
+		gasOracle:     gasOracle,
 	}
 }
 
 // Execute 执行交易
 func (e *executor) Execute(ctx context.Context, tx *data.Transaction, operator *data.Operator) (*ExecutionResult, error) {
+	return e.ExecuteWithFeeBump(ctx, tx, operator, 100)
+}
+
+// ExecuteWithFeeBump 执行交易（费用按 bumpPercent 上调）
+func (e *executor) ExecuteWithFeeBump(ctx context.Context, tx *data.Transaction, operator *data.Operator, bumpPercent int64) (*ExecutionResult, error) {
 	// 1. 获取 Nonce
 	nonce, err := e.nonceMgr.AcquireNonce(ctx, operator.Address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire nonce: %w", err)
 	}
 
-	// 2. 估算 Gas Limit（如果未提供）
+	// 2. 使用该 Nonce 执行，失败时释放 Nonce（仅当它仍是最后分配的 Nonce 时可回收）
+	result, err := e.executeWithNonce(ctx, tx, operator, nonce, bumpPercent)
+	if err != nil {
+		e.nonceMgr.ReleaseNonce(ctx, operator.Address, nonce)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ExecuteWithNonce 使用指定 Nonce 执行交易
+func (e *executor) ExecuteWithNonce(ctx context.Context, tx *data.Transaction, operator *data.Operator, nonce uint64) (*ExecutionResult, error) {
+	return e.executeWithNonce(ctx, tx, operator, nonce, 100)
+}
+
+// executeWithNonce 使用指定 Nonce 和费用倍数执行交易
+func (e *executor) executeWithNonce(ctx context.Context, tx *data.Transaction, operator *data.Operator, nonce uint64, bumpPercent int64) (*ExecutionResult, error) {
+	// 1. 估算 Gas Limit（如果未提供）
 	gasLimit := uint64(tx.GasLimit)
 	if gasLimit == 0 {
-		gasLimit, err = e.EstimateGas(ctx, tx)
+		var err error
+		gasLimit, err = e.estimateGas(ctx, tx, common.HexToAddress(operator.Address))
 		if err != nil {
-			e.nonceMgr.ReleaseNonce(ctx, operator.Address, nonce)
 			return nil, fmt.Errorf("failed to estimate gas: %w", err)
 		}
 	}
 
-	// 3. 获取 Gas Price（加权 10% 以加速）
-	gasPrice, err := e.ethClient.SuggestGasPrice(ctx)
+	// 2. 签名前模拟执行，会回滚的交易不广播（避免消耗 Operator 的 Gas）
+	if _, err := e.simulate(ctx, tx, operator, gasLimit); err != nil {
+		return nil, err
+	}
+
+	// 3. 获取 EIP-1559 费用参数（standard 档位），并按交易类型的上限封顶（超过上限时返回 gas.ErrFeeCapExceeded，任务等待）
+	fees, err := e.gasOracle.Suggest(ctx, gas.TierStandard)
 	if err != nil {
-		e.nonceMgr.ReleaseNonce(ctx, operator.Address, nonce)
-		return nil, fmt.Errorf("failed to get gas price: %w", err)
+		return nil, fmt.Errorf("failed to get fee params: %w", err)
+	}
+	tipCap, feeCap := fees.MaxPriorityFeePerGas, fees.MaxFeePerGas
+	if bumpPercent > 100 {
+		tipCap = bumpFee(tipCap, bumpPercent)
+		feeCap = bumpFee(feeCap, bumpPercent)
+	}
+	// Proxy Wallet 中继交易的小费和费用上限不低于签名的 gasPrice（tx.gasprice 低于它时 relayCall 回滚）
+	relayPrice, err := relayGasPrice(tx)
+	if err != nil {
+		return nil, err
+	}
+	if relayPrice != nil {
+		if tipCap.Cmp(relayPrice) < 0 {
+			tipCap = relayPrice
+		}
+		if feeCap.Cmp(tipCap) < 0 {
+			feeCap = tipCap
+		}
+	}
+	feeCap, err = e.gasOracle.ApplyCap(tx.TransactionType, fees.BaseFee, tipCap, feeCap)
+	if err != nil {
+		return nil, err
+	}
+	if relayPrice != nil && feeCap.Cmp(relayPrice) < 0 {
+		return nil, fmt.Errorf("%w: relay requires gas price %s wei, cap %s wei", gas.ErrFeeCapExceeded, relayPrice, feeCap)
 	}
-	// 应用倍数
-	gasPrice = new(big.Int).Mul(gasPrice, big.NewInt(e.gasMultiplier))
-	gasPrice = new(big.Int).Div(gasPrice, big.NewInt(100))
 
-	// 4. 解析目标地址和数据
-	toAddr := common.HexToAddress(tx.ToAddress)
-	var dataBytes []byte
-	if tx.Data != "" {
-		dataBytes = common.FromHex(tx.Data)
+	// 4. 解析目标地址、Value 和数据
+	toAddr, value, dataBytes, err := parsePayload(tx)
+	if err != nil {
+		return nil, err
 	}
 
-	// 5. 解析 Value
-	value := big.NewInt(0)
-	if tx.Value != "" && tx.Value != "0x0" {
-		value, _ = new(big.Int).SetString(tx.Value[2:], 16)
+	// 5. 创建、签名并广播交易（EIP-1559 DynamicFeeTx）
+	// 注意：这里不等待交易确认，交易监控器会处理确认逻辑
+	return e.signAndSend(ctx, operator, &types.DynamicFeeTx{
+		ChainID:   e.chainID,
+		Nonce:     nonce,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       gasLimit,
+		To:        &toAddr,
+		Value:     value,
+		Data:      dataBytes,
+	})
+}
+
+// SendSelfTransfer 使用指定 Nonce 发送 0 值自转账
+// 使用 fast 档位且不受交易类型上限约束：Nonce 空洞会阻塞该 Operator 后续所有交易
+func (e *executor) SendSelfTransfer(ctx context.Context, operator *data.Operator, nonce uint64) (*ExecutionResult, error) {
+	fees, err := e.gasOracle.Suggest(ctx, gas.TierFast)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee params: %w", err)
 	}
+	tipCap, feeCap := fees.MaxPriorityFeePerGas, fees.MaxFeePerGas
+
+	self := common.HexToAddress(operator.Address)
+	return e.signAndSend(ctx, operator, &types.DynamicFeeTx{
+		ChainID:   e.chainID,
+		Nonce:     nonce,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       params.TxGas,
+		To:        &self,
+		Value:     big.NewInt(0),
+	})
+}
 
-	// 6. 解密私钥
-	// 注意：这里需要从 KMS 解密私钥
-	// TODO: 集成 KMS 解密私钥
-	// 临时实现：假设 private_key_encrypted 就是私钥（实际应该解密）
-	privateKey, err := crypto.HexToECDSA(operator.PrivateKeyEncrypted)
+// Rebroadcast 重新广播已签名交易
+func (e *executor) Rebroadcast(ctx context.Context, rawTx string) error {
+	raw, err := hexutil.Decode(rawTx)
 	if err != nil {
-		e.nonceMgr.ReleaseNonce(ctx, operator.Address, nonce)
-		return nil, fmt.Errorf("failed to parse private key: %w", err)
+		return fmt.Errorf("failed to decode raw transaction: %w", err)
+	}
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(raw); err != nil {
+		return fmt.Errorf("failed to decode raw transaction: %w", err)
 	}
 
-	// 7. 创建交易
-	rawTx := types.NewTransaction(
-		nonce,
-		toAddr,
-		value,
-		gasLimit,
-		gasPrice,
-		dataBytes,
-	)
+	if err := e.ethClient.SendTransaction(ctx, signedTx); err != nil && !isAlreadyKnown(err) {
+		return fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return nil
+}
+
+// ReplaceByFee 以相同 Nonce、更高费用重新签名并广播原交易
+// Nonce、gas limit 和上一次出价均取自交易记录，保证替换交易与原交易的载荷完全一致
+func (e *executor) ReplaceByFee(ctx context.Context, tx *data.Transaction, bumpPercent int64) (*ExecutionResult, error) {
+	if bumpPercent < 100+MinReplacementBumpPercent {
+		bumpPercent = 100 + MinReplacementBumpPercent
+	}
 
-	// 8. 签名交易
-	signer := types.NewEIP155Signer(e.chainID)
-	signedTx, err := types.SignTx(rawTx, signer, privateKey)
+	// 1. 获取原交易的 Operator（必须使用同一私钥签名）
+	operator, err := e.operatorRepo.GetByAddress(ctx, tx.FromAddress)
 	if err != nil {
-		e.nonceMgr.ReleaseNonce(ctx, operator.Address, nonce)
-		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+		return nil, fmt.Errorf("failed to get operator: %w", err)
+	}
+	if operator == nil {
+		return nil, fmt.Errorf("operator not found: %s", tx.FromAddress)
 	}
 
-	// 9. 广播交易
-	if err := e.ethClient.SendTransaction(ctx, signedTx); err != nil {
-		e.nonceMgr.ReleaseNonce(ctx, operator.Address, nonce)
-		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	// 2. 解析上一次出价
+	prevTipCap, ok := new(big.Int).SetString(tx.MaxPriorityFeePerGas, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid max priority fee per gas: %q", tx.MaxPriorityFeePerGas)
+	}
+	prevFeeCap, ok := new(big.Int).SetString(tx.MaxFeePerGas, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid max fee per gas: %q", tx.MaxFeePerGas)
 	}
 
-	// 10. 更新交易记录中的 Gas Price（字符串格式）
-	// 注意：这里不等待交易确认，交易监控器会处理确认逻辑
+	// 3. 计算新费用：max(原出价 * 涨幅, 当前 fast 档位建议值)
+	tipCap := bumpFee(prevTipCap, bumpPercent)
+	minFeeCap := bumpFee(prevFeeCap, bumpPercent)
+	feeCap := minFeeCap
+	suggested, err := e.gasOracle.Suggest(ctx, gas.TierFast)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee params: %w", err)
+	}
+	if suggested.MaxPriorityFeePerGas.Cmp(tipCap) > 0 {
+		tipCap = suggested.MaxPriorityFeePerGas
+	}
+	if suggested.MaxFeePerGas.Cmp(feeCap) > 0 {
+		feeCap = suggested.MaxFeePerGas
+	}
+	if tipCap.Cmp(feeCap) > 0 {
+		feeCap = new(big.Int).Set(tipCap)
+	}
+
+	// 4. 按交易类型的上限封顶；封顶后低于节点最小替换涨幅时不替换，原交易继续等待
+	feeCap, err = e.gasOracle.ApplyCap(tx.TransactionType, suggested.BaseFee, tipCap, feeCap)
+	if err != nil {
+		return nil, err
+	}
+	if feeCap.Cmp(minFeeCap) < 0 {
+		return nil, fmt.Errorf("%w: replacement requires max fee %s wei, cap %s wei", gas.ErrFeeCapExceeded, minFeeCap, feeCap)
+	}
+
+	// 5. 以相同 Nonce 重新签名原载荷并广播
+	toAddr, value, dataBytes, err := parsePayload(tx)
+	if err != nil {
+		return nil, err
+	}
+	return e.signAndSend(ctx, operator, &types.DynamicFeeTx{
+		ChainID:   e.chainID,
+		Nonce:     uint64(tx.Nonce),
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       uint64(tx.GasLimit),
+		To:        &toAddr,
+		Value:     value,
+		Data:      dataBytes,
+	})
+}
 
+// signAndSend 使用 Operator 的签名器签名 DynamicFeeTx 并广播
+// 节点已持有该交易（此前超时的广播实际已被接收）视为成功；
+// 广播结果不确定（超时、连接中断等，节点没有明确拒绝）时同样返回执行结果并在 SendError 中保留错误：
+// 节点可能已接收交易，Nonce 不能回收，由调用方按已广播记录，监控器确认或重新广播
+func (e *executor) signAndSend(ctx context.Context, operator *data.Operator, txData *types.DynamicFeeTx) (*ExecutionResult, error) {
+	s, err := e.signers.Signer(ctx, operator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signer: %w", err)
+	}
+
+	signedTx, err := s.SignTx(ctx, types.NewTx(txData), e.chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	sendErr := e.ethClient.SendTransaction(ctx, signedTx)
+	if sendErr != nil && isAlreadyKnown(sendErr) {
+		sendErr = nil
+	}
+	if sendErr != nil && !isAmbiguousSend(sendErr) {
+		return nil, fmt.Errorf("failed to send transaction: %w", sendErr)
+	}
+
+	result, err := newExecutionResult(signedTx)
+	if err != nil {
+		return nil, err
+	}
+	result.SendError = sendErr
+	return result, nil
+}
+
+// newExecutionResult 根据已广播的交易构建执行结果
+func newExecutionResult(signedTx *types.Transaction) (*ExecutionResult, error) {
+	raw, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
 	return &ExecutionResult{
-		TxHash: signedTx.Hash().Hex(),
+		TxHash:               signedTx.Hash().Hex(),
+		Nonce:                signedTx.Nonce(),
+		GasLimit:             signedTx.Gas(),
+		RawTx:                hexutil.Encode(raw),
+		MaxFeePerGas:         signedTx.GasFeeCap(),
+		MaxPriorityFeePerGas: signedTx.GasTipCap(),
 	}, nil
 }
 
-// EstimateGas 估算 Gas Limit
-func (e *executor) EstimateGas(ctx context.Context, tx *data.Transaction) (uint64, error) {
+// parsePayload 解析交易记录中的目标地址、Value（0x 十六进制或十进制，为空时为 0）和调用数据（hex）
+func parsePayload(tx *data.Transaction) (common.Address, *big.Int, []byte, error) {
 	toAddr := common.HexToAddress(tx.ToAddress)
+
 	var dataBytes []byte
 	if tx.Data != "" {
 		dataBytes = common.FromHex(tx.Data)
 	}
 
 	value := big.NewInt(0)
-	if tx.Value != "" && tx.Value != "0x0" {
-		value, _ = new(big.Int).SetString(tx.Value[2:], 16)
+	if tx.Value != "" {
+		var ok bool
+		if value, ok = math.ParseBig256(tx.Value); !ok || value.Sign() < 0 {
+			return common.Address{}, nil, nil, fmt.Errorf("invalid transaction value %q", tx.Value)
+		}
 	}
 
-	// 估算 Gas（使用第一个 Operator 的地址作为 from）
+	return toAddr, value, dataBytes, nil
+}
+
+// relayGasPrice Proxy Wallet 中继交易签名的 gasPrice（其他交易返回 nil）
+func relayGasPrice(tx *data.Transaction) (*big.Int, error) {
+	if tx.TransactionType != "PROXY_RELAY" {
+		return nil, nil
+	}
+	gasPrice, err := wallet.RelayCallGasPrice(common.FromHex(tx.Data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid relay call: %w", err)
+	}
+	return gasPrice, nil
+}
+
+// withRelayGasPrice 中继交易的模拟和估算以签名的 gasPrice 作为小费和费用上限（未指定费用时 tx.gasprice 为 0，relayCall 回滚）
+func withRelayGasPrice(msg *ethereum.CallMsg, tx *data.Transaction) error {
+	gasPrice, err := relayGasPrice(tx)
+	if err != nil || gasPrice == nil {
+		return err
+	}
+	msg.GasFeeCap = gasPrice
+	msg.GasTipCap = gasPrice
+	return nil
+}
+
+// bumpFee 按百分比上调费用（向上取整，保证涨幅不低于 bumpPercent）
+func bumpFee(fee *big.Int, bumpPercent int64) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(bumpPercent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// EstimateGas 估算 Gas Limit（使用第一个激活 Operator 的地址作为 from）
+func (e *executor) EstimateGas(ctx context.Context, tx *data.Transaction) (uint64, error) {
 	operators, err := e.operatorRepo.GetActiveOperators(ctx)
 	if err != nil || len(operators) == 0 {
 		return 0, fmt.Errorf("no active operators available")
 	}
-	fromAddr := common.HexToAddress(operators[0].Address)
+	return e.estimateGas(ctx, tx, common.HexToAddress(operators[0].Address))
+}
 
-	gasLimit, err := e.ethClient.EstimateGas(ctx, ethereum.CallMsg{
+// estimateGas 以指定地址作为 from 估算 Gas Limit（回滚时返回 *RevertError）
+func (e *executor) estimateGas(ctx context.Context, tx *data.Transaction, fromAddr common.Address) (uint64, error) {
+	toAddr, value, dataBytes, err := parsePayload(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	msg := ethereum.CallMsg{
 		From:  fromAddr,
 		To:    &toAddr,
 		Value: value,
 		Data:  dataBytes,
-	})
+	}
+	if err := withRelayGasPrice(&msg, tx); err != nil {
+		return 0, err
+	}
+	gasLimit, err := e.ethClient.EstimateGas(ctx, msg)
 	if err != nil {
+		if revertErr := e.revertDecoder.asRevertError(err); revertErr != nil {
+			return 0, revertErr
+		}
 		return 0, fmt.Errorf("failed to estimate gas: %w", err)
 	}
 
@@ -180,18 +453,87 @@ func (e *executor) EstimateGas(ctx context.Context, tx *data.Transaction) (uint6
 	return gasLimit, nil
 }
 
-// SelectOperator 选择可用的 Operator
-func (e *executor) SelectOperator(ctx context.Context) (*data.Operator, error) {
-	operators, err := e.operatorRepo.GetActiveOperators(ctx)
+// Simulate 模拟执行（Gas Limit 未提供时使用估算值）
+func (e *executor) Simulate(ctx context.Context, tx *data.Transaction, operator *data.Operator) error {
+	_, err := e.SimulateCall(ctx, tx, operator)
+	return err
+}
+
+// SimulateCall 模拟执行并返回返回数据（Gas Limit 未提供时使用估算值）
+func (e *executor) SimulateCall(ctx context.Context, tx *data.Transaction, operator *data.Operator) ([]byte, error) {
+	gasLimit := uint64(tx.GasLimit)
+	if gasLimit == 0 {
+		var err error
+		gasLimit, err = e.estimateGas(ctx, tx, common.HexToAddress(operator.Address))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return e.simulate(ctx, tx, operator, gasLimit)
+}
+
+// DecodeRevert 解码回滚数据
+func (e *executor) DecodeRevert(data []byte) string {
+	return e.revertDecoder.Decode(data)
+}
+
+// simulate 以 Operator 身份、指定 Gas Limit 执行 eth_call
+func (e *executor) simulate(ctx context.Context, tx *data.Transaction, operator *data.Operator, gasLimit uint64) ([]byte, error) {
+	toAddr, value, dataBytes, err := parsePayload(tx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get operators: %w", err)
+		return nil, err
 	}
-	if len(operators) == 0 {
-		return nil, fmt.Errorf("no active operators available")
+
+	msg := ethereum.CallMsg{
+		From:  common.HexToAddress(operator.Address),
+		To:    &toAddr,
+		Gas:   gasLimit,
+		Value: value,
+		Data:  dataBytes,
+	}
+	if err := withRelayGasPrice(&msg, tx); err != nil {
+		return nil, err
+	}
+	result, err := e.ethClient.PendingCallContract(ctx, msg)
+	if err != nil {
+		if revertErr := e.revertDecoder.asRevertError(err); revertErr != nil {
+			return nil, revertErr
+		}
+		return nil, fmt.Errorf("failed to simulate transaction: %w", err)
 	}
 
-	// 简单的轮询选择（TODO: 可以实现更智能的选择策略，如基于余额、负载等）
-	// 这里使用时间戳取模实现轮询
-	index := time.Now().Unix() % int64(len(operators))
-	return operators[index], nil
+	// RelayHub.relayCall 在中继请求被拒绝时不回滚，需单独调用 canRelay 判断
+	if tx.TransactionType == "PROXY_RELAY" {
+		if err := wallet.CheckRelayCall(ctx, e.ethClient, toAddr, common.HexToAddress(operator.Address), dataBytes); err != nil {
+			var rejected *wallet.RelayRejectedError
+			if errors.As(err, &rejected) {
+				return nil, &RevertError{Reason: rejected.Error()}
+			}
+			return nil, fmt.Errorf("failed to simulate transaction: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// ReplayRevert 重放交易获取回滚原因
+func (e *executor) ReplayRevert(ctx context.Context, tx *data.Transaction, blockNumber *big.Int) (*RevertError, error) {
+	toAddr, value, dataBytes, err := parsePayload(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = e.ethClient.CallContract(ctx, ethereum.CallMsg{
+		From:  common.HexToAddress(tx.FromAddress),
+		To:    &toAddr,
+		Gas:   uint64(tx.GasLimit),
+		Value: value,
+		Data:  dataBytes,
+	}, blockNumber)
+	if err == nil {
+		return nil, nil
+	}
+	if revertErr := e.revertDecoder.asRevertError(err); revertErr != nil {
+		return revertErr, nil
+	}
+	return nil, fmt.Errorf("failed to replay transaction: %w", err)
 }
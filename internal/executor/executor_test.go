@@ -0,0 +1,220 @@
+package executor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/gas"
+	"prediction-relayer-service/internal/nonce"
+	"prediction-relayer-service/internal/signer"
+	"prediction-relayer-service/internal/wallet"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestParsePayloadValue(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "0x0", want: 0},
+		{value: "0x10", want: 16},
+		{value: "10", want: 10},
+		{value: "1", want: 1},
+		{value: "0x", wantErr: true},
+		{value: "0xzz", wantErr: true},
+		{value: "-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		_, value, _, err := parsePayload(&data.Transaction{ToAddress: "0x00000000000000000000000000000000000000bb", Value: tt.value})
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("parsePayload(%q) accepted an invalid value", tt.value)
+			}
+			continue
+		}
+		if err != nil || value.Int64() != tt.want {
+			t.Fatalf("parsePayload(%q) = %v, %v; want %d", tt.value, value, err, tt.want)
+		}
+	}
+}
+
+// fakeChain 记录估算、模拟和广播的交易；canRelay 始终通过
+type fakeChain struct {
+	estimateMsg ethereum.CallMsg
+	simulateMsg ethereum.CallMsg
+	sent        *types.Transaction
+}
+
+func (f *fakeChain) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	f.sent = tx
+	return nil
+}
+
+func (f *fakeChain) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	f.estimateMsg = msg
+	return 100_000, nil
+}
+
+func (f *fakeChain) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	f.simulateMsg = msg
+	return nil, nil
+}
+
+func (f *fakeChain) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	uint256, _ := abi.NewType("uint256", "", nil)
+	bytesType, _ := abi.NewType("bytes", "", nil)
+	return abi.Arguments{{Type: uint256}, {Type: bytesType}}.Pack(big.NewInt(0), []byte{})
+}
+
+// fakeNonces 固定分配 Nonce 7，记录释放的 Nonce
+type fakeNonces struct {
+	nonce.Manager
+	released bool
+}
+
+func (f *fakeNonces) AcquireNonce(ctx context.Context, operator string) (uint64, error) {
+	return 7, nil
+}
+
+func (f *fakeNonces) ReleaseNonce(ctx context.Context, operator string, nonce uint64) error {
+	f.released = true
+	return nil
+}
+
+// fakeSigners 所有 Operator 使用同一私钥签名
+type fakeSigners struct {
+	signer.Provider
+	key *ecdsa.PrivateKey
+}
+
+func (f *fakeSigners) Signer(ctx context.Context, operator *data.Operator) (signer.Signer, error) {
+	return &fakeSigner{key: f.key}, nil
+}
+
+// fakeSigner 本地私钥签名器
+type fakeSigner struct {
+	signer.Signer
+	key *ecdsa.PrivateKey
+}
+
+func (f *fakeSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), f.key)
+}
+
+// fakeOracle 返回固定的费用建议，封顶使用真实实现
+type fakeOracle struct {
+	gas.GasOracle
+	fees *gas.Fees
+}
+
+func (f *fakeOracle) Suggest(ctx context.Context, tier gas.Tier) (*gas.Fees, error) {
+	return f.fees, nil
+}
+
+func TestExecuteRelayGasPrice(t *testing.T) {
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.GWei)) }
+	relayPrice := gwei(30)
+
+	relayCall, err := wallet.PackRelayCall(&wallet.RelayRequest{
+		From:     common.HexToAddress("0x00000000000000000000000000000000000000cc"),
+		To:       common.HexToAddress("0xaB45c5A4B0c941a2F231C04C3f49182e1A254052"),
+		Data:     common.FromHex("0x095ea7b3"),
+		TxFee:    big.NewInt(0),
+		GasPrice: relayPrice,
+		GasLimit: big.NewInt(500_000),
+		Nonce:    big.NewInt(0),
+	}, make([]byte, 65))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _ := crypto.GenerateKey()
+	operator := &data.Operator{Address: crypto.PubkeyToAddress(key.PublicKey).Hex()}
+
+	tests := []struct {
+		name       string
+		txType     string
+		fees       *gas.Fees
+		maxFee     *big.Int
+		wantTip    *big.Int
+		wantFeeCap *big.Int
+		wantErr    error
+	}{
+		// 建议小费低于签名 gasPrice：小费提高到 gasPrice，费用上限不变
+		{name: "tip below relay price", txType: "PROXY_RELAY", fees: &gas.Fees{BaseFee: gwei(25), MaxPriorityFeePerGas: gwei(1), MaxFeePerGas: gwei(51)},
+			wantTip: relayPrice, wantFeeCap: gwei(51)},
+		// 费用上限同样不低于签名 gasPrice
+		{name: "fee cap below relay price", txType: "PROXY_RELAY", fees: &gas.Fees{BaseFee: gwei(1), MaxPriorityFeePerGas: gwei(1), MaxFeePerGas: gwei(3)},
+			wantTip: relayPrice, wantFeeCap: relayPrice},
+		{name: "tip above relay price", txType: "PROXY_RELAY", fees: &gas.Fees{BaseFee: gwei(25), MaxPriorityFeePerGas: gwei(40), MaxFeePerGas: gwei(90)},
+			wantTip: gwei(40), wantFeeCap: gwei(90)},
+		// 交易类型上限低于 baseFee + 签名 gasPrice：等待费用回落
+		{name: "relay price above cap", txType: "PROXY_RELAY", fees: &gas.Fees{BaseFee: gwei(25), MaxPriorityFeePerGas: gwei(1), MaxFeePerGas: gwei(51)},
+			maxFee: gwei(40), wantErr: gas.ErrFeeCapExceeded},
+		// 非中继交易不受影响
+		{name: "other transaction", txType: "SAFE", fees: &gas.Fees{BaseFee: gwei(25), MaxPriorityFeePerGas: gwei(1), MaxFeePerGas: gwei(51)},
+			wantTip: gwei(1), wantFeeCap: gwei(51)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := &fakeChain{}
+			nonces := &fakeNonces{}
+			oracle := &fakeOracle{
+				GasOracle: gas.NewGasOracle(nil, gas.Config{MaxFee: map[string]*big.Int{tt.txType: tt.maxFee}}),
+				fees:      tt.fees,
+			}
+			exec := NewExecutor(chain, big.NewInt(137), nonces, nil, &fakeSigners{key: key}, nil, oracle)
+			tx := &data.Transaction{
+				TaskID:          "task",
+				TransactionType: tt.txType,
+				ToAddress:       "0xD216153c06E857cD7f72665E0aF1d7D82172F494",
+				Data:            hexutil.Encode(relayCall),
+			}
+
+			result, err := exec.Execute(context.Background(), tx, operator)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !nonces.released {
+					t.Fatalf("Execute err = %v (nonce released: %v); want %v", err, nonces.released, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if result.MaxPriorityFeePerGas.Cmp(tt.wantTip) != 0 || result.MaxFeePerGas.Cmp(tt.wantFeeCap) != 0 {
+				t.Fatalf("fees = tip %s, cap %s; want tip %s, cap %s", result.MaxPriorityFeePerGas, result.MaxFeePerGas, tt.wantTip, tt.wantFeeCap)
+			}
+
+			// 中继交易的估算和模拟以签名 gasPrice 出价，其他交易不指定费用
+			var wantCallPrice *big.Int
+			if tt.txType == "PROXY_RELAY" {
+				wantCallPrice = relayPrice
+			}
+			for name, msg := range map[string]ethereum.CallMsg{"estimate": chain.estimateMsg, "simulate": chain.simulateMsg} {
+				if !equalBig(msg.GasTipCap, wantCallPrice) || !equalBig(msg.GasFeeCap, wantCallPrice) {
+					t.Fatalf("%s fees = tip %v, cap %v; want %v", name, msg.GasTipCap, msg.GasFeeCap, wantCallPrice)
+				}
+			}
+		})
+	}
+}
+
+// equalBig 比较可能为 nil 的 *big.Int
+func equalBig(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Cmp(b) == 0
+}
@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Solidity 内置回滚数据选择器
+var (
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]  // require / revert("reason")
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4] // assert、算术溢出、数组越界等
+)
+
+// RevertError 模拟执行回滚（Reason 为解码后的回滚原因）
+type RevertError struct {
+	Reason string // 解码后的回滚原因（无法解码时为空）
+	Data   []byte // 原始回滚数据
+}
+
+// Error 实现 error 接口
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return "execution reverted"
+	}
+	return "execution reverted: " + e.Reason
+}
+
+// RevertDecoder 回滚数据解码器
+// 支持 Error(string)、Panic(uint256) 以及已注册 ABI 中声明的自定义错误
+type RevertDecoder struct {
+	errors map[[4]byte]abi.Error
+}
+
+// NewRevertDecoder 创建回滚数据解码器，abis 为合约 ABI（JSON），其中的 error 定义注册为可解码的自定义错误
+func NewRevertDecoder(abis ...string) (*RevertDecoder, error) {
+	d := &RevertDecoder{errors: make(map[[4]byte]abi.Error)}
+	for i, abiJSON := range abis {
+		parsed, err := abi.JSON(strings.NewReader(abiJSON))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse error abi #%d: %w", i, err)
+		}
+		for _, e := range parsed.Errors {
+			var id [4]byte
+			copy(id[:], e.ID[:4])
+			d.errors[id] = e
+		}
+	}
+	return d, nil
+}
+
+// Decode 解码回滚数据
+func (d *RevertDecoder) Decode(data []byte) string {
+	if len(data) < 4 {
+		return ""
+	}
+
+	selector := data[:4]
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			return reason
+		}
+	case bytes.Equal(selector, panicSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			return "panic: " + reason
+		}
+	default:
+		if d != nil {
+			var id [4]byte
+			copy(id[:], selector)
+			if e, ok := d.errors[id]; ok {
+				return formatCustomError(&e, data)
+			}
+		}
+	}
+	return fmt.Sprintf("unknown error %s", hexutil.Encode(selector))
+}
+
+// formatCustomError 格式化自定义错误：Name(arg1, arg2)
+func formatCustomError(e *abi.Error, data []byte) string {
+	unpacked, err := e.Unpack(data)
+	if err != nil {
+		return e.Name
+	}
+	values, _ := unpacked.([]interface{})
+	args := make([]string, len(values))
+	for i, v := range values {
+		args[i] = fmt.Sprintf("%v", v)
+	}
+	return fmt.Sprintf("%s(%s)", e.Name, strings.Join(args, ", "))
+}
+
+// asRevertError 将 eth_call / eth_estimateGas 返回的错误转换为 RevertError（非回滚错误返回 nil）
+// 节点通过 JSON-RPC 错误的 data 字段返回原始回滚数据
+func (d *RevertDecoder) asRevertError(err error) *RevertError {
+	if err == nil {
+		return nil
+	}
+
+	var revertErr *RevertError
+	if errors.As(err, &revertErr) {
+		return revertErr
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if s, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(s); decodeErr == nil && len(data) > 0 {
+				return &RevertError{Reason: d.Decode(data), Data: data}
+			}
+		}
+	}
+
+	// 部分节点不返回 data，只在错误消息中给出原因
+	msg := err.Error()
+	if i := strings.Index(msg, "execution reverted"); i >= 0 {
+		reason := strings.TrimPrefix(strings.TrimPrefix(msg[i:], "execution reverted"), ":")
+		return &RevertError{Reason: strings.TrimSpace(reason)}
+	}
+	return nil
+}
@@ -4,17 +4,22 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"strings"
+	"time"
 
 	"prediction-relayer-service/internal/data"
 )
 
 // Tracker 费用追踪器接口
 type Tracker interface {
-	// RecordFee 记录交易费用
+	// RecordFee 记录交易费用（优先使用回执中的实际成交 Gas 价格，同一交易重复记录时忽略）
 	RecordFee(ctx context.Context, tx *data.Transaction, gasUsed uint64) error
 
 	// CalculateCost 计算交易成本
 	CalculateCost(gasUsed uint64, gasPrice string) (string, error)
+
+	// GetStats 获取 Builder 在时间范围内的费用统计
+	GetStats(ctx context.Context, apiKey string, startTime, endTime time.Time) (*data.BuilderFeeStats, error)
 }
 
 // tracker 费用追踪器实现
@@ -31,8 +36,12 @@ func NewTracker(feeRepo data.BuilderFeeRepo) Tracker {
 
 // RecordFee 记录交易费用
 func (t *tracker) RecordFee(ctx context.Context, tx *data.Transaction, gasUsed uint64) error {
-	// 1. 计算总成本
-	cost, err := t.CalculateCost(gasUsed, tx.GasPrice)
+	// 1. 计算总成本（maxFeePerGas 只是出价上限，实际成交价格取自回执）
+	gasPrice := tx.GasPrice
+	if tx.EffectiveGasPrice != "" {
+		gasPrice = tx.EffectiveGasPrice
+	}
+	cost, err := t.CalculateCost(gasUsed, gasPrice)
 	if err != nil {
 		return fmt.Errorf("failed to calculate cost: %w", err)
 	}
@@ -43,7 +52,7 @@ func (t *tracker) RecordFee(ctx context.Context, tx *data.Transaction, gasUsed u
 		TransactionType: tx.TransactionType,
 		TransactionID:   tx.TaskID,
 		GasUsed:         int64(gasUsed),
-		GasPrice:        tx.GasPrice,
+		GasPrice:        gasPrice,
 		TotalCost:       cost,
 	}
 
@@ -72,14 +81,22 @@ func (t *tracker) CalculateCost(gasUsed uint64, gasPriceStr string) (string, err
 		}
 	}
 
-	// 计算成本：gasUsed * gasPrice
-	cost := new(big.Int).Mul(big.NewInt(int64(gasUsed)), gasPrice)
+	// 计算成本：gasUsed * gasPrice（wei）
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), gasPrice)
 
-	// 转换为 MATIC（除以 10^18）
+	// 转换为 MATIC（除以 10^18，保留 18 位精度并去掉末尾的 0）
 	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
-	cost = new(big.Int).Div(cost, divisor)
+	matic := new(big.Rat).SetFrac(cost, divisor).FloatString(18)
+	matic = strings.TrimSuffix(strings.TrimRight(matic, "0"), ".")
+
+	return matic, nil
+}
 
-	// 返回字符串格式（保留 6 位小数）
-	// 这里简化处理，返回整数部分
-	return cost.String(), nil
+// GetStats 获取 Builder 费用统计
+func (t *tracker) GetStats(ctx context.Context, apiKey string, startTime, endTime time.Time) (*data.BuilderFeeStats, error) {
+	stats, err := t.feeRepo.GetStatsByBuilder(ctx, apiKey, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee stats: %w", err)
+	}
+	return stats, nil
 }
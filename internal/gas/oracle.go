@@ -0,0 +1,204 @@
+package gas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ErrFeeCapExceeded 当前费用超过交易类型的 maxFeePerGas 上限，交易应等待费用回落后再广播
+var ErrFeeCapExceeded = errors.New("fee exceeds cap for transaction type")
+
+// Tier 费用档位
+type Tier int
+
+const (
+	TierSlow     Tier = iota // 低优先级
+	TierStandard             // 普通提交
+	TierFast                 // RBF 替换交易
+)
+
+// String 档位名称
+func (t Tier) String() string {
+	switch t {
+	case TierSlow:
+		return "slow"
+	case TierStandard:
+		return "standard"
+	case TierFast:
+		return "fast"
+	default:
+		return fmt.Sprintf("tier(%d)", int(t))
+	}
+}
+
+// Fees EIP-1559 费用建议
+type Fees struct {
+	BaseFee              *big.Int // 下一区块的 baseFee（wei）
+	MaxPriorityFeePerGas *big.Int // 小费（wei）
+	MaxFeePerGas         *big.Int // 2 * baseFee + 小费（可承受连续若干区块 baseFee 上涨）
+}
+
+// GasOracle Gas 价格预言机接口
+type GasOracle interface {
+	// Suggest 获取指定档位的费用建议
+	Suggest(ctx context.Context, tier Tier) (*Fees, error)
+
+	// ApplyCap 按交易类型的上限封顶 maxFeePerGas
+	// 下一区块需支付的价格（baseFee + 小费）已超过上限时返回 ErrFeeCapExceeded，否则返回不超过上限的 maxFeePerGas
+	ApplyCap(txType string, baseFee, tipCap, feeCap *big.Int) (*big.Int, error)
+}
+
+// FeeHistoryReader eth_feeHistory 查询接口（*ethclient.Client 已实现）
+type FeeHistoryReader interface {
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+}
+
+// DefaultCapKey 未单独配置上限的交易类型使用的默认上限 key
+const DefaultCapKey = "*"
+
+// Config 预言机配置
+type Config struct {
+	HistoryBlocks     uint64              // eth_feeHistory 采样区块数
+	RewardPercentiles [3]float64          // slow / standard / fast 档位的小费百分位
+	CacheTTL          time.Duration       // 费用建议缓存时间
+	TipMultiplier     int64               // 小费倍数（例如 110 = 110%，chain.gas_price_multiplier）
+	MinPriorityFee    *big.Int            // 小费下限（wei）
+	MaxFee            map[string]*big.Int // 按交易类型的 maxFeePerGas 上限（wei）
+}
+
+// oracle GasOracle 实现：按 eth_feeHistory 最近区块的小费百分位中位数给出各档位费用
+type oracle struct {
+	client FeeHistoryReader
+	cfg    Config
+
+	mu        sync.Mutex
+	tiers     [3]*Fees
+	fetchedAt time.Time
+}
+
+// NewGasOracle 创建 Gas 价格预言机
+func NewGasOracle(client FeeHistoryReader, cfg Config) GasOracle {
+	if cfg.MinPriorityFee == nil {
+		cfg.MinPriorityFee = new(big.Int)
+	}
+	return &oracle{client: client, cfg: cfg}
+}
+
+// Suggest 获取指定档位的费用建议（缓存过期时重新查询 eth_feeHistory）
+func (o *oracle) Suggest(ctx context.Context, tier Tier) (*Fees, error) {
+	if tier < TierSlow || tier > TierFast {
+		return nil, fmt.Errorf("unknown fee tier: %s", tier)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.tiers[tier] == nil || time.Since(o.fetchedAt) >= o.cfg.CacheTTL {
+		tiers, err := o.fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		o.tiers, o.fetchedAt = tiers, time.Now()
+	}
+	return copyFees(o.tiers[tier]), nil
+}
+
+// fetch 查询 eth_feeHistory 并计算各档位费用
+func (o *oracle) fetch(ctx context.Context) ([3]*Fees, error) {
+	var tiers [3]*Fees
+
+	history, err := o.client.FeeHistory(ctx, o.cfg.HistoryBlocks, nil, o.cfg.RewardPercentiles[:])
+	if err != nil {
+		return tiers, fmt.Errorf("failed to get fee history: %w", err)
+	}
+	if len(history.BaseFee) == 0 {
+		return tiers, fmt.Errorf("chain does not support EIP-1559: fee history has no base fee")
+	}
+	// BaseFee 比采样区块多一个元素，最后一个为下一区块的 baseFee
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	prevTip := new(big.Int)
+	for tier := range tiers {
+		tip := medianReward(history, tier)
+		tip.Mul(tip, big.NewInt(o.cfg.TipMultiplier))
+		tip.Div(tip, big.NewInt(100))
+		if tip.Cmp(o.cfg.MinPriorityFee) < 0 {
+			tip.Set(o.cfg.MinPriorityFee)
+		}
+		// 档位单调递增（采样区块较少时百分位中位数可能倒挂）
+		if tip.Cmp(prevTip) < 0 {
+			tip.Set(prevTip)
+		}
+		prevTip = tip
+
+		feeCap := new(big.Int).Mul(baseFee, big.NewInt(2))
+		feeCap.Add(feeCap, tip)
+		tiers[tier] = &Fees{
+			BaseFee:              new(big.Int).Set(baseFee),
+			MaxPriorityFeePerGas: tip,
+			MaxFeePerGas:         feeCap,
+		}
+	}
+	return tiers, nil
+}
+
+// medianReward 采样区块中第 index 个百分位小费的中位数（跳过空区块）
+func medianReward(history *ethereum.FeeHistory, index int) *big.Int {
+	var rewards []*big.Int
+	for i, blockRewards := range history.Reward {
+		if i < len(history.GasUsedRatio) && history.GasUsedRatio[i] == 0 {
+			continue
+		}
+		if index < len(blockRewards) && blockRewards[index] != nil {
+			rewards = append(rewards, blockRewards[index])
+		}
+	}
+	if len(rewards) == 0 {
+		return new(big.Int)
+	}
+
+	sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+	return new(big.Int).Set(rewards[len(rewards)/2])
+}
+
+// ApplyCap 按交易类型的上限封顶 maxFeePerGas
+func (o *oracle) ApplyCap(txType string, baseFee, tipCap, feeCap *big.Int) (*big.Int, error) {
+	limit, ok := o.cfg.MaxFee[txType]
+	if !ok {
+		limit, ok = o.cfg.MaxFee[DefaultCapKey]
+	}
+	if !ok || limit == nil {
+		return feeCap, nil
+	}
+
+	required := new(big.Int).Add(baseFee, tipCap)
+	if required.Cmp(limit) > 0 {
+		return nil, fmt.Errorf("%w: %s requires %s gwei, cap %s gwei", ErrFeeCapExceeded, txType, toGwei(required), toGwei(limit))
+	}
+	if feeCap.Cmp(limit) > 0 {
+		return new(big.Int).Set(limit), nil
+	}
+	return feeCap, nil
+}
+
+// copyFees 复制费用建议（调用方可能修改返回值）
+func copyFees(f *Fees) *Fees {
+	return &Fees{
+		BaseFee:              new(big.Int).Set(f.BaseFee),
+		MaxPriorityFeePerGas: new(big.Int).Set(f.MaxPriorityFeePerGas),
+		MaxFeePerGas:         new(big.Int).Set(f.MaxFeePerGas),
+	}
+}
+
+// toGwei 格式化 wei 为 gwei（保留两位小数）
+func toGwei(wei *big.Int) string {
+	return new(big.Rat).SetFrac(wei, big.NewInt(params.GWei)).FloatString(2)
+}
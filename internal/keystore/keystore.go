@@ -0,0 +1,185 @@
+package keystore
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/kms"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// KeyStore Operator 私钥存储接口
+// 私钥以密文形式保存在 operator.private_key_encrypted 中，使用前通过 KMS 解密
+type KeyStore interface {
+	// PrivateKey 获取 Operator 的私钥（解密后在内存中缓存一段时间）
+	// 返回的是缓存的副本，调用方用完后应调用 Zero 清零
+	PrivateKey(ctx context.Context, operator *data.Operator) (*ecdsa.PrivateKey, error)
+
+	// VerifyAll 校验所有激活 Operator 的私钥可以解密且与地址匹配（启动时调用）
+	VerifyAll(ctx context.Context) error
+
+	// Purge 清空缓存并将私钥内存清零
+	Purge()
+}
+
+// KeyError 单个 Operator 的私钥校验失败（VerifyAll 返回的错误中按 Operator 区分，调用方据此停用 Operator）
+type KeyError struct {
+	Operator string
+	Err      error
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("operator %s: %v", e.Operator, e.Err)
+}
+
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// cachedKey 缓存的私钥
+type cachedKey struct {
+	key       *ecdsa.PrivateKey
+	expiresAt time.Time
+}
+
+// keyStore KeyStore 实现
+type keyStore struct {
+	kms          kms.KMS
+	operatorRepo data.OperatorRepo
+	ttl          time.Duration // 缓存时间，<= 0 表示不缓存
+	log          *log.Helper
+
+	mu    sync.Mutex
+	cache map[string]*cachedKey // key: 小写 Operator 地址
+}
+
+// NewKeyStore 创建 Operator 私钥存储
+func NewKeyStore(k kms.KMS, operatorRepo data.OperatorRepo, ttl time.Duration, logger log.Logger) KeyStore {
+	return &keyStore{
+		kms:          k,
+		operatorRepo: operatorRepo,
+		ttl:          ttl,
+		log:          log.NewHelper(log.With(logger, "module", "keystore")),
+		cache:        make(map[string]*cachedKey),
+	}
+}
+
+// PrivateKey 获取 Operator 的私钥
+func (s *keyStore) PrivateKey(ctx context.Context, operator *data.Operator) (*ecdsa.PrivateKey, error) {
+	addr := strings.ToLower(operator.Address)
+	now := time.Now()
+
+	s.mu.Lock()
+	s.evictExpired(now)
+	if entry, ok := s.cache[addr]; ok {
+		defer s.mu.Unlock()
+		return copyKey(entry.key), nil
+	}
+	s.mu.Unlock()
+
+	key, err := s.decrypt(ctx, operator)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.ttl > 0 {
+		s.mu.Lock()
+		if _, ok := s.cache[addr]; !ok {
+			s.cache[addr] = &cachedKey{key: copyKey(key), expiresAt: now.Add(s.ttl)}
+		}
+		s.mu.Unlock()
+	}
+
+	return key, nil
+}
+
+// VerifyAll 校验所有激活 Operator 的私钥
+func (s *keyStore) VerifyAll(ctx context.Context) error {
+	operators, err := s.operatorRepo.GetActiveOperators(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get operators: %w", err)
+	}
+
+	var errs []error
+	for _, op := range operators {
+		key, err := s.decrypt(ctx, op)
+		if err != nil {
+			errs = append(errs, &KeyError{Operator: op.Address, Err: err})
+			continue
+		}
+		Zero(key)
+	}
+	return errors.Join(errs...)
+}
+
+// Purge 清空缓存并将私钥内存清零
+func (s *keyStore) Purge() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for addr, entry := range s.cache {
+		Zero(entry.key)
+		delete(s.cache, addr)
+	}
+}
+
+// decrypt 通过 KMS 解密 Operator 私钥，并校验与 Operator 地址一致
+func (s *keyStore) decrypt(ctx context.Context, operator *data.Operator) (*ecdsa.PrivateKey, error) {
+	plain, err := s.kms.Decrypt(ctx, operator.PrivateKeyEncrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt private key: %w", err)
+	}
+
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(plain), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	derived := crypto.PubkeyToAddress(key.PublicKey)
+	if !strings.EqualFold(derived.Hex(), operator.Address) {
+		Zero(key)
+		return nil, fmt.Errorf("private key does not match operator address: derived %s", derived.Hex())
+	}
+
+	s.log.WithContext(ctx).Debugw("msg", "operator key decrypted", "operator", operator.Address)
+	return key, nil
+}
+
+// evictExpired 清除过期缓存（调用方需持有锁）
+func (s *keyStore) evictExpired(now time.Time) {
+	for addr, entry := range s.cache {
+		if now.After(entry.expiresAt) {
+			Zero(entry.key)
+			delete(s.cache, addr)
+		}
+	}
+}
+
+// copyKey 复制私钥（缓存与调用方各持有一份，互不影响清零）
+func copyKey(key *ecdsa.PrivateKey) *ecdsa.PrivateKey {
+	return &ecdsa.PrivateKey{
+		PublicKey: key.PublicKey,
+		D:         new(big.Int).Set(key.D),
+	}
+}
+
+// Zero 将私钥标量清零
+func Zero(key *ecdsa.PrivateKey) {
+	if key == nil || key.D == nil {
+		return
+	}
+	words := key.D.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	key.D.SetInt64(0)
+}
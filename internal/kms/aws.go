@@ -0,0 +1,288 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWSConfig AWS KMS 配置（kms_config JSON）
+type AWSConfig struct {
+	Region          string `json:"region"`            // 区域，例如 ap-southeast-1
+	Endpoint        string `json:"endpoint"`          // 自定义端点（可选，默认 https://kms.<region>.amazonaws.com）
+	KeyID           string `json:"key_id"`            // 对称加密密钥 ID / ARN（Encrypt 使用）
+	AccessKeyID     string `json:"access_key_id"`     // 访问密钥（为空时读取 AWS_ACCESS_KEY_ID）
+	SecretAccessKey string `json:"secret_access_key"` // 访问密钥（为空时读取 AWS_SECRET_ACCESS_KEY）
+	SessionToken    string `json:"session_token"`     // 临时凭证 Token（为空时读取 AWS_SESSION_TOKEN）
+	Timeout         string `json:"timeout"`           // 单次请求超时（默认 10s）
+}
+
+// AsymmetricSigner 非对称签名接口（私钥不离开 KMS）
+type AsymmetricSigner interface {
+	// GetPublicKey 获取签名密钥的公钥（DER 编码的 SubjectPublicKeyInfo）
+	GetPublicKey(ctx context.Context, keyID string) ([]byte, error)
+
+	// SignDigest 对 32 字节摘要签名（ECDSA_SHA_256，返回 DER 编码签名）
+	SignDigest(ctx context.Context, keyID string, digest []byte) ([]byte, error)
+}
+
+// AWSKMS AWS KMS 实现（JSON 协议 + SigV4 签名）
+// 同时实现 KMS（对称加解密）与 AsymmetricSigner（ECC_SECG_P256K1 签名）
+type AWSKMS struct {
+	cfg        AWSConfig
+	endpoint   string
+	host       string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// awsError AWS KMS 返回的错误
+type awsError struct {
+	StatusCode int
+	Type       string `json:"__type"`
+	Message    string `json:"message"`
+}
+
+func (e *awsError) Error() string {
+	return fmt.Sprintf("aws kms returned status %d: %s: %s", e.StatusCode, e.Type, e.Message)
+}
+
+// NewAWSKMS 创建 AWS KMS 客户端（httpClient 为空时按配置超时创建）
+func NewAWSKMS(cfg AWSConfig, httpClient *http.Client) (*AWSKMS, error) {
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("aws kms region is required")
+	}
+	if cfg.AccessKeyID == "" {
+		cfg.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if cfg.SecretAccessKey == "" {
+		cfg.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if cfg.SessionToken == "" {
+		cfg.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("aws kms credentials are required")
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", cfg.Region)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aws kms endpoint: %w", err)
+	}
+
+	if httpClient == nil {
+		timeout := 10 * time.Second
+		if cfg.Timeout != "" {
+			d, err := time.ParseDuration(cfg.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid aws kms timeout: %w", err)
+			}
+			timeout = d
+		}
+		httpClient = &http.Client{Timeout: timeout}
+	}
+
+	return &AWSKMS{
+		cfg:        cfg,
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		host:       u.Host,
+		httpClient: httpClient,
+		now:        time.Now,
+	}, nil
+}
+
+// NewAWSKMSFromJSON 从 kms_config JSON 创建 AWS KMS 客户端
+func NewAWSKMSFromJSON(kmsConfig string) (*AWSKMS, error) {
+	var cfg AWSConfig
+	if err := json.Unmarshal([]byte(kmsConfig), &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse aws kms config: %w", err)
+	}
+	return NewAWSKMS(cfg, nil)
+}
+
+// Encrypt 使用对称密钥加密（返回 base64 编码的 CiphertextBlob）
+func (k *AWSKMS) Encrypt(ctx context.Context, plainData string) (string, error) {
+	if k.cfg.KeyID == "" {
+		return "", fmt.Errorf("aws kms key_id is required for encryption")
+	}
+
+	var out struct {
+		CiphertextBlob []byte `json:"CiphertextBlob"`
+	}
+	if err := k.call(ctx, "Encrypt", map[string]interface{}{
+		"KeyId":     k.cfg.KeyID,
+		"Plaintext": []byte(plainData),
+	}, &out); err != nil {
+		return "", fmt.Errorf("failed to encrypt: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(out.CiphertextBlob), nil
+}
+
+// Decrypt 解密 base64 编码的 CiphertextBlob（密钥 ID 包含在密文中）
+func (k *AWSKMS) Decrypt(ctx context.Context, encryptedData string) (string, error) {
+	blob, err := base64.StdEncoding.DecodeString(encryptedData)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode encrypted data: %w", err)
+	}
+
+	var out struct {
+		Plaintext []byte `json:"Plaintext"`
+	}
+	if err := k.call(ctx, "Decrypt", map[string]interface{}{
+		"CiphertextBlob": blob,
+	}, &out); err != nil {
+		return "", fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return string(out.Plaintext), nil
+}
+
+// GetPublicKey 获取签名密钥的公钥
+func (k *AWSKMS) GetPublicKey(ctx context.Context, keyID string) ([]byte, error) {
+	var out struct {
+		PublicKey []byte `json:"PublicKey"`
+		KeySpec   string `json:"KeySpec"`
+	}
+	if err := k.call(ctx, "GetPublicKey", map[string]interface{}{
+		"KeyId": keyID,
+	}, &out); err != nil {
+		return nil, fmt.Errorf("failed to get public key: %w", err)
+	}
+	if out.KeySpec != "" && out.KeySpec != "ECC_SECG_P256K1" {
+		return nil, fmt.Errorf("unsupported key spec: %s", out.KeySpec)
+	}
+	return out.PublicKey, nil
+}
+
+// SignDigest 对 32 字节摘要签名
+func (k *AWSKMS) SignDigest(ctx context.Context, keyID string, digest []byte) ([]byte, error) {
+	var out struct {
+		Signature []byte `json:"Signature"`
+	}
+	if err := k.call(ctx, "Sign", map[string]interface{}{
+		"KeyId":            keyID,
+		"Message":          digest,
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": "ECDSA_SHA_256",
+	}, &out); err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	return out.Signature, nil
+}
+
+// call 调用 KMS JSON 协议接口（[]byte 字段由 encoding/json 自动进行 base64 编解码）
+func (k *AWSKMS) call(ctx context.Context, action string, in interface{}, out interface{}) error {
+	payload, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, k.endpoint+"/", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	k.sign(req, payload)
+
+	resp, err := k.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call aws kms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read aws kms response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		apiErr := &awsError{StatusCode: resp.StatusCode}
+		_ = json.Unmarshal(raw, apiErr)
+		return apiErr
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to decode aws kms response: %w", err)
+	}
+	return nil
+}
+
+// sign 使用 AWS Signature Version 4 签名请求
+func (k *AWSKMS) sign(req *http.Request, payload []byte) {
+	now := k.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Host = k.host
+	req.Header.Set("X-Amz-Date", amzDate)
+	if k.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", k.cfg.SessionToken)
+	}
+
+	// 规范请求
+	headers := map[string]string{"host": k.host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	payloadHash := sha256.Sum256(payload)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	// 待签名字符串
+	scope := date + "/" + k.cfg.Region + "/kms/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	// 派生签名密钥并签名
+	signingKey := hmacSHA256([]byte("AWS4"+k.cfg.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, k.cfg.Region)
+	signingKey = hmacSHA256(signingKey, "kms")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		k.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 计算 HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
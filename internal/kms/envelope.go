@@ -0,0 +1,95 @@
+package kms
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const (
+	envelopePrefix  = "enc"
+	envelopeVersion = "v1"
+
+	// AlgAES256GCM AES-256-GCM（payload 为 12 字节 nonce + 密文）
+	AlgAES256GCM = "aes-256-gcm"
+
+	// LegacyPlaintextPrefix 显式标记的历史明文凭证前缀（plain:<明文>）
+	// 早期版本以明文保存 Builder 凭证，迁移时需先加上该前缀，再由密钥轮换加密
+	LegacyPlaintextPrefix = "plain:"
+)
+
+// Envelope 带版本的密文信封：enc:v1:<key_id>:<alg>:<base64 payload>
+// 密钥 ID 与算法随密文保存，主密钥轮换后旧密文仍可定位到对应密钥解密
+type Envelope struct {
+	KeyID     string
+	Algorithm string
+	Payload   []byte
+}
+
+// KeyRotator 支持密钥轮换的 KMS
+type KeyRotator interface {
+	// NeedsRotation 密文是否需要用当前激活密钥重新加密
+	NeedsRotation(ctx context.Context, encryptedData string) (bool, error)
+}
+
+// Rewrapper 支持在 KMS 内重新加密的 KMS（明文不离开 KMS）
+type Rewrapper interface {
+	// Rewrap 将密文重新加密到当前激活密钥
+	Rewrap(ctx context.Context, encryptedData string) (string, error)
+}
+
+// IsEnvelope 是否为信封格式密文
+func IsEnvelope(s string) bool {
+	return strings.HasPrefix(s, envelopePrefix+":")
+}
+
+// ParseLegacyPlaintext 解析显式标记的历史明文凭证（无前缀时返回 false）
+func ParseLegacyPlaintext(s string) (string, bool) {
+	if !strings.HasPrefix(s, LegacyPlaintextPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(s, LegacyPlaintextPrefix), true
+}
+
+// ParseEnvelope 解析信封格式密文
+func ParseEnvelope(s string) (*Envelope, error) {
+	parts := strings.SplitN(s, ":", 5)
+	if len(parts) != 5 || parts[0] != envelopePrefix {
+		return nil, fmt.Errorf("invalid envelope format")
+	}
+	if parts[1] != envelopeVersion {
+		return nil, fmt.Errorf("unsupported envelope version: %s", parts[1])
+	}
+	if parts[2] == "" || parts[3] == "" {
+		return nil, fmt.Errorf("envelope key id and algorithm are required")
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode envelope payload: %w", err)
+	}
+	return &Envelope{
+		KeyID:     parts[2],
+		Algorithm: parts[3],
+		Payload:   payload,
+	}, nil
+}
+
+// Header 信封头（不含 payload），同时作为 AEAD 附加数据，防止篡改密钥 ID 和算法
+func (e *Envelope) Header() string {
+	return strings.Join([]string{envelopePrefix, envelopeVersion, e.KeyID, e.Algorithm}, ":")
+}
+
+// String 编码为信封格式字符串
+func (e *Envelope) String() string {
+	return e.Header() + ":" + base64.StdEncoding.EncodeToString(e.Payload)
+}
+
+// validateKeyID 校验密钥 ID（不能包含分隔符）
+func validateKeyID(keyID string) error {
+	if keyID == "" || strings.Contains(keyID, ":") {
+		return fmt.Errorf("invalid key id: %q", keyID)
+	}
+	return nil
+}
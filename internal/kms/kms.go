@@ -6,8 +6,10 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 )
 
 // KMS 密钥管理服务接口
@@ -29,44 +31,156 @@ const (
 )
 
 // localKMS 本地 KMS 实现（使用 AES-256-GCM）
+// 支持多个主密钥：激活密钥用于加密，其余密钥仅用于解密旧密文
 type localKMS struct {
-	key []byte // 32 字节密钥（AES-256）
+	keys        map[string][]byte // key_id -> 32 字节密钥（AES-256）
+	activeKeyID string            // 加密使用的密钥
+	legacyKeyID string            // 解密无信封旧密文（base64(nonce + 密文)）使用的密钥，可为空
 }
 
+// LocalConfig 本地 KMS 多密钥配置（kms_config JSON）
+// 兼容旧配置：kms_config 为单个 base64 密钥时，等价于只有一个 ID 为 default 的密钥
+type LocalConfig struct {
+	ActiveKeyID string            `json:"active_key_id"` // 加密使用的密钥 ID
+	Keys        map[string]string `json:"keys"`          // key_id -> base64 编码的 32 字节密钥
+	LegacyKeyID string            `json:"legacy_key_id"` // 解密无信封旧密文使用的密钥 ID（可选）
+}
+
+// defaultLocalKeyID 旧配置（单个 base64 密钥）对应的密钥 ID
+const defaultLocalKeyID = "default"
+
 // NewKMS 创建 KMS 实例
 func NewKMS(kmsType, kmsConfig string) (KMS, error) {
 	switch kmsType {
 	case "local":
-		// 从配置中读取密钥（base64 编码）
-		key, err := base64.StdEncoding.DecodeString(kmsConfig)
+		// 从配置中读取密钥（多密钥 JSON 或单个 base64 编码密钥）
+		return newLocalKMS(kmsConfig)
+	case "aws-kms":
+		// 从配置中读取 AWS KMS 配置（JSON）
+		k, err := NewAWSKMSFromJSON(kmsConfig)
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode key: %w", err)
+			return nil, err
 		}
-		if len(key) != 32 {
-			return nil, fmt.Errorf("key must be 32 bytes for AES-256")
-		}
-		return &localKMS{key: key}, nil
-	case "aws-kms":
-		// TODO: 实现 AWS KMS 集成
-		return nil, fmt.Errorf("AWS KMS not implemented yet")
+		return k, nil
 	case "vault":
-		// TODO: 实现 HashiCorp Vault 集成
-		return nil, fmt.Errorf("HashiCorp Vault not implemented yet")
+		// 从配置中读取 Vault Transit 配置（JSON）
+		return newVaultKMSFromJSON(kmsConfig)
 	default:
 		return nil, fmt.Errorf("unknown KMS type: %s", kmsType)
 	}
 }
 
+// newLocalKMS 创建本地 KMS
+func newLocalKMS(kmsConfig string) (KMS, error) {
+	cfg := LocalConfig{
+		ActiveKeyID: defaultLocalKeyID,
+		Keys:        map[string]string{defaultLocalKeyID: kmsConfig},
+		LegacyKeyID: defaultLocalKeyID,
+	}
+	if strings.HasPrefix(strings.TrimSpace(kmsConfig), "{") {
+		cfg = LocalConfig{}
+		if err := json.Unmarshal([]byte(kmsConfig), &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse local kms config: %w", err)
+		}
+	}
+
+	k := &localKMS{
+		keys:        make(map[string][]byte, len(cfg.Keys)),
+		activeKeyID: cfg.ActiveKeyID,
+		legacyKeyID: cfg.LegacyKeyID,
+	}
+	for id, encoded := range cfg.Keys {
+		if err := validateKeyID(id); err != nil {
+			return nil, err
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode key %s: %w", id, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key %s must be 32 bytes for AES-256", id)
+		}
+		k.keys[id] = key
+	}
+	if _, ok := k.keys[k.activeKeyID]; !ok {
+		return nil, fmt.Errorf("active key not found: %q", k.activeKeyID)
+	}
+	if _, ok := k.keys[k.legacyKeyID]; k.legacyKeyID != "" && !ok {
+		return nil, fmt.Errorf("legacy key not found: %q", k.legacyKeyID)
+	}
+	return k, nil
+}
+
 // Decrypt 解密数据（AES-256-GCM）
+// 信封格式按其中的密钥 ID 选择密钥；无信封的旧密文使用 legacy 密钥解密
 func (k *localKMS) Decrypt(ctx context.Context, encryptedData string) (string, error) {
+	if !IsEnvelope(encryptedData) {
+		return k.decryptLegacy(encryptedData)
+	}
+
+	env, err := ParseEnvelope(encryptedData)
+	if err != nil {
+		return "", err
+	}
+	if env.Algorithm != AlgAES256GCM {
+		return "", fmt.Errorf("unsupported algorithm: %s", env.Algorithm)
+	}
+	key, ok := k.keys[env.KeyID]
+	if !ok {
+		return "", fmt.Errorf("unknown key id: %s", env.KeyID)
+	}
+
+	plaintext, err := openGCM(key, env.Payload, []byte(env.Header()))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Encrypt 加密数据（AES-256-GCM，使用激活密钥，输出信封格式）
+func (k *localKMS) Encrypt(ctx context.Context, plainData string) (string, error) {
+	env := &Envelope{KeyID: k.activeKeyID, Algorithm: AlgAES256GCM}
+
+	payload, err := sealGCM(k.keys[k.activeKeyID], []byte(plainData), []byte(env.Header()))
+	if err != nil {
+		return "", err
+	}
+	env.Payload = payload
+	return env.String(), nil
+}
+
+// NeedsRotation 密文不是信封格式，或不是由激活密钥加密时需要轮换
+func (k *localKMS) NeedsRotation(ctx context.Context, encryptedData string) (bool, error) {
+	env, err := ParseEnvelope(encryptedData)
+	if err != nil {
+		return true, nil
+	}
+	return env.KeyID != k.activeKeyID || env.Algorithm != AlgAES256GCM, nil
+}
+
+// decryptLegacy 解密无信封的旧密文（base64(nonce + 密文)）
+func (k *localKMS) decryptLegacy(encryptedData string) (string, error) {
+	if k.legacyKeyID == "" {
+		return "", fmt.Errorf("legacy ciphertext is not supported without legacy_key_id")
+	}
+
 	// 解码 base64
 	data, err := base64.StdEncoding.DecodeString(encryptedData)
 	if err != nil {
 		return "", fmt.Errorf("failed to decode encrypted data: %w", err)
 	}
 
+	plaintext, err := openGCM(k.keys[k.legacyKeyID], data, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// openGCM 解密 nonce（前 12 字节）+ 密文
+func openGCM(key, data, additionalData []byte) ([]byte, error) {
 	if len(data) < 12+16 {
-		return "", fmt.Errorf("encrypted data too short")
+		return nil, fmt.Errorf("encrypted data too short")
 	}
 
 	// 提取 nonce（前 12 字节）和密文
@@ -74,54 +188,43 @@ func (k *localKMS) Decrypt(ctx context.Context, encryptedData string) (string, e
 	ciphertext := data[12:]
 
 	// 创建 cipher
-	block, err := aes.NewCipher(k.key)
+	block, err := aes.NewCipher(key)
 	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %w", err)
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
 	}
 
 	aesGCM, err := cipher.NewGCM(block)
 	if err != nil {
-		return "", fmt.Errorf("failed to create GCM: %w", err)
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
 	}
 
 	// 解密
-	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, nil)
+	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, additionalData)
 	if err != nil {
-		return "", fmt.Errorf("failed to decrypt: %w", err)
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
 	}
-
-	return string(plaintext), nil
+	return plaintext, nil
 }
 
-// Encrypt 加密数据（AES-256-GCM）
-func (k *localKMS) Encrypt(ctx context.Context, plainData string) (string, error) {
+// sealGCM 加密并返回 nonce（12 字节）+ 密文
+func sealGCM(key, plaintext, additionalData []byte) ([]byte, error) {
 	// 创建 cipher
-	block, err := aes.NewCipher(k.key)
+	block, err := aes.NewCipher(key)
 	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %w", err)
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
 	}
 
 	aesGCM, err := cipher.NewGCM(block)
 	if err != nil {
-		return "", fmt.Errorf("failed to create GCM: %w", err)
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
 	}
 
 	// 生成 nonce（12 字节）
 	nonce := make([]byte, 12)
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", fmt.Errorf("failed to generate nonce: %w", err)
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
 	}
 
-	// 加密
-	ciphertext := aesGCM.Seal(nil, nonce, []byte(plainData), nil)
-
-	// 组合 nonce + ciphertext
-	encrypted := append(nonce, ciphertext...)
-
-	// 编码为 base64
-	return base64.StdEncoding.EncodeToString(encrypted), nil
+	// 加密并组合 nonce + ciphertext
+	return aesGCM.Seal(nonce, nonce, plaintext, additionalData), nil
 }
-
-
-
-
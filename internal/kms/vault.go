@@ -0,0 +1,476 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VaultConfig HashiCorp Vault Transit 配置（kms_config JSON）
+type VaultConfig struct {
+	Address      string `json:"address"`       // Vault 地址，例如 https://vault.example.com:8200
+	Namespace    string `json:"namespace"`     // Vault Enterprise 命名空间（可选）
+	Mount        string `json:"mount"`         // Transit 引擎挂载路径（默认 transit）
+	KeyName      string `json:"key_name"`      // Transit 密钥名
+	KeyVersion   int    `json:"key_version"`   // 加密使用的密钥版本（0 表示最新版本）
+	Token        string `json:"token"`         // Token 认证
+	RoleID       string `json:"role_id"`       // AppRole 认证 role_id
+	SecretID     string `json:"secret_id"`     // AppRole 认证 secret_id
+	AppRoleMount string `json:"approle_mount"` // AppRole 挂载路径（默认 approle）
+	Timeout      string `json:"timeout"`       // 单次请求超时（默认 10s）
+	MaxRetries   int    `json:"max_retries"`   // 临时错误最大重试次数（默认 3）
+}
+
+// vaultRenewWindow Token 剩余有效期小于该值时续期
+const vaultRenewWindow = time.Minute
+
+// vaultKeyInfoTTL Transit 密钥最新版本的缓存时间（一次轮换中逐条判断密文时不重复查询）
+const vaultKeyInfoTTL = time.Minute
+
+// vaultKMS HashiCorp Vault Transit 实现
+// 密文为 Vault 原生格式 vault:v<版本>:<base64>，解密时由 Vault 根据版本选择密钥
+type vaultKMS struct {
+	cfg        VaultConfig
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time // 零值表示不过期（例如 root token）
+	renewable   bool
+	looked      bool // 是否已查询静态 Token 的有效期
+
+	latestVersion   int       // Transit 密钥最新版本（缓存）
+	latestFetchedAt time.Time // latestVersion 的查询时间
+}
+
+// vaultError Vault 返回的错误
+type vaultError struct {
+	StatusCode int
+	Errors     []string
+}
+
+func (e *vaultError) Error() string {
+	return fmt.Sprintf("vault returned status %d: %s", e.StatusCode, strings.Join(e.Errors, "; "))
+}
+
+// vaultResponse Vault 通用响应
+type vaultResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Auth   *vaultAuth      `json:"auth"`
+	Errors []string        `json:"errors"`
+}
+
+// vaultAuth Vault 认证信息
+type vaultAuth struct {
+	ClientToken   string `json:"client_token"`
+	LeaseDuration int64  `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+// NewVaultKMS 创建 Vault Transit KMS（httpClient 为空时按配置超时创建）
+func NewVaultKMS(cfg VaultConfig, httpClient *http.Client) (KMS, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("vault address is required")
+	}
+	if cfg.KeyName == "" {
+		return nil, fmt.Errorf("vault key_name is required")
+	}
+	if cfg.Token == "" && (cfg.RoleID == "" || cfg.SecretID == "") {
+		return nil, fmt.Errorf("vault token or approle role_id/secret_id is required")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = "transit"
+	}
+	if cfg.AppRoleMount == "" {
+		cfg.AppRoleMount = "approle"
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	cfg.Address = strings.TrimRight(cfg.Address, "/")
+
+	if httpClient == nil {
+		timeout := 10 * time.Second
+		if cfg.Timeout != "" {
+			d, err := time.ParseDuration(cfg.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid vault timeout: %w", err)
+			}
+			timeout = d
+		}
+		httpClient = &http.Client{Timeout: timeout}
+	}
+
+	return &vaultKMS{
+		cfg:        cfg,
+		httpClient: httpClient,
+		token:      cfg.Token,
+	}, nil
+}
+
+// newVaultKMSFromJSON 从 kms_config JSON 创建 Vault Transit KMS
+func newVaultKMSFromJSON(kmsConfig string) (KMS, error) {
+	var cfg VaultConfig
+	if err := json.Unmarshal([]byte(kmsConfig), &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse vault config: %w", err)
+	}
+	return NewVaultKMS(cfg, nil)
+}
+
+// Encrypt 使用 Transit 密钥加密
+func (k *vaultKMS) Encrypt(ctx context.Context, plainData string) (string, error) {
+	body := map[string]interface{}{
+		"plaintext": base64.StdEncoding.EncodeToString([]byte(plainData)),
+	}
+	if k.cfg.KeyVersion > 0 {
+		body["key_version"] = k.cfg.KeyVersion
+	}
+
+	var out struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	if err := k.transit(ctx, "encrypt", body, &out); err != nil {
+		return "", fmt.Errorf("failed to encrypt: %w", err)
+	}
+	return out.Ciphertext, nil
+}
+
+// Decrypt 使用 Transit 密钥解密（密文中携带密钥版本）
+func (k *vaultKMS) Decrypt(ctx context.Context, encryptedData string) (string, error) {
+	if _, err := VaultKeyVersion(encryptedData); err != nil {
+		return "", err
+	}
+
+	var out struct {
+		Plaintext string `json:"plaintext"`
+	}
+	if err := k.transit(ctx, "decrypt", map[string]interface{}{"ciphertext": encryptedData}, &out); err != nil {
+		return "", fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	plain, err := base64.StdEncoding.DecodeString(out.Plaintext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode plaintext: %w", err)
+	}
+	return string(plain), nil
+}
+
+// Rewrap 将密文重新加密到 Transit 密钥的最新版本（明文不离开 Vault）
+func (k *vaultKMS) Rewrap(ctx context.Context, encryptedData string) (string, error) {
+	body := map[string]interface{}{"ciphertext": encryptedData}
+	if k.cfg.KeyVersion > 0 {
+		body["key_version"] = k.cfg.KeyVersion
+	}
+
+	var out struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	if err := k.transit(ctx, "rewrap", body, &out); err != nil {
+		return "", fmt.Errorf("failed to rewrap: %w", err)
+	}
+	return out.Ciphertext, nil
+}
+
+// NeedsRotation 密文的密钥版本不是加密使用的版本（配置的 key_version，未配置时为密钥的 latest_version）时需要轮换
+// 不是 Vault 密文时需要轮换（由调用方决定如何处理）
+func (k *vaultKMS) NeedsRotation(ctx context.Context, encryptedData string) (bool, error) {
+	version, err := VaultKeyVersion(encryptedData)
+	if err != nil {
+		return true, nil
+	}
+	target := k.cfg.KeyVersion
+	if target <= 0 {
+		if target, err = k.latestKeyVersion(ctx); err != nil {
+			return false, err
+		}
+	}
+	return version != target, nil
+}
+
+// latestKeyVersion 查询 Transit 密钥的最新版本（缓存 vaultKeyInfoTTL）
+func (k *vaultKMS) latestKeyVersion(ctx context.Context) (int, error) {
+	k.mu.Lock()
+	if k.latestVersion > 0 && time.Since(k.latestFetchedAt) < vaultKeyInfoTTL {
+		version := k.latestVersion
+		k.mu.Unlock()
+		return version, nil
+	}
+	k.mu.Unlock()
+
+	resp, err := k.authedRequest(ctx, http.MethodGet, fmt.Sprintf("/v1/%s/keys/%s", k.cfg.Mount, k.cfg.KeyName), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read vault key: %w", err)
+	}
+	var out struct {
+		LatestVersion int `json:"latest_version"`
+	}
+	if err := json.Unmarshal(resp.Data, &out); err != nil {
+		return 0, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	if out.LatestVersion <= 0 {
+		return 0, fmt.Errorf("vault key %s has no latest_version", k.cfg.KeyName)
+	}
+
+	k.mu.Lock()
+	k.latestVersion, k.latestFetchedAt = out.LatestVersion, time.Now()
+	k.mu.Unlock()
+	return out.LatestVersion, nil
+}
+
+// VaultKeyVersion 解析 Vault 密文中的密钥版本（vault:v<版本>:...）
+func VaultKeyVersion(ciphertext string) (int, error) {
+	parts := strings.SplitN(ciphertext, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" || !strings.HasPrefix(parts[1], "v") {
+		return 0, fmt.Errorf("invalid vault ciphertext format")
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid vault key version: %s", parts[1])
+	}
+	return version, nil
+}
+
+// transit 调用 Transit 接口
+func (k *vaultKMS) transit(ctx context.Context, op string, body interface{}, out interface{}) error {
+	path := fmt.Sprintf("/v1/%s/%s/%s", k.cfg.Mount, op, k.cfg.KeyName)
+
+	resp, err := k.authedRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	return nil
+}
+
+// authedRequest 携带 Token 发起请求：必要时续期或重新登录，Token 失效（403）时使用 AppRole 重新登录一次
+func (k *vaultKMS) authedRequest(ctx context.Context, method, path string, body interface{}) (*vaultResponse, error) {
+	token, err := k.ensureToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := k.request(ctx, method, path, token, body)
+	var vErr *vaultError
+	if errors.As(err, &vErr) && vErr.StatusCode == http.StatusForbidden && k.canLogin() {
+		if token, err = k.login(ctx); err != nil {
+			return nil, err
+		}
+		resp, err = k.request(ctx, method, path, token, body)
+	}
+	return resp, err
+}
+
+// ensureToken 返回可用 Token；剩余有效期不足 vaultRenewWindow 时续期，无法续期时使用 AppRole 重新登录
+func (k *vaultKMS) ensureToken(ctx context.Context) (string, error) {
+	k.mu.Lock()
+	token, looked := k.token, k.looked
+	k.mu.Unlock()
+
+	if token == "" {
+		return k.login(ctx)
+	}
+	if !looked {
+		// 静态 Token 首次使用时查询有效期，之后按有效期续期
+		if err := k.lookup(ctx, token); err != nil {
+			if !k.canLogin() {
+				return "", err
+			}
+			return k.login(ctx)
+		}
+	}
+
+	k.mu.Lock()
+	expiry, renewable := k.tokenExpiry, k.renewable
+	k.mu.Unlock()
+
+	if expiry.IsZero() || time.Until(expiry) > vaultRenewWindow {
+		return token, nil
+	}
+
+	if renewable {
+		err := k.renew(ctx, token)
+		if err == nil {
+			return token, nil
+		}
+		if !k.canLogin() {
+			return "", err
+		}
+	}
+	if k.canLogin() {
+		return k.login(ctx)
+	}
+	return token, nil
+}
+
+// lookup 查询静态 Token 的剩余有效期和是否可续期
+func (k *vaultKMS) lookup(ctx context.Context, token string) error {
+	resp, err := k.request(ctx, http.MethodGet, "/v1/auth/token/lookup-self", token, nil)
+	if err != nil {
+		return fmt.Errorf("failed to lookup vault token: %w", err)
+	}
+
+	var info struct {
+		TTL       int64 `json:"ttl"`
+		Renewable bool  `json:"renewable"`
+	}
+	if err := json.Unmarshal(resp.Data, &info); err != nil {
+		return fmt.Errorf("failed to decode vault token info: %w", err)
+	}
+
+	k.setToken(&vaultAuth{ClientToken: token, LeaseDuration: info.TTL, Renewable: info.Renewable})
+	return nil
+}
+
+// canLogin 是否配置了 AppRole
+func (k *vaultKMS) canLogin() bool {
+	return k.cfg.RoleID != "" && k.cfg.SecretID != ""
+}
+
+// login 使用 AppRole 登录获取 Token
+func (k *vaultKMS) login(ctx context.Context) (string, error) {
+	if !k.canLogin() {
+		return "", fmt.Errorf("vault token expired and approle is not configured")
+	}
+
+	resp, err := k.request(ctx, http.MethodPost, fmt.Sprintf("/v1/auth/%s/login", k.cfg.AppRoleMount), "", map[string]string{
+		"role_id":   k.cfg.RoleID,
+		"secret_id": k.cfg.SecretID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to login with approle: %w", err)
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("vault approle login returned no token")
+	}
+
+	k.setToken(resp.Auth)
+	return resp.Auth.ClientToken, nil
+}
+
+// renew 续期当前 Token
+func (k *vaultKMS) renew(ctx context.Context, token string) error {
+	resp, err := k.request(ctx, http.MethodPost, "/v1/auth/token/renew-self", token, map[string]string{})
+	if err != nil {
+		return fmt.Errorf("failed to renew vault token: %w", err)
+	}
+	if resp.Auth == nil {
+		return fmt.Errorf("vault token renewal returned no auth")
+	}
+	if resp.Auth.ClientToken == "" {
+		resp.Auth.ClientToken = token
+	}
+
+	k.setToken(resp.Auth)
+	return nil
+}
+
+// setToken 保存 Token 及其过期时间
+func (k *vaultKMS) setToken(auth *vaultAuth) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.token = auth.ClientToken
+	k.renewable = auth.Renewable
+	k.looked = true
+	k.tokenExpiry = time.Time{}
+	if auth.LeaseDuration > 0 {
+		k.tokenExpiry = time.Now().Add(time.Duration(auth.LeaseDuration) * time.Second)
+	}
+}
+
+// request 发起 Vault HTTP 请求，网络错误、429 和 5xx 按指数退避重试
+func (k *vaultKMS) request(ctx context.Context, method, path, token string, body interface{}) (*vaultResponse, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	backoff := 200 * time.Millisecond
+	var lastErr error
+	for attempt := 0; attempt <= k.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		resp, err := k.do(ctx, method, path, token, payload)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+		if !isRetryableVaultError(err) {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+// do 执行一次 HTTP 请求
+func (k *vaultKMS) do(ctx context.Context, method, path, token string, payload []byte) (*vaultResponse, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, k.cfg.Address+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if k.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", k.cfg.Namespace)
+	}
+
+	httpResp, err := k.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call vault: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault response: %w", err)
+	}
+
+	var resp vaultResponse
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &resp); err != nil && httpResp.StatusCode < 300 {
+			return nil, fmt.Errorf("failed to decode vault response: %w", err)
+		}
+	}
+	if httpResp.StatusCode >= 300 {
+		return nil, &vaultError{StatusCode: httpResp.StatusCode, Errors: resp.Errors}
+	}
+	return &resp, nil
+}
+
+// isRetryableVaultError 是否为临时错误（网络错误、429、5xx）
+func isRetryableVaultError(err error) bool {
+	var vErr *vaultError
+	if errors.As(err, &vErr) {
+		return vErr.StatusCode == http.StatusTooManyRequests || vErr.StatusCode >= 500
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
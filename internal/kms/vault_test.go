@@ -0,0 +1,386 @@
+package kms
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeVaultToken stand-in 中的 Token
+type fakeVaultToken struct {
+	ttl       int64
+	renewable bool
+}
+
+// fakeVault Vault stand-in：Transit（encrypt / decrypt / rewrap / keys，按版本区分密钥）、AppRole 登录和 Token lookup / renew
+type fakeVault struct {
+	mu             sync.Mutex
+	latest         int // Transit 密钥最新版本
+	tokens         map[string]*fakeVaultToken
+	roleID         string
+	secretID       string
+	loginTTL       int64 // AppRole 登录返回的 Token 有效期（秒）
+	logins         int
+	renewals       int
+	failNext       int // 接下来 N 次 Transit 请求返回 503
+	keyReads       int // 读取密钥信息（latest_version）的次数
+	namespaceSeen  string
+	issuedTokenSeq int
+}
+
+func newFakeVault(t *testing.T) (*fakeVault, *httptest.Server) {
+	f := &fakeVault{
+		latest:   1,
+		tokens:   map[string]*fakeVaultToken{},
+		roleID:   "role",
+		secretID: "secret",
+		loginTTL: 3600,
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if ns := r.Header.Get("X-Vault-Namespace"); ns != "" {
+		f.namespaceSeen = ns
+	}
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	reply := func(status int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	fail := func(status int, msg string) {
+		reply(status, map[string]interface{}{"errors": []string{msg}})
+	}
+
+	if r.URL.Path == "/v1/auth/approle/login" {
+		if body["role_id"] != f.roleID || body["secret_id"] != f.secretID {
+			fail(http.StatusBadRequest, "invalid role or secret id")
+			return
+		}
+		f.logins++
+		f.issuedTokenSeq++
+		token := fmt.Sprintf("approle-token-%d", f.issuedTokenSeq)
+		f.tokens[token] = &fakeVaultToken{ttl: f.loginTTL, renewable: true}
+		reply(http.StatusOK, map[string]interface{}{"auth": map[string]interface{}{
+			"client_token": token, "lease_duration": f.loginTTL, "renewable": true,
+		}})
+		return
+	}
+
+	token, ok := f.tokens[r.Header.Get("X-Vault-Token")]
+	if !ok {
+		fail(http.StatusForbidden, "permission denied")
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/v1/auth/token/lookup-self":
+		reply(http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"ttl": token.ttl, "renewable": token.renewable}})
+	case r.URL.Path == "/v1/auth/token/renew-self":
+		f.renewals++
+		token.ttl = 3600
+		reply(http.StatusOK, map[string]interface{}{"auth": map[string]interface{}{
+			"client_token": r.Header.Get("X-Vault-Token"), "lease_duration": token.ttl, "renewable": true,
+		}})
+	case strings.HasPrefix(r.URL.Path, "/v1/transit/"):
+		if f.failNext > 0 {
+			f.failNext--
+			fail(http.StatusServiceUnavailable, "vault is sealed")
+			return
+		}
+		f.transit(strings.TrimPrefix(r.URL.Path, "/v1/transit/"), body, reply, fail)
+	default:
+		fail(http.StatusNotFound, "unsupported path")
+	}
+}
+
+// transit 处理 Transit 请求（密文为 vault:v<版本>:base64(<版本>|明文)）
+func (f *fakeVault) transit(path string, body map[string]interface{}, reply func(int, interface{}), fail func(int, string)) {
+	encrypt := func(plaintextB64 string) string {
+		plain, _ := base64.StdEncoding.DecodeString(plaintextB64)
+		version := f.latest
+		if v, ok := body["key_version"].(float64); ok && v > 0 {
+			version = int(v)
+		}
+		return fmt.Sprintf("vault:v%d:%s", version, base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%d|%s", version, plain))))
+	}
+	decrypt := func(ciphertext string) (string, bool) {
+		version, err := VaultKeyVersion(ciphertext)
+		if err != nil || version > f.latest {
+			return "", false
+		}
+		raw, err := base64.StdEncoding.DecodeString(strings.SplitN(ciphertext, ":", 3)[2])
+		if err != nil {
+			return "", false
+		}
+		parts := strings.SplitN(string(raw), "|", 2)
+		if len(parts) != 2 || parts[0] != fmt.Sprint(version) {
+			return "", false
+		}
+		return base64.StdEncoding.EncodeToString([]byte(parts[1])), true
+	}
+
+	switch path {
+	case "keys/relayer":
+		f.keyReads++
+		reply(http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"name": "relayer", "latest_version": f.latest}})
+	case "encrypt/relayer":
+		reply(http.StatusOK, map[string]interface{}{"data": map[string]string{"ciphertext": encrypt(body["plaintext"].(string))}})
+	case "decrypt/relayer":
+		plain, ok := decrypt(body["ciphertext"].(string))
+		if !ok {
+			fail(http.StatusBadRequest, "invalid ciphertext")
+			return
+		}
+		reply(http.StatusOK, map[string]interface{}{"data": map[string]string{"plaintext": plain}})
+	case "rewrap/relayer":
+		plain, ok := decrypt(body["ciphertext"].(string))
+		if !ok {
+			fail(http.StatusBadRequest, "invalid ciphertext")
+			return
+		}
+		reply(http.StatusOK, map[string]interface{}{"data": map[string]string{"ciphertext": encrypt(plain)}})
+	default:
+		fail(http.StatusNotFound, "unknown transit path")
+	}
+}
+
+func (f *fakeVault) rotate() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.latest++
+}
+
+func (f *fakeVault) counts() (logins, renewals int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.logins, f.renewals
+}
+
+func TestVaultTransitRoundTrip(t *testing.T) {
+	fake, srv := newFakeVault(t)
+	fake.tokens["static-token"] = &fakeVaultToken{} // ttl 0：不过期（root token）
+
+	k, err := NewVaultKMS(VaultConfig{Address: srv.URL, KeyName: "relayer", Token: "static-token", Namespace: "relayer-ns"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	ciphertext, err := k.Encrypt(ctx, "0xsecret")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if v, err := VaultKeyVersion(ciphertext); err != nil || v != 1 {
+		t.Fatalf("key version = %d, %v; want 1", v, err)
+	}
+	plain, err := k.Decrypt(ctx, ciphertext)
+	if err != nil || plain != "0xsecret" {
+		t.Fatalf("Decrypt = %q, %v", plain, err)
+	}
+	if fake.namespaceSeen != "relayer-ns" {
+		t.Fatalf("namespace header = %q", fake.namespaceSeen)
+	}
+
+	if _, err := k.Decrypt(ctx, "not-a-vault-ciphertext"); err == nil {
+		t.Fatal("Decrypt accepted a non-vault ciphertext")
+	}
+}
+
+func TestVaultRewrap(t *testing.T) {
+	fake, srv := newFakeVault(t)
+	fake.tokens["static-token"] = &fakeVaultToken{}
+
+	k, err := NewVaultKMS(VaultConfig{Address: srv.URL, KeyName: "relayer", Token: "static-token"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	old, err := k.Encrypt(ctx, "0xsecret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.rotate()
+
+	rewrapped, err := k.(*vaultKMS).Rewrap(ctx, old)
+	if err != nil {
+		t.Fatalf("Rewrap: %v", err)
+	}
+	if v, _ := VaultKeyVersion(rewrapped); v != 2 {
+		t.Fatalf("rewrapped key version = %d, want 2", v)
+	}
+	for _, c := range []string{old, rewrapped} {
+		if plain, err := k.Decrypt(ctx, c); err != nil || plain != "0xsecret" {
+			t.Fatalf("Decrypt(%s) = %q, %v", c, plain, err)
+		}
+	}
+}
+
+func TestVaultNeedsRotation(t *testing.T) {
+	fake, srv := newFakeVault(t)
+	fake.tokens["static-token"] = &fakeVaultToken{}
+
+	k, err := NewVaultKMS(VaultConfig{Address: srv.URL, KeyName: "relayer", Token: "static-token"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	kr, ok := k.(KeyRotator)
+	if !ok {
+		t.Fatal("vault KMS does not implement KeyRotator")
+	}
+	if _, ok := k.(Rewrapper); !ok {
+		t.Fatal("vault KMS does not implement Rewrapper")
+	}
+
+	v1, err := k.Encrypt(ctx, "0xsecret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ { // 最新版本在缓存期内只查询一次
+		if needed, err := kr.NeedsRotation(ctx, v1); err != nil || needed {
+			t.Fatalf("NeedsRotation(latest) = %v, %v; want false", needed, err)
+		}
+	}
+	if fake.keyReads != 1 {
+		t.Fatalf("key reads = %d, want 1", fake.keyReads)
+	}
+	if needed, _ := kr.NeedsRotation(ctx, "not-a-vault-ciphertext"); !needed {
+		t.Fatal("NeedsRotation(non-vault ciphertext) = false")
+	}
+
+	// 密钥轮换后旧版本密文需要轮换（缓存过期后重新查询最新版本）
+	fake.rotate()
+	k.(*vaultKMS).latestFetchedAt = time.Time{}
+	if needed, err := kr.NeedsRotation(ctx, v1); err != nil || !needed {
+		t.Fatalf("NeedsRotation(v1 after rotate) = %v, %v; want true", needed, err)
+	}
+	v2, err := k.(Rewrapper).Rewrap(ctx, v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if needed, err := kr.NeedsRotation(ctx, v2); err != nil || needed {
+		t.Fatalf("NeedsRotation(rewrapped) = %v, %v; want false", needed, err)
+	}
+
+	// 固定加密版本时以配置版本为准，不查询最新版本
+	pinned, err := NewVaultKMS(VaultConfig{Address: srv.URL, KeyName: "relayer", Token: "static-token", KeyVersion: 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reads := fake.keyReads
+	if needed, err := pinned.(KeyRotator).NeedsRotation(ctx, v1); err != nil || needed {
+		t.Fatalf("NeedsRotation(v1, pinned to v1) = %v, %v; want false", needed, err)
+	}
+	if needed, _ := pinned.(KeyRotator).NeedsRotation(ctx, v2); !needed {
+		t.Fatal("NeedsRotation(v2, pinned to v1) = false")
+	}
+	if fake.keyReads != reads {
+		t.Fatal("pinned key version queried latest_version")
+	}
+}
+
+func TestVaultAppRoleLoginAndRenewal(t *testing.T) {
+	fake, srv := newFakeVault(t)
+	fake.loginTTL = 30 // 小于 vaultRenewWindow：下次请求前续期
+
+	k, err := NewVaultKMS(VaultConfig{Address: srv.URL, KeyName: "relayer", RoleID: "role", SecretID: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	ciphertext, err := k.Encrypt(ctx, "0xsecret")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if logins, renewals := fake.counts(); logins != 1 || renewals != 0 {
+		t.Fatalf("after first request logins=%d renewals=%d; want 1, 0", logins, renewals)
+	}
+
+	if _, err := k.Decrypt(ctx, ciphertext); err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if logins, renewals := fake.counts(); logins != 1 || renewals != 1 {
+		t.Fatalf("after renewal window logins=%d renewals=%d; want 1, 1", logins, renewals)
+	}
+
+	// 续期后有效期充足，不再续期
+	if _, err := k.Decrypt(ctx, ciphertext); err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if _, renewals := fake.counts(); renewals != 1 {
+		t.Fatalf("renewals = %d, want 1", renewals)
+	}
+}
+
+func TestVaultReloginOnRevokedToken(t *testing.T) {
+	fake, srv := newFakeVault(t)
+
+	k, err := NewVaultKMS(VaultConfig{Address: srv.URL, KeyName: "relayer", RoleID: "role", SecretID: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := k.Encrypt(ctx, "0xsecret"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Token 被吊销：请求返回 403 后使用 AppRole 重新登录一次
+	fake.mu.Lock()
+	fake.tokens = map[string]*fakeVaultToken{}
+	fake.mu.Unlock()
+	if _, err := k.Encrypt(ctx, "0xsecret"); err != nil {
+		t.Fatalf("Encrypt after revocation: %v", err)
+	}
+	if logins, _ := fake.counts(); logins != 2 {
+		t.Fatalf("logins = %d, want 2", logins)
+	}
+}
+
+func TestVaultStaticTokenWithoutAppRole(t *testing.T) {
+	fake, srv := newFakeVault(t)
+	fake.tokens["static-token"] = &fakeVaultToken{}
+
+	k, err := NewVaultKMS(VaultConfig{Address: srv.URL, KeyName: "relayer", Token: "static-token"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.mu.Lock()
+	delete(fake.tokens, "static-token")
+	fake.mu.Unlock()
+
+	if _, err := k.Encrypt(context.Background(), "0xsecret"); err == nil {
+		t.Fatal("Encrypt succeeded with a revoked token and no approle")
+	}
+}
+
+func TestVaultRetriesTransientErrors(t *testing.T) {
+	fake, srv := newFakeVault(t)
+	fake.tokens["static-token"] = &fakeVaultToken{}
+	fake.failNext = 2
+
+	k, err := NewVaultKMS(VaultConfig{Address: srv.URL, KeyName: "relayer", Token: "static-token", MaxRetries: 3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.Encrypt(context.Background(), "0xsecret"); err != nil {
+		t.Fatalf("Encrypt with transient errors: %v", err)
+	}
+}
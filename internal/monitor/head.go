@@ -0,0 +1,148 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// receiptBatchSize 单个 JSON-RPC 批量请求中的回执查询数（避免超出节点的批量请求上限）
+const receiptBatchSize = 100
+
+// subscribeNewHead 通过 WebSocket RPC 订阅新区块头（未配置 ws_url 或订阅失败时返回 nil，由轮询兜底）
+func (m *monitor) subscribeNewHead(ctx context.Context, heads chan<- *types.Header) ethereum.Subscription {
+	if m.wsURL == "" {
+		return nil
+	}
+
+	if m.wsClient == nil {
+		client, err := ethclient.DialContext(ctx, m.wsURL)
+		if err != nil {
+			m.logger.Log(log.LevelWarn, "msg", "failed to connect to websocket rpc, polling instead", "error", err)
+			return nil
+		}
+		m.wsClient = client
+	}
+
+	sub, err := m.wsClient.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		m.logger.Log(log.LevelWarn, "msg", "failed to subscribe new heads, polling instead", "error", err)
+		m.wsClient.Close()
+		m.wsClient = nil
+		return nil
+	}
+
+	m.logger.Log(log.LevelInfo, "msg", "subscribed to new heads")
+	return sub
+}
+
+// unsubscribe 取消订阅并关闭 WebSocket 连接（下次订阅时重新建立连接）
+func (m *monitor) unsubscribe(sub ethereum.Subscription) {
+	if sub != nil {
+		sub.Unsubscribe()
+	}
+	if m.wsClient != nil {
+		m.wsClient.Close()
+		m.wsClient = nil
+	}
+}
+
+// subscriptionErr 订阅的错误通道（未订阅时返回 nil 通道，select 中永远阻塞）
+func subscriptionErr(sub ethereum.Subscription) <-chan error {
+	if sub == nil {
+		return nil
+	}
+	return sub.Err()
+}
+
+// checkNewHead 新区块到达：找出区块中包含的待确认交易，批量查询回执并处理，然后推进已打包交易的确认
+// 区块和回执从推送新区块头的 WebSocket 节点查询，避免 HTTP 节点尚未同步到该区块
+func (m *monitor) checkNewHead(ctx context.Context, header *types.Header) error {
+	if m.wsClient == nil {
+		return nil
+	}
+
+	pending, err := m.loadPending(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(pending.txs) > 0 {
+		var block struct {
+			Transactions []common.Hash `json:"transactions"`
+		}
+		if err := m.wsClient.Client().CallContext(ctx, &block, "eth_getBlockByHash", header.Hash(), false); err != nil {
+			return fmt.Errorf("failed to get block: %w", err)
+		}
+
+		included := make(map[common.Hash]bool, len(block.Transactions))
+		for _, hash := range block.Transactions {
+			included[hash] = true
+		}
+		var relevant []common.Hash
+		for _, hash := range pending.hashes() {
+			if included[hash] {
+				relevant = append(relevant, hash)
+			}
+		}
+
+		if len(relevant) > 0 {
+			receipts, err := fetchReceipts(ctx, m.wsClient.Client(), relevant)
+			if err != nil {
+				return fmt.Errorf("failed to fetch receipts: %w", err)
+			}
+			for _, tx := range pending.txs {
+				if tx.TxHash == "" {
+					continue
+				}
+				if receipt := findReceipt(tx, pending.attempts[tx.TaskID], receipts); receipt != nil {
+					if err := m.processReceipt(ctx, tx, receipt); err != nil {
+						m.logger.Log(log.LevelError, "msg", "failed to process transaction receipt", "task_id", tx.TaskID, "error", err)
+					}
+				}
+			}
+		}
+	}
+
+	return m.monitorMinedTransactions(ctx)
+}
+
+// fetchReceipts 通过 JSON-RPC 批量请求查询交易回执，返回已上链交易的回执（未上链的交易不在结果中）
+func fetchReceipts(ctx context.Context, client BatchCaller, hashes []common.Hash) (map[common.Hash]*types.Receipt, error) {
+	receipts := make(map[common.Hash]*types.Receipt, len(hashes))
+	for start := 0; start < len(hashes); start += receiptBatchSize {
+		end := start + receiptBatchSize
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+
+		batch := make([]rpc.BatchElem, end-start)
+		results := make([]*types.Receipt, end-start)
+		for i, hash := range hashes[start:end] {
+			batch[i] = rpc.BatchElem{
+				Method: "eth_getTransactionReceipt",
+				Args:   []interface{}{hash},
+				Result: &results[i],
+			}
+		}
+		if err := client.BatchCallContext(ctx, batch); err != nil {
+			return nil, err
+		}
+
+		for i, elem := range batch {
+			if elem.Error != nil {
+				return nil, fmt.Errorf("failed to get receipt %s: %w", hashes[start+i].Hex(), elem.Error)
+			}
+			if results[i] != nil {
+				receipts[hashes[start+i]] = results[i]
+			}
+		}
+	}
+	return receipts, nil
+}
@@ -2,20 +2,39 @@ package monitor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
 
 	"prediction-relayer-service/internal/data"
 	"prediction-relayer-service/internal/executor"
+	"prediction-relayer-service/internal/fee"
+	"prediction-relayer-service/internal/gas"
+	"prediction-relayer-service/internal/wallet"
 
-	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/go-kratos/kratos/v2/log"
 )
 
+// pollInterval 轮询间隔（RBF、超时处理，以及未订阅新区块头时的回执检查）
+const pollInterval = 10 * time.Second
+
+// BatchCaller JSON-RPC 批量请求接口（*rpc.Client 和 *rpcpool.Pool 已实现）
+type BatchCaller interface {
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+// ChainClient 链上查询接口（*rpcpool.Pool 已实现）
+type ChainClient interface {
+	BatchCaller
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
 // Monitor 交易监控器接口
 type Monitor interface {
 	// Start 启动监控器
@@ -27,51 +46,86 @@ type Monitor interface {
 
 // monitor 交易监控器实现
 type monitor struct {
-	ethClient      *ethclient.Client
+	ethClient      ChainClient
 	txRepo         data.TransactionRepo
+	attemptRepo    data.TransactionAttemptRepo
 	executor       executor.Executor
+	feeTracker     fee.Tracker
 	logger         log.Logger
 	pendingTimeout time.Duration // Pending 交易超时时间（默认 30 秒）
-	rbfThreshold   time.Duration // RBF 触发阈值（默认 30 秒）
+	rbfThreshold   time.Duration // RBF 触发阈值（默认 30 秒，从最近一次广播开始计时）
+	rbfBumpPercent int64         // RBF 最小费用涨幅（节点最小替换涨幅，实际出价不低于 Gas 预言机 fast 档位）
+	confirmations  int64         // 确认深度（打包区块之后的区块数）
+	wsURL          string        // WebSocket RPC 地址（为空时仅轮询）
+	wsClient       *ethclient.Client
 	stopCh         chan struct{}
 }
 
 // NewMonitor 创建交易监控器
 func NewMonitor(
-	ethClient *ethclient.Client,
+	ethClient ChainClient,
 	txRepo data.TransactionRepo,
+	attemptRepo data.TransactionAttemptRepo,
 	exec executor.Executor,
+	feeTracker fee.Tracker,
 	logger log.Logger,
 	pendingTimeout time.Duration,
+	confirmations int64,
+	wsURL string,
 ) Monitor {
 	return &monitor{
 		ethClient:      ethClient,
 		txRepo:         txRepo,
+		attemptRepo:    attemptRepo,
 		executor:       exec,
+		feeTracker:     feeTracker,
 		logger:         logger,
 		pendingTimeout: pendingTimeout,
 		rbfThreshold:   pendingTimeout,
+		rbfBumpPercent: 100 + executor.MinReplacementBumpPercent,
+		confirmations:  confirmations,
+		wsURL:          wsURL,
 		stopCh:         make(chan struct{}),
 	}
 }
 
 // Start 启动监控器
+// 配置了 WebSocket RPC 时订阅新区块头，每个新区块到达即检查其中包含的待确认交易；
+// 10 秒轮询始终运行，负责 RBF、超时处理及补漏，订阅断开时由轮询兜底并定期重新订阅
 func (m *monitor) Start(ctx context.Context) error {
-	m.logger.Log(log.LevelInfo, "msg", "starting transaction monitor")
+	m.logger.Log(log.LevelInfo, "msg", "starting transaction monitor", "subscribe_new_heads", m.wsURL != "")
 
-	ticker := time.NewTicker(10 * time.Second) // 每 10 秒检查一次
+	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
+	heads := make(chan *types.Header, 16)
+	sub := m.subscribeNewHead(ctx, heads)
+	defer m.unsubscribe(sub)
+
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-m.stopCh:
 			return nil
+		case err := <-subscriptionErr(sub):
+			m.logger.Log(log.LevelWarn, "msg", "new head subscription dropped, falling back to polling", "error", err)
+			m.unsubscribe(sub)
+			sub = nil
+		case header := <-heads:
+			if err := m.checkNewHead(ctx, header); err != nil {
+				m.logger.Log(log.LevelError, "msg", "failed to check new head", "block_number", header.Number, "error", err)
+			}
 		case <-ticker.C:
+			if sub == nil {
+				sub = m.subscribeNewHead(ctx, heads)
+			}
 			if err := m.monitorPendingTransactions(ctx); err != nil {
 				m.logger.Log(log.LevelError, "msg", "failed to monitor pending transactions", "error", err)
 			}
+			if err := m.monitorMinedTransactions(ctx); err != nil {
+				m.logger.Log(log.LevelError, "msg", "failed to monitor mined transactions", "error", err)
+			}
 		}
 	}
 }
@@ -82,44 +136,47 @@ func (m *monitor) Stop() {
 }
 
 // monitorPendingTransactions 监控 Pending 交易
+// 批量查询所有待确认交易（含替换交易）的回执，未上链且距最近一次广播超过阈值的交易执行 RBF
 func (m *monitor) monitorPendingTransactions(ctx context.Context) error {
-	// 1. 获取所有 Pending 交易
-	txs, err := m.txRepo.GetPendingTransactions(ctx, 100)
+	// 1. 获取所有 Pending 交易及其广播记录
+	pending, err := m.loadPending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending.txs) == 0 {
+		return nil
+	}
+
+	// 2. 批量查询回执
+	receipts, err := fetchReceipts(ctx, m.ethClient, pending.hashes())
 	if err != nil {
-		return fmt.Errorf("failed to get pending transactions: %w", err)
+		return fmt.Errorf("failed to fetch receipts: %w", err)
 	}
 
 	now := time.Now()
-	for _, tx := range txs {
-		// 2. 检查交易是否超时
-		elapsed := now.Sub(tx.CreatedAt)
-		if elapsed > m.rbfThreshold {
-			// 3. 检查交易是否已确认
-			if tx.TxHash != "" {
-				confirmed, err := m.checkTransactionConfirmed(ctx, tx.TxHash)
-				if err != nil {
-					m.logger.Log(log.LevelError, "msg", "failed to check transaction confirmation", "tx_hash", tx.TxHash, "error", err)
-					continue
-				}
-				if confirmed {
-					// 交易已确认，更新状态
-					if err := m.txRepo.UpdateStatus(ctx, tx.TaskID, "MINED"); err != nil {
-						m.logger.Log(log.LevelError, "msg", "failed to update transaction status", "task_id", tx.TaskID, "error", err)
-					}
-					continue
-				}
-
-				// 4. 执行 RBF（Replace By Fee）
-				if err := m.replaceByFee(ctx, tx); err != nil {
-					m.logger.Log(log.LevelError, "msg", "failed to replace by fee", "task_id", tx.TaskID, "error", err)
-				}
-			} else {
-				// 5. 如果超过 5 分钟未确认，标记为失败
-				if elapsed > 5*time.Minute {
-					if err := m.txRepo.UpdateStatus(ctx, tx.TaskID, "FAILED"); err != nil {
-						m.logger.Log(log.LevelError, "msg", "failed to update transaction status to failed", "task_id", tx.TaskID, "error", err)
-					}
-				}
+	for _, tx := range pending.txs {
+		// PENDING 交易均已广播（未广播的任务由队列处理，失败时记录错误信息）
+		if tx.TxHash == "" {
+			continue
+		}
+
+		// 3. 交易（含所有替换交易）已上链，归属到原任务 ID 并处理回执
+		attempts := pending.attempts[tx.TaskID]
+		if receipt := findReceipt(tx, attempts, receipts); receipt != nil {
+			if err := m.processReceipt(ctx, tx, receipt); err != nil {
+				m.logger.Log(log.LevelError, "msg", "failed to process transaction receipt", "task_id", tx.TaskID, "error", err)
+			}
+			continue
+		}
+
+		// 4. 距最近一次广播超过阈值，执行 RBF（Replace By Fee）
+		lastBroadcast := tx.CreatedAt
+		if len(attempts) > 0 {
+			lastBroadcast = attempts[len(attempts)-1].CreatedAt
+		}
+		if now.Sub(lastBroadcast) > m.rbfThreshold {
+			if err := m.replaceByFee(ctx, tx, attempts); err != nil {
+				m.logger.Log(log.LevelError, "msg", "failed to replace by fee", "task_id", tx.TaskID, "error", err)
 			}
 		}
 	}
@@ -127,60 +184,161 @@ func (m *monitor) monitorPendingTransactions(ctx context.Context) error {
 	return nil
 }
 
-// checkTransactionConfirmed 检查交易是否已确认
-func (m *monitor) checkTransactionConfirmed(ctx context.Context, txHash string) (bool, error) {
-	hash := common.HexToHash(txHash)
-	receipt, err := m.ethClient.TransactionReceipt(ctx, hash)
+// pendingSet 待确认交易及其广播记录
+type pendingSet struct {
+	txs      []*data.Transaction
+	attempts map[string][]*data.TransactionAttempt // task_id -> 广播记录（按尝试序号升序）
+}
+
+// loadPending 获取 Pending 交易及其广播记录
+func (m *monitor) loadPending(ctx context.Context) (*pendingSet, error) {
+	txs, err := m.txRepo.GetPendingTransactions(ctx, 100)
 	if err != nil {
-		if err == ethereum.NotFound {
-			return false, nil
+		return nil, fmt.Errorf("failed to get pending transactions: %w", err)
+	}
+
+	taskIDs := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		if tx.TxHash != "" {
+			taskIDs = append(taskIDs, tx.TaskID)
 		}
-		return false, err
 	}
+	attempts, err := m.attemptRepo.GetByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction attempts: %w", err)
+	}
+
+	set := &pendingSet{txs: txs, attempts: make(map[string][]*data.TransactionAttempt, len(taskIDs))}
+	for _, attempt := range attempts {
+		set.attempts[attempt.TaskID] = append(set.attempts[attempt.TaskID], attempt)
+	}
+	return set, nil
+}
 
-	// 交易已确认
-	if receipt.Status == types.ReceiptStatusSuccessful {
-		// 更新 Gas Used 和 Block Number
-		// TODO: 需要获取 Transaction 对象来更新
-		return true, nil
+// hashes 所有待确认交易（含替换交易）的哈希
+func (s *pendingSet) hashes() []common.Hash {
+	var hashes []common.Hash
+	for _, tx := range s.txs {
+		if tx.TxHash == "" {
+			continue
+		}
+		hashes = append(hashes, common.HexToHash(tx.TxHash))
+		for _, attempt := range s.attempts[tx.TaskID] {
+			if attempt.TxHash != tx.TxHash {
+				hashes = append(hashes, common.HexToHash(attempt.TxHash))
+			}
+		}
 	}
+	return hashes
+}
 
-	return false, nil
+// findReceipt 在已查询的回执中查找原始交易或任一替换交易的回执（均未上链时返回 nil）
+// status = 0 的回执同样表示交易已上链、Nonce 已消耗，不能再替换
+func findReceipt(tx *data.Transaction, attempts []*data.TransactionAttempt, receipts map[common.Hash]*types.Receipt) *types.Receipt {
+	if receipt, ok := receipts[common.HexToHash(tx.TxHash)]; ok {
+		return receipt
+	}
+	for _, attempt := range attempts {
+		if receipt, ok := receipts[common.HexToHash(attempt.TxHash)]; ok {
+			return receipt
+		}
+	}
+	return nil
 }
 
-// replaceByFee 执行 RBF（Replace By Fee）
-func (m *monitor) replaceByFee(ctx context.Context, tx *data.Transaction) error {
-	m.logger.Log(log.LevelInfo, "msg", "replacing transaction by fee", "task_id", tx.TaskID, "tx_hash", tx.TxHash)
+// processReceipt 处理回执：记录 Gas 消耗、区块号、区块哈希和实际成交 Gas 价格
+// 执行成功的交易置为 MINED（达到确认深度后再写入 Builder 费用记录），执行失败（status = 0）的交易置为 FAILED 并记录失败原因
+// Proxy Wallet 中继交易被 RelayHub 拒绝或目标调用失败时（回执 status = 1）同样置为 FAILED
+func (m *monitor) processReceipt(ctx context.Context, tx *data.Transaction, receipt *types.Receipt) error {
+	result := &data.TransactionReceipt{
+		TxHash:      receipt.TxHash.Hex(),
+		Status:      "MINED",
+		BlockNumber: receipt.BlockNumber.Int64(),
+		BlockHash:   receipt.BlockHash.Hex(),
+		GasUsed:     int64(receipt.GasUsed),
+	}
+	if receipt.EffectiveGasPrice != nil {
+		result.EffectiveGasPrice = receipt.EffectiveGasPrice.String()
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		result.Status = "FAILED"
+		result.ErrorMessage = m.failureReason(ctx, tx, receipt)
+	} else if tx.TransactionType == "PROXY_RELAY" {
+		// relayCall 在 canRelay 不通过或目标调用失败时不回滚，根据 RelayHub 事件判断中继调用是否执行
+		if err := wallet.CheckRelayLogs(receipt.Logs, common.HexToAddress(tx.ToAddress)); err != nil {
+			result.Status = "FAILED"
+			result.ErrorMessage = executor.FormatError(executor.CategoryExecutionReverted, err)
+		}
+	}
 
-	// 1. 获取当前 Gas Price（增加 20%）
-	gasPrice, err := m.ethClient.SuggestGasPrice(ctx)
+	applied, err := m.attemptRepo.RecordReceipt(ctx, tx.TaskID, result)
 	if err != nil {
-		return fmt.Errorf("failed to get gas price: %w", err)
+		return fmt.Errorf("failed to record receipt: %w", err)
+	}
+	if !applied {
+		return nil
 	}
-	newGasPrice := new(big.Int).Mul(gasPrice, big.NewInt(120))
-	newGasPrice = new(big.Int).Div(newGasPrice, big.NewInt(100))
 
-	// 2. 获取 Operator（用于后续创建替换交易）
-	_, err = m.executor.SelectOperator(ctx)
+	m.logger.Log(log.LevelInfo, "msg", "transaction receipt processed", "task_id", tx.TaskID, "tx_hash", result.TxHash,
+		"status", result.Status, "block_number", result.BlockNumber, "gas_used", result.GasUsed, "error", result.ErrorMessage)
+	return nil
+}
+
+// failureReason 获取执行失败的原因：在上一区块状态上重放交易解码回滚原因，无法重放时根据 Gas 消耗判断是否耗尽 Gas
+func (m *monitor) failureReason(ctx context.Context, tx *data.Transaction, receipt *types.Receipt) string {
+	parent := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	revertErr, err := m.executor.ReplayRevert(ctx, tx, parent)
 	if err != nil {
-		return fmt.Errorf("failed to select operator: %w", err)
+		m.logger.Log(log.LevelWarn, "msg", "failed to replay failed transaction", "task_id", tx.TaskID, "error", err)
+	}
+	if revertErr != nil {
+		return executor.FormatError(executor.CategoryExecutionReverted, revertErr)
 	}
+	if tx.GasLimit > 0 && receipt.GasUsed >= uint64(tx.GasLimit) {
+		return executor.FormatError(executor.CategoryExecutionReverted, fmt.Errorf("out of gas"))
+	}
+	return executor.FormatError(executor.CategoryExecutionReverted, fmt.Errorf("execution reverted"))
+}
 
-	// 3. 创建新的交易（相同 Nonce，更高 Gas Price）
-	// 注意：这里需要从原交易获取 Nonce
-	// TODO: 实现从原交易获取 Nonce 并创建替换交易
-	// 这里简化处理，实际应该：
-	// - 获取原交易的 Nonce
-	// - 使用相同的 Nonce 创建新交易
-	// - Gas Price 提高 20%
-	// - 广播新交易
+// replaceByFee 执行 RBF（Replace By Fee）
+// 以相同 Nonce 和 Operator 私钥重新签名原交易并广播，任务保持 PENDING，替换记录追加到替换链
+func (m *monitor) replaceByFee(ctx context.Context, tx *data.Transaction, attempts []*data.TransactionAttempt) error {
+	m.logger.Log(log.LevelInfo, "msg", "replacing transaction by fee", "task_id", tx.TaskID, "tx_hash", tx.TxHash)
 
-	// 4. 更新原交易状态为 REPLACED
-	if err := m.txRepo.UpdateStatus(ctx, tx.TaskID, "REPLACED"); err != nil {
-		return fmt.Errorf("failed to update transaction status: %w", err)
+	// 1. 重新签名并广播替换交易
+	result, err := m.executor.ReplaceByFee(ctx, tx, m.rbfBumpPercent)
+	if err != nil {
+		if errors.Is(err, gas.ErrFeeCapExceeded) {
+			// 替换费用超过交易类型上限：不加价，原交易继续等待
+			m.logger.Log(log.LevelInfo, "msg", "replacement fee above cap, waiting", "task_id", tx.TaskID, "error", err)
+			return nil
+		}
+		return fmt.Errorf("failed to broadcast replacement: %w", err)
+	}
+	if result.SendError != nil {
+		// 广播结果不确定：替换交易可能已被节点接收，同样记录到替换链
+		m.logger.Log(log.LevelWarn, "msg", "replacement broadcast is ambiguous", "task_id", tx.TaskID, "tx_hash", result.TxHash, "error", result.SendError)
+	}
+
+	// 2. 记录替换交易，并将任务指向新交易哈希（第 0 次尝试为原始交易）
+	attemptNo := int32(len(attempts))
+	if attemptNo == 0 {
+		attemptNo = 1
+	}
+	if err := m.attemptRepo.Replace(ctx, &data.TransactionAttempt{
+		TaskID:               tx.TaskID,
+		TxHash:               result.TxHash,
+		Attempt:              attemptNo,
+		Nonce:                int64(result.Nonce),
+		MaxFeePerGas:         result.MaxFeePerGas.String(),
+		MaxPriorityFeePerGas: result.MaxPriorityFeePerGas.String(),
+		RawTx:                result.RawTx,
+		Status:               "BROADCAST",
+	}); err != nil {
+		return fmt.Errorf("failed to record replacement: %w", err)
 	}
 
-	m.logger.Log(log.LevelInfo, "msg", "transaction replaced by fee", "task_id", tx.TaskID, "new_gas_price", newGasPrice.String())
+	m.logger.Log(log.LevelInfo, "msg", "transaction replaced by fee", "task_id", tx.TaskID, "old_tx_hash", tx.TxHash, "new_tx_hash", result.TxHash, "max_fee_per_gas", result.MaxFeePerGas.String())
 
 	return nil
 }
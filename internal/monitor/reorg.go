@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"prediction-relayer-service/internal/data"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// monitorMinedTransactions 跟踪已打包交易：打包区块仍在规范链上且达到确认深度时置为 CONFIRMED 并写入 Builder 费用记录，
+// 打包区块已不在规范链上（链重组）时回退为 PENDING 并重新广播
+// 执行失败（FAILED）的交易在达到确认深度前同样检测链重组，达到确认深度后保持 FAILED
+func (m *monitor) monitorMinedTransactions(ctx context.Context) error {
+	head, err := m.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get block number: %w", err)
+	}
+
+	txs, err := m.txRepo.GetMinedTransactions(ctx, int64(head)-m.confirmations, 100)
+	if err != nil {
+		return fmt.Errorf("failed to get mined transactions: %w", err)
+	}
+
+	// 同一区块的交易只查询一次规范链区块哈希
+	canonical := make(map[int64]common.Hash)
+	for _, tx := range txs {
+		if tx.BlockNumber == nil {
+			continue
+		}
+		blockNumber := *tx.BlockNumber
+
+		hash, ok := canonical[blockNumber]
+		if !ok {
+			header, err := m.ethClient.HeaderByNumber(ctx, big.NewInt(blockNumber))
+			if err != nil {
+				m.logger.Log(log.LevelError, "msg", "failed to get block header", "block_number", blockNumber, "error", err)
+				continue
+			}
+			hash = header.Hash()
+			canonical[blockNumber] = hash
+		}
+
+		if hash != common.HexToHash(tx.BlockHash) {
+			if err := m.handleReorg(ctx, tx, hash); err != nil {
+				m.logger.Log(log.LevelError, "msg", "failed to handle chain reorganization", "task_id", tx.TaskID, "error", err)
+			}
+			continue
+		}
+
+		if tx.Status == "MINED" && int64(head)-blockNumber >= m.confirmations {
+			if err := m.confirm(ctx, tx); err != nil {
+				m.logger.Log(log.LevelError, "msg", "failed to confirm transaction", "task_id", tx.TaskID, "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+// confirm 写入 Builder 费用记录（按实际成交 Gas 价格计算）后将交易置为 CONFIRMED
+// 费用记录按交易幂等（重复写入时忽略），先于状态更新写入：置为 CONFIRMED 后交易不再被跟踪，
+// 若先更新状态再写入费用，费用写入失败时记录会永久丢失；置为 CONFIRMED 失败时下一轮重试，不会重复记账
+func (m *monitor) confirm(ctx context.Context, tx *data.Transaction) error {
+	var gasUsed uint64
+	if tx.GasUsed != nil {
+		gasUsed = uint64(*tx.GasUsed)
+	}
+	if err := m.feeTracker.RecordFee(ctx, tx, gasUsed); err != nil {
+		return fmt.Errorf("failed to record fee: %w", err)
+	}
+
+	confirmed, err := m.txRepo.MarkConfirmed(ctx, tx.TaskID, tx.BlockHash)
+	if err != nil {
+		return fmt.Errorf("failed to mark transaction confirmed: %w", err)
+	}
+	if confirmed {
+		m.logger.Log(log.LevelInfo, "msg", "transaction confirmed", "task_id", tx.TaskID, "tx_hash", tx.TxHash, "block_number", *tx.BlockNumber)
+	}
+	return nil
+}
+
+// handleReorg 打包区块被链重组移出规范链：撤销回执、任务回退为 PENDING，并重新广播上链的那笔交易
+// 重组后交易可能已被打包进新区块，后续由 Pending 监控重新获取回执；同 Nonce 的其他替换交易上链同样会被识别
+func (m *monitor) handleReorg(ctx context.Context, tx *data.Transaction, canonical common.Hash) error {
+	m.logger.Log(log.LevelWarn, "msg", "chain reorganization detected", "task_id", tx.TaskID, "tx_hash", tx.TxHash,
+		"block_number", *tx.BlockNumber, "block_hash", tx.BlockHash, "canonical_hash", canonical.Hex())
+
+	rolledBack, err := m.attemptRepo.RollbackReceipt(ctx, tx.TaskID, tx.BlockHash)
+	if err != nil {
+		return fmt.Errorf("failed to rollback receipt: %w", err)
+	}
+	if !rolledBack {
+		return nil
+	}
+
+	attempt, err := m.attemptRepo.GetByTxHash(ctx, tx.TxHash)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction attempt: %w", err)
+	}
+	if attempt == nil {
+		return fmt.Errorf("transaction attempt not found: %s", tx.TxHash)
+	}
+	if err := m.executor.Rebroadcast(ctx, attempt.RawTx); err != nil {
+		// 交易可能已被重新打包或被同 Nonce 交易取代，任务已回退为 PENDING，由 Pending 监控继续跟踪
+		m.logger.Log(log.LevelWarn, "msg", "failed to rebroadcast reorganized transaction", "task_id", tx.TaskID, "tx_hash", tx.TxHash, "error", err)
+		return nil
+	}
+
+	m.logger.Log(log.LevelInfo, "msg", "reorganized transaction rebroadcast", "task_id", tx.TaskID, "tx_hash", tx.TxHash)
+	return nil
+}
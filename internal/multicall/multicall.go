@@ -0,0 +1,82 @@
+package multicall
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultAddress Multicall3 在各 EVM 链上的标准部署地址
+const DefaultAddress = "0xcA11bde05977b3631167028862bE2a173976CA11"
+
+// aggregate3ABI Multicall3.aggregate3 的 ABI
+const aggregate3ABI = `[{
+	"name": "aggregate3",
+	"type": "function",
+	"stateMutability": "payable",
+	"inputs": [{
+		"name": "calls",
+		"type": "tuple[]",
+		"components": [
+			{"name": "target", "type": "address"},
+			{"name": "allowFailure", "type": "bool"},
+			{"name": "callData", "type": "bytes"}
+		]
+	}],
+	"outputs": [{
+		"name": "returnData",
+		"type": "tuple[]",
+		"components": [
+			{"name": "success", "type": "bool"},
+			{"name": "returnData", "type": "bytes"}
+		]
+	}]
+}]`
+
+// parsedABI 解析后的 aggregate3 ABI
+var parsedABI = mustParseABI(aggregate3ABI)
+
+// Call aggregate3 子调用
+type Call struct {
+	Target       common.Address `abi:"target"`
+	AllowFailure bool           `abi:"allowFailure"` // 为 false 时该调用失败会回滚整笔交易
+	CallData     []byte         `abi:"callData"`
+}
+
+// Result aggregate3 子调用结果
+type Result struct {
+	Success    bool   `abi:"success"`
+	ReturnData []byte `abi:"returnData"` // 成功时为返回数据，失败时为回滚数据
+}
+
+// PackAggregate3 编码 aggregate3(calls) 调用数据
+func PackAggregate3(calls []Call) ([]byte, error) {
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("no calls to aggregate")
+	}
+	data, err := parsedABI.Pack("aggregate3", calls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack aggregate3: %w", err)
+	}
+	return data, nil
+}
+
+// UnpackAggregate3 解码 aggregate3 的返回数据
+func UnpackAggregate3(data []byte) ([]Result, error) {
+	var results []Result
+	if err := parsedABI.UnpackIntoInterface(&results, "aggregate3", data); err != nil {
+		return nil, fmt.Errorf("failed to unpack aggregate3 results: %w", err)
+	}
+	return results, nil
+}
+
+// mustParseABI 解析内置 ABI（解析失败说明 ABI 常量有误）
+func mustParseABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(fmt.Sprintf("invalid multicall abi: %v", err))
+	}
+	return parsed
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
+	"time"
 
 	"prediction-relayer-service/internal/data"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kratos/kratos/v2/log"
 	"gorm.io/gorm"
 )
 
@@ -16,30 +20,71 @@ type Manager interface {
 	// 使用数据库事务实现原子操作，防止 Nonce 冲突
 	AcquireNonce(ctx context.Context, operator string) (uint64, error)
 
-	// ReleaseNonce 释放 Nonce（交易确认后）
-	// 注意：Nonce 不需要释放，因为它是严格递增的
+	// ReleaseNonce 释放未能广播的 Nonce
+	// 仅当它仍是最后分配的 Nonce 时回收，否则留下的空洞由 Nonce 审计器填补
 	ReleaseNonce(ctx context.Context, operator string, nonce uint64) error
 
-	// GetPendingNonce 获取当前待处理的 Nonce
+	// GetPendingNonce 获取链上待处理的 Nonce（包含交易池中的交易）
 	GetPendingNonce(ctx context.Context, operator string) (uint64, error)
 
-	// GetCurrentNonce 获取当前链上 Nonce
+	// GetCurrentNonce 获取当前链上 Nonce（最新区块）
 	GetCurrentNonce(ctx context.Context, operator string) (uint64, error)
+
+	// Reconcile 将 Operator 的数据库 Nonce 与链上 pending Nonce 对账
+	Reconcile(ctx context.Context, operator string) error
+
+	// ReconcileAll 对账所有激活 Operator 的 Nonce（启动时调用）
+	ReconcileAll(ctx context.Context) error
 }
 
-// manager Nonce 管理器实现
-type manager struct {
-	db           *gorm.DB
+// ChainReader 链上 Nonce 查询接口（*ethclient.Client 已实现）
+type ChainReader interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+}
+
+// DriftEvent Nonce 漂移事件（通过 RocketMQ 发送，tag 为 NONCE_DRIFT）
+type DriftEvent struct {
+	ChainID    int64  `json:"chain_id"`
+	Operator   string `json:"operator"`
+	DBNonce    uint64 `json:"db_nonce"`
+	ChainNonce uint64 `json:"chain_nonce"`
+	Resolved   bool   `json:"resolved"` // 是否已自动修正数据库 Nonce
+	DetectedAt int64  `json:"detected_at"`
+}
+
+// chainSync 链上 Nonce 查询与漂移上报（各 Manager 实现共用）
+type chainSync struct {
+	chainID      int64
 	operatorRepo data.OperatorRepo
-	ethClient    interface{} // 以太坊客户端（用于查询链上 Nonce）
+	ethClient    ChainReader
+	producer     data.RocketMQProducer // 可选，用于发送漂移事件
+	log          *log.Helper
+}
+
+// manager Nonce 管理器实现（MySQL）
+// operator.current_nonce 保存下一个待分配的 Nonce，与链上 PendingNonceAt 语义一致
+type manager struct {
+	chainSync
+	db *gorm.DB
 }
 
-// NewManager 创建 Nonce 管理器
-func NewManager(db *gorm.DB, operatorRepo data.OperatorRepo, ethClient interface{}) Manager {
+// NewManager 创建 Nonce 管理器（operatorRepo 需限定为 chainID 对应的链）
+func NewManager(db *gorm.DB, chainID int64, operatorRepo data.OperatorRepo, ethClient ChainReader, producer data.RocketMQProducer, logger log.Logger) Manager {
 	return &manager{
-		db:           db,
+		chainSync: newChainSync(chainID, operatorRepo, ethClient, producer, logger),
+		db:        db,
+	}
+}
+
+// newChainSync 创建链上同步组件
+func newChainSync(chainID int64, operatorRepo data.OperatorRepo, ethClient ChainReader, producer data.RocketMQProducer, logger log.Logger) chainSync {
+	return chainSync{
+		chainID:      chainID,
 		operatorRepo: operatorRepo,
 		ethClient:    ethClient,
+		producer:     producer,
+		log:          log.NewHelper(log.With(logger, "module", "nonce")),
 	}
 }
 
@@ -48,22 +93,22 @@ func NewManager(db *gorm.DB, operatorRepo data.OperatorRepo, ethClient interface
 // 注意：Nonce 是严格递增的，不需要队列，只需要原子递增
 func (m *manager) AcquireNonce(ctx context.Context, operator string) (uint64, error) {
 	// 使用数据库事务确保原子性
-	var nextNonce uint64
+	var nonce uint64
 	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 1. 查询当前 Operator 的 Nonce
+		// 1. 查询当前 Operator 的下一个待分配 Nonce
 		var op data.Operator
-		if err := tx.Where("address = ?", operator).First(&op).Error; err != nil {
+		if err := tx.Where("chain_id = ? AND address = ?", m.chainID, operator).First(&op).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return fmt.Errorf("operator not found: %s", operator)
 			}
 			return fmt.Errorf("failed to get operator: %w", err)
 		}
 
-		// 2. 原子递增 Nonce（使用数据库的原子操作）
-		nextNonce = uint64(op.CurrentNonce) + 1
+		// 2. 分配当前值，并原子递增（新 Operator 的 CurrentNonce=0，第一笔交易使用 Nonce 0）
+		nonce = uint64(op.CurrentNonce)
 
 		// 3. 更新 Nonce
-		if err := tx.Model(&op).Update("current_nonce", int64(nextNonce)).Error; err != nil {
+		if err := tx.Model(&op).Update("current_nonce", int64(nonce+1)).Error; err != nil {
 			return fmt.Errorf("failed to update nonce: %w", err)
 		}
 
@@ -74,44 +119,127 @@ func (m *manager) AcquireNonce(ctx context.Context, operator string) (uint64, er
 		return 0, err
 	}
 
-	return nextNonce, nil
+	return nonce, nil
 }
 
-// ReleaseNonce 释放 Nonce（交易确认后）
-// 注意：Nonce 是严格递增的，不需要释放
-// 这个方法保留用于未来可能的优化（如 Nonce 回滚等）
+// ReleaseNonce 释放未能广播的 Nonce
+// 使用 CAS 回退：仅当 current_nonce 仍为 nonce+1（之后没有再分配）时回收
 func (m *manager) ReleaseNonce(ctx context.Context, operator string, nonce uint64) error {
-	// Nonce 是严格递增的，不需要释放
-	// 如果未来需要支持 Nonce 回滚，可以在这里实现
+	result := m.db.WithContext(ctx).
+		Model(&data.Operator{}).
+		Where("chain_id = ? AND address = ? AND current_nonce = ?", m.chainID, operator, int64(nonce+1)).
+		Update("current_nonce", int64(nonce))
+	if result.Error != nil {
+		return fmt.Errorf("failed to release nonce: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		m.log.WithContext(ctx).Warnw("msg", "nonce not released, a later nonce was already allocated",
+			"operator", operator, "nonce", nonce)
+	}
 	return nil
 }
 
-// GetPendingNonce 获取当前待处理的 Nonce
-func (m *manager) GetPendingNonce(ctx context.Context, operator string) (uint64, error) {
-	// 从数据库获取当前 Nonce
+// GetPendingNonce 获取链上待处理的 Nonce（包含交易池中的交易）
+func (m *chainSync) GetPendingNonce(ctx context.Context, operator string) (uint64, error) {
+	nonce, err := m.ethClient.PendingNonceAt(ctx, common.HexToAddress(operator))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending nonce: %w", err)
+	}
+	return nonce, nil
+}
+
+// GetCurrentNonce 获取当前链上 Nonce（最新区块）
+func (m *chainSync) GetCurrentNonce(ctx context.Context, operator string) (uint64, error) {
+	nonce, err := m.ethClient.NonceAt(ctx, common.HexToAddress(operator), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	return nonce, nil
+}
+
+// Reconcile 将 Operator 的数据库 Nonce 与链上 pending Nonce 对账
+// - 链上领先（外部发送了交易或数据库更新丢失）：将数据库 Nonce 提升到链上值
+// - 数据库领先（已分配的 Nonce 未进入交易池）：只记录漂移，不回退，避免复用已广播交易的 Nonce
+func (m *manager) Reconcile(ctx context.Context, operator string) error {
 	op, err := m.operatorRepo.GetByAddress(ctx, operator)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get operator: %w", err)
+		return fmt.Errorf("failed to get operator: %w", err)
 	}
 	if op == nil {
-		return 0, fmt.Errorf("operator not found: %s", operator)
+		return fmt.Errorf("operator not found: %s", operator)
 	}
 
-	// 待处理的 Nonce = 当前 Nonce + 1（下一个要使用的）
-	return uint64(op.CurrentNonce) + 1, nil
+	return m.reconcile(ctx, operator, uint64(op.CurrentNonce), func(dbNonce, chainNonce uint64) (bool, error) {
+		// 使用 CAS 更新，避免覆盖并发分配的 Nonce
+		result := m.db.WithContext(ctx).
+			Model(&data.Operator{}).
+			Where("chain_id = ? AND address = ? AND current_nonce = ?", m.chainID, operator, int64(dbNonce)).
+			Update("current_nonce", int64(chainNonce))
+		if result.Error != nil {
+			return false, fmt.Errorf("failed to update nonce: %w", result.Error)
+		}
+		return result.RowsAffected > 0, nil
+	})
 }
 
-// GetCurrentNonce 获取当前链上 Nonce
-func (m *manager) GetCurrentNonce(ctx context.Context, operator string) (uint64, error) {
-	// TODO: 实现从链上获取 Nonce（用于初始化或同步）
-	// 这里简化处理，从数据库获取
-	op, err := m.operatorRepo.GetByAddress(ctx, operator)
+// reconcile 比较本地 Nonce 与链上 pending Nonce，链上领先时调用 raise 提升本地值，存在漂移时记录并上报
+func (m *chainSync) reconcile(ctx context.Context, operator string, localNonce uint64, raise func(localNonce, chainNonce uint64) (bool, error)) error {
+	chainNonce, err := m.GetPendingNonce(ctx, operator)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get operator: %w", err)
+		return err
 	}
-	if op == nil {
-		return 0, fmt.Errorf("operator not found: %s", operator)
+	if localNonce == chainNonce {
+		return nil
+	}
+
+	resolved := false
+	if chainNonce > localNonce {
+		if resolved, err = raise(localNonce, chainNonce); err != nil {
+			return err
+		}
 	}
 
-	return uint64(op.CurrentNonce), nil
+	m.log.WithContext(ctx).Warnw("msg", "operator nonce drift detected",
+		"operator", operator, "db_nonce", localNonce, "chain_nonce", chainNonce, "resolved", resolved)
+	m.emitDrift(ctx, &DriftEvent{
+		ChainID:    m.chainID,
+		Operator:   operator,
+		DBNonce:    localNonce,
+		ChainNonce: chainNonce,
+		Resolved:   resolved,
+		DetectedAt: time.Now().Unix(),
+	})
+
+	return nil
+}
+
+// ReconcileAll 对账所有激活 Operator 的 Nonce（启动时调用）
+func (m *manager) ReconcileAll(ctx context.Context) error {
+	return m.reconcileAll(ctx, m.Reconcile)
+}
+
+// reconcileAll 对所有激活 Operator 执行 reconcile
+func (m *chainSync) reconcileAll(ctx context.Context, reconcile func(ctx context.Context, operator string) error) error {
+	operators, err := m.operatorRepo.GetActiveOperators(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get operators: %w", err)
+	}
+
+	var errs []error
+	for _, op := range operators {
+		if err := reconcile(ctx, op.Address); err != nil {
+			errs = append(errs, fmt.Errorf("operator %s: %w", op.Address, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// emitDrift 发送 Nonce 漂移事件
+func (m *chainSync) emitDrift(ctx context.Context, event *DriftEvent) {
+	if m.producer == nil {
+		return
+	}
+	if err := m.producer.SendMessage(ctx, "", "NONCE_DRIFT", event); err != nil {
+		m.log.WithContext(ctx).Errorf("failed to send nonce drift event: %v", err)
+	}
 }
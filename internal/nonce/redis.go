@@ -0,0 +1,213 @@
+package nonce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"prediction-relayer-service/internal/data"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// redisKeyPrefix Redis 中 Operator Nonce 的键前缀（键格式 relayer:nonce:{chain_id}:{address}）
+const redisKeyPrefix = "relayer:nonce:"
+
+// acquireScript 原子分配 Nonce
+// KEYS[1]: Nonce 键；ARGV[1]: 种子值（数据库中的高水位），为空时表示不做初始化
+// 返回分配的 Nonce；键不存在且未提供种子时返回 -1，由调用方从数据库加载种子后重试
+var acquireScript = redis.NewScript(`
+local v = redis.call('GET', KEYS[1])
+if not v then
+	if ARGV[1] == '' then
+		return -1
+	end
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+return redis.call('INCR', KEYS[1]) - 1
+`)
+
+// releaseScript CAS 回退 Nonce：仅当当前值仍为 nonce+1 时回退到 nonce
+// KEYS[1]: Nonce 键；ARGV[1]: nonce；ARGV[2]: nonce+1
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[2] then
+	redis.call('SET', KEYS[1], ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// raiseScript 将 Nonce 提升到目标值（只增不减）
+// KEYS[1]: Nonce 键；ARGV[1]: 目标值
+var raiseScript = redis.NewScript(`
+local v = tonumber(redis.call('GET', KEYS[1]) or '-1')
+if v < tonumber(ARGV[1]) then
+	redis.call('SET', KEYS[1], ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// redisManager 基于 Redis 的 Nonce 管理器实现（多副本部署）
+// Redis 键保存下一个待分配的 Nonce，通过 Lua 脚本原子递增；
+// 每次分配后将高水位写回 operator.current_nonce，Redis 数据丢失时以数据库高水位重新初始化
+type redisManager struct {
+	chainSync
+	highWater highWaterStore
+	rdb       *redis.Client
+}
+
+// highWaterStore Nonce 高水位（operator.current_nonce）的持久化
+type highWaterStore interface {
+	// Raise 将高水位提升到 next（只增不减，兼容多副本乱序写入）
+	Raise(ctx context.Context, operator string, next uint64) error
+
+	// Release CAS 回退高水位：仅当当前值仍为 nonce+1 时回退到 nonce
+	Release(ctx context.Context, operator string, nonce uint64) error
+}
+
+// NewRedisManager 创建基于 Redis 的 Nonce 管理器（operatorRepo 需限定为 chainID 对应的链）
+func NewRedisManager(db *gorm.DB, rdb *redis.Client, chainID int64, operatorRepo data.OperatorRepo, ethClient ChainReader, producer data.RocketMQProducer, logger log.Logger) Manager {
+	return &redisManager{
+		chainSync: newChainSync(chainID, operatorRepo, ethClient, producer, logger),
+		highWater: &dbHighWater{db: db, chainID: chainID},
+		rdb:       rdb,
+	}
+}
+
+// AcquireNonce 获取并锁定 Nonce
+// 通过 Lua 脚本原子递增，多个副本之间不会分配到相同的 Nonce
+func (m *redisManager) AcquireNonce(ctx context.Context, operator string) (uint64, error) {
+	key := m.key(operator)
+
+	nonce, err := acquireScript.Run(ctx, m.rdb, []string{key}, "").Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire nonce: %w", err)
+	}
+
+	if nonce < 0 {
+		// 键不存在（首次使用或 Redis 数据丢失），以数据库高水位初始化
+		seed, err := m.loadHighWater(ctx, operator)
+		if err != nil {
+			return 0, err
+		}
+		nonce, err = acquireScript.Run(ctx, m.rdb, []string{key}, seed).Int64()
+		if err != nil {
+			return 0, fmt.Errorf("failed to acquire nonce: %w", err)
+		}
+	}
+
+	// 将高水位写回数据库，保证 Redis 数据丢失后不会重复分配
+	if err := m.highWater.Raise(ctx, operator, uint64(nonce)+1); err != nil {
+		// 回退本次分配，避免 Redis 领先于数据库
+		if releaseErr := m.ReleaseNonce(ctx, operator, uint64(nonce)); releaseErr != nil {
+			m.log.WithContext(ctx).Errorf("failed to release nonce %d for %s: %v", nonce, operator, releaseErr)
+		}
+		return 0, err
+	}
+
+	return uint64(nonce), nil
+}
+
+// ReleaseNonce 释放未能广播的 Nonce
+// 使用 CAS 回退：仅当 Redis 中的值仍为 nonce+1（之后没有再分配）时回收，并同步回退数据库
+func (m *redisManager) ReleaseNonce(ctx context.Context, operator string, nonce uint64) error {
+	released, err := releaseScript.Run(ctx, m.rdb, []string{m.key(operator)}, nonce, nonce+1).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to release nonce: %w", err)
+	}
+	if released == 0 {
+		m.log.WithContext(ctx).Warnw("msg", "nonce not released, a later nonce was already allocated",
+			"operator", operator, "nonce", nonce)
+		return nil
+	}
+
+	return m.highWater.Release(ctx, operator, nonce)
+}
+
+// Reconcile 将 Operator 在 Redis 中的 Nonce 与链上 pending Nonce 对账
+// 链上领先时提升 Redis 与数据库的高水位；Redis 领先时只记录漂移
+func (m *redisManager) Reconcile(ctx context.Context, operator string) error {
+	key := m.key(operator)
+
+	local, err := m.rdb.Get(ctx, key).Uint64()
+	if errors.Is(err, redis.Nil) {
+		seed, err := m.loadHighWater(ctx, operator)
+		if err != nil {
+			return err
+		}
+		if _, err := raiseScript.Run(ctx, m.rdb, []string{key}, seed).Result(); err != nil {
+			return fmt.Errorf("failed to seed nonce: %w", err)
+		}
+		local, err = m.rdb.Get(ctx, key).Uint64()
+		if err != nil {
+			return fmt.Errorf("failed to get nonce: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	return m.reconcile(ctx, operator, local, func(_, chainNonce uint64) (bool, error) {
+		raised, err := raiseScript.Run(ctx, m.rdb, []string{key}, chainNonce).Int64()
+		if err != nil {
+			return false, fmt.Errorf("failed to update nonce: %w", err)
+		}
+		if err := m.highWater.Raise(ctx, operator, chainNonce); err != nil {
+			return false, err
+		}
+		return raised == 1, nil
+	})
+}
+
+// ReconcileAll 对账所有激活 Operator 的 Nonce（启动时调用）
+func (m *redisManager) ReconcileAll(ctx context.Context) error {
+	return m.reconcileAll(ctx, m.Reconcile)
+}
+
+// loadHighWater 从数据库读取 Operator 的 Nonce 高水位（作为 Redis 初始化种子）
+func (m *redisManager) loadHighWater(ctx context.Context, operator string) (string, error) {
+	op, err := m.operatorRepo.GetByAddress(ctx, operator)
+	if err != nil {
+		return "", fmt.Errorf("failed to get operator: %w", err)
+	}
+	if op == nil {
+		return "", fmt.Errorf("operator not found: %s", operator)
+	}
+	return fmt.Sprintf("%d", op.CurrentNonce), nil
+}
+
+// key 返回 Operator 在本链的 Nonce 键（地址统一小写，避免大小写不同导致重复计数）
+func (m *redisManager) key(operator string) string {
+	return fmt.Sprintf("%s%d:%s", redisKeyPrefix, m.chainID, strings.ToLower(operator))
+}
+
+// dbHighWater highWaterStore 实现（MySQL operator 表）
+type dbHighWater struct {
+	db      *gorm.DB
+	chainID int64
+}
+
+// Raise 将高水位写回数据库（只增不减）
+func (h *dbHighWater) Raise(ctx context.Context, operator string, next uint64) error {
+	if err := h.db.WithContext(ctx).
+		Model(&data.Operator{}).
+		Where("chain_id = ? AND address = ?", h.chainID, operator).
+		Update("current_nonce", gorm.Expr("GREATEST(current_nonce, ?)", int64(next))).Error; err != nil {
+		return fmt.Errorf("failed to persist nonce: %w", err)
+	}
+	return nil
+}
+
+// Release CAS 回退数据库高水位
+func (h *dbHighWater) Release(ctx context.Context, operator string, nonce uint64) error {
+	if err := h.db.WithContext(ctx).
+		Model(&data.Operator{}).
+		Where("chain_id = ? AND address = ? AND current_nonce = ?", h.chainID, operator, int64(nonce+1)).
+		Update("current_nonce", int64(nonce)).Error; err != nil {
+		return fmt.Errorf("failed to release nonce: %w", err)
+	}
+	return nil
+}
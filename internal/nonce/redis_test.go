@@ -0,0 +1,385 @@
+package nonce
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"prediction-relayer-service/internal/data"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestRedis 启动进程内 Redis（miniredis）
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return rdb
+}
+
+func TestAcquireScript(t *testing.T) {
+	ctx := context.Background()
+	rdb := newTestRedis(t)
+	key := []string{"relayer:nonce:137:0xabc"}
+
+	// 键不存在且未提供种子：返回 -1，由调用方加载种子
+	n, err := acquireScript.Run(ctx, rdb, key, "").Int64()
+	if err != nil || n != -1 {
+		t.Fatalf("acquire without seed = %d, %v; want -1", n, err)
+	}
+	if exists, _ := rdb.Exists(ctx, key[0]).Result(); exists != 0 {
+		t.Fatalf("key created without seed")
+	}
+
+	// 以种子初始化后从种子开始分配
+	n, err = acquireScript.Run(ctx, rdb, key, "7").Int64()
+	if err != nil || n != 7 {
+		t.Fatalf("acquire with seed = %d, %v; want 7", n, err)
+	}
+	// 键已存在时忽略种子
+	n, err = acquireScript.Run(ctx, rdb, key, "100").Int64()
+	if err != nil || n != 8 {
+		t.Fatalf("acquire after seed = %d, %v; want 8", n, err)
+	}
+	if v, _ := rdb.Get(ctx, key[0]).Int64(); v != 9 {
+		t.Fatalf("next nonce = %d, want 9", v)
+	}
+}
+
+func TestAcquireScriptConcurrent(t *testing.T) {
+	ctx := context.Background()
+	rdb := newTestRedis(t)
+	key := []string{"relayer:nonce:137:0xabc"}
+	if err := rdb.Set(ctx, key[0], 0, 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	const workers, perWorker = 8, 25
+	var (
+		mu   sync.Mutex
+		seen = make(map[int64]bool)
+		wg   sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				n, err := acquireScript.Run(ctx, rdb, key, "").Int64()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				if seen[n] {
+					t.Errorf("nonce %d allocated twice", n)
+				}
+				seen[n] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != workers*perWorker {
+		t.Fatalf("allocated %d nonces, want %d", len(seen), workers*perWorker)
+	}
+	for n := int64(0); n < workers*perWorker; n++ {
+		if !seen[n] {
+			t.Fatalf("nonce %d skipped", n)
+		}
+	}
+}
+
+func TestReleaseScript(t *testing.T) {
+	ctx := context.Background()
+	rdb := newTestRedis(t)
+	key := []string{"relayer:nonce:137:0xabc"}
+	if err := rdb.Set(ctx, key[0], 5, 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 最近一次分配的是 4：当前值为 5 时回退到 4
+	released, err := releaseScript.Run(ctx, rdb, key, 4, 5).Int64()
+	if err != nil || released != 1 {
+		t.Fatalf("release latest = %d, %v; want 1", released, err)
+	}
+	if v, _ := rdb.Get(ctx, key[0]).Int64(); v != 4 {
+		t.Fatalf("after release = %d, want 4", v)
+	}
+
+	// 之后又分配过 Nonce：不回退（否则会重复分配）
+	if err := rdb.Set(ctx, key[0], 6, 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	released, err = releaseScript.Run(ctx, rdb, key, 4, 5).Int64()
+	if err != nil || released != 0 {
+		t.Fatalf("release stale = %d, %v; want 0", released, err)
+	}
+	if v, _ := rdb.Get(ctx, key[0]).Int64(); v != 6 {
+		t.Fatalf("after stale release = %d, want 6", v)
+	}
+
+	// 键不存在：不回退、不创建
+	released, err = releaseScript.Run(ctx, rdb, []string{"relayer:nonce:137:0xdef"}, 0, 1).Int64()
+	if err != nil || released != 0 {
+		t.Fatalf("release missing = %d, %v; want 0", released, err)
+	}
+}
+
+func TestRaiseScript(t *testing.T) {
+	ctx := context.Background()
+	rdb := newTestRedis(t)
+	key := []string{"relayer:nonce:137:0xabc"}
+
+	// 键不存在：初始化为目标值
+	raised, err := raiseScript.Run(ctx, rdb, key, 3).Int64()
+	if err != nil || raised != 1 {
+		t.Fatalf("raise missing = %d, %v; want 1", raised, err)
+	}
+	if v, _ := rdb.Get(ctx, key[0]).Int64(); v != 3 {
+		t.Fatalf("after raise = %d, want 3", v)
+	}
+
+	// 链上领先：提升
+	raised, err = raiseScript.Run(ctx, rdb, key, 10).Int64()
+	if err != nil || raised != 1 {
+		t.Fatalf("raise ahead = %d, %v; want 1", raised, err)
+	}
+
+	// 目标值不高于当前值：只增不减
+	for _, target := range []int64{10, 2} {
+		raised, err = raiseScript.Run(ctx, rdb, key, target).Int64()
+		if err != nil || raised != 0 {
+			t.Fatalf("raise to %d = %d, %v; want 0", target, raised, err)
+		}
+	}
+	if v, _ := rdb.Get(ctx, key[0]).Int64(); v != 10 {
+		t.Fatalf("final = %d, want 10", v)
+	}
+}
+
+// fakeOperators Operator 仓库和高水位存储 stand-in（与 dbHighWater 的 SQL 语义一致）
+type fakeOperators struct {
+	data.OperatorRepo
+	mu        sync.Mutex
+	operators map[string]*data.Operator
+	failRaise bool // Raise 返回错误（模拟数据库写入失败）
+}
+
+func (f *fakeOperators) GetByAddress(ctx context.Context, address string) (*data.Operator, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	op, ok := f.operators[address]
+	if !ok {
+		return nil, nil
+	}
+	copied := *op
+	return &copied, nil
+}
+
+func (f *fakeOperators) Raise(ctx context.Context, operator string, next uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failRaise {
+		return errors.New("database unavailable")
+	}
+	if op := f.operators[operator]; op != nil && op.CurrentNonce < int64(next) {
+		op.CurrentNonce = int64(next)
+	}
+	return nil
+}
+
+func (f *fakeOperators) Release(ctx context.Context, operator string, nonce uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if op := f.operators[operator]; op != nil && op.CurrentNonce == int64(nonce+1) {
+		op.CurrentNonce = int64(nonce)
+	}
+	return nil
+}
+
+func (f *fakeOperators) highWater(operator string) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.operators[operator].CurrentNonce
+}
+
+func (f *fakeOperators) setHighWater(operator string, nonce int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.operators[operator].CurrentNonce = nonce
+}
+
+// fakeChain 链上 Nonce stand-in
+type fakeChain struct {
+	pending uint64
+}
+
+func (f *fakeChain) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return f.pending, nil
+}
+
+func (f *fakeChain) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return f.pending, nil
+}
+
+// testOperator 大小写混合的 Operator 地址（Redis 键统一小写）
+const testOperator = "0x00000000000000000000000000000000000000Aa"
+
+// newTestRedisManager 创建使用进程内 Redis 和 stand-in 的 Redis Nonce 管理器（数据库高水位为 highWater）
+func newTestRedisManager(t *testing.T, highWater int64) (*redisManager, *miniredis.Miniredis, *fakeOperators, *fakeChain) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	operators := &fakeOperators{operators: map[string]*data.Operator{
+		testOperator: {ChainID: 137, Address: testOperator, CurrentNonce: highWater},
+	}}
+	chain := &fakeChain{}
+	m := &redisManager{
+		chainSync: newChainSync(137, operators, chain, nil, log.DefaultLogger),
+		highWater: operators,
+		rdb:       rdb,
+	}
+	return m, mr, operators, chain
+}
+
+// redisNonce 返回 Redis 中 Operator 的下一个待分配 Nonce（键不存在时返回 -1）
+func redisNonce(t *testing.T, mr *miniredis.Miniredis) int64 {
+	t.Helper()
+	v, err := mr.Get("relayer:nonce:137:" + strings.ToLower(testOperator))
+	if err != nil {
+		return -1
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestRedisManagerAcquireSeedsFromHighWater(t *testing.T) {
+	ctx := context.Background()
+	m, mr, operators, _ := newTestRedisManager(t, 42)
+
+	for want := uint64(42); want < 44; want++ {
+		n, err := m.AcquireNonce(ctx, testOperator)
+		if err != nil || n != want {
+			t.Fatalf("AcquireNonce = %d, %v; want %d", n, err, want)
+		}
+		if hw := operators.highWater(testOperator); hw != int64(want)+1 {
+			t.Fatalf("high water after nonce %d = %d, want %d", want, hw, want+1)
+		}
+	}
+
+	// Redis 数据丢失：以数据库高水位重新初始化，不重复分配
+	mr.FlushAll()
+	n, err := m.AcquireNonce(ctx, testOperator)
+	if err != nil || n != 44 {
+		t.Fatalf("AcquireNonce after key loss = %d, %v; want 44", n, err)
+	}
+	if v := redisNonce(t, mr); v != 45 {
+		t.Fatalf("redis nonce = %d, want 45", v)
+	}
+
+	if _, err := m.AcquireNonce(ctx, "0x00000000000000000000000000000000000000bb"); err == nil {
+		t.Fatal("AcquireNonce succeeded for an unknown operator")
+	}
+}
+
+func TestRedisManagerAcquireRollsBackWhenPersistFails(t *testing.T) {
+	ctx := context.Background()
+	m, mr, operators, _ := newTestRedisManager(t, 5)
+
+	operators.failRaise = true
+	if _, err := m.AcquireNonce(ctx, testOperator); err == nil {
+		t.Fatal("AcquireNonce succeeded although the high water could not be persisted")
+	}
+	// Redis 回退到分配前的值，不领先于数据库
+	if v := redisNonce(t, mr); v != 5 {
+		t.Fatalf("redis nonce after failed persist = %d, want 5", v)
+	}
+	if hw := operators.highWater(testOperator); hw != 5 {
+		t.Fatalf("high water after failed persist = %d, want 5", hw)
+	}
+
+	operators.failRaise = false
+	if n, err := m.AcquireNonce(ctx, testOperator); err != nil || n != 5 {
+		t.Fatalf("AcquireNonce after recovery = %d, %v; want 5", n, err)
+	}
+}
+
+func TestRedisManagerRelease(t *testing.T) {
+	ctx := context.Background()
+	m, mr, operators, _ := newTestRedisManager(t, 5)
+	for i := 0; i < 2; i++ {
+		if _, err := m.AcquireNonce(ctx, testOperator); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 5 之后已分配 6：Redis 和数据库都不回退
+	if err := m.ReleaseNonce(ctx, testOperator, 5); err != nil {
+		t.Fatal(err)
+	}
+	if v, hw := redisNonce(t, mr), operators.highWater(testOperator); v != 7 || hw != 7 {
+		t.Fatalf("after stale release redis = %d, high water = %d; want 7, 7", v, hw)
+	}
+
+	// 最后分配的 6：Redis 和数据库一起回退
+	if err := m.ReleaseNonce(ctx, testOperator, 6); err != nil {
+		t.Fatal(err)
+	}
+	if v, hw := redisNonce(t, mr), operators.highWater(testOperator); v != 6 || hw != 6 {
+		t.Fatalf("after release redis = %d, high water = %d; want 6, 6", v, hw)
+	}
+
+	// 数据库高水位已被其他副本提升：数据库 CAS 不匹配，不回退数据库
+	if _, err := m.AcquireNonce(ctx, testOperator); err != nil {
+		t.Fatal(err)
+	}
+	operators.setHighWater(testOperator, 9)
+	if err := m.ReleaseNonce(ctx, testOperator, 6); err != nil {
+		t.Fatal(err)
+	}
+	if v, hw := redisNonce(t, mr), operators.highWater(testOperator); v != 6 || hw != 9 {
+		t.Fatalf("after release with raised high water redis = %d, high water = %d; want 6, 9", v, hw)
+	}
+}
+
+func TestRedisManagerReconcile(t *testing.T) {
+	ctx := context.Background()
+	m, mr, operators, chain := newTestRedisManager(t, 4)
+
+	// Redis 键不存在：先以数据库高水位初始化，链上领先时提升 Redis 和数据库
+	chain.pending = 10
+	if err := m.Reconcile(ctx, testOperator); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if v, hw := redisNonce(t, mr), operators.highWater(testOperator); v != 10 || hw != 10 {
+		t.Fatalf("after reconcile redis = %d, high water = %d; want 10, 10", v, hw)
+	}
+
+	// 本地领先：只记录漂移，不回退
+	chain.pending = 8
+	if err := m.Reconcile(ctx, testOperator); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if v, hw := redisNonce(t, mr), operators.highWater(testOperator); v != 10 || hw != 10 {
+		t.Fatalf("after reconcile behind chain redis = %d, high water = %d; want 10, 10", v, hw)
+	}
+	if n, err := m.AcquireNonce(ctx, testOperator); err != nil || n != 10 {
+		t.Fatalf("AcquireNonce after reconcile = %d, %v; want 10", n, err)
+	}
+}
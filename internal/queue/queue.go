@@ -0,0 +1,488 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/executor"
+	"prediction-relayer-service/internal/nonce"
+	"prediction-relayer-service/internal/selector"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// ErrQueueFull 排队中的任务达到上限（HTTP 429 / gRPC RESOURCE_EXHAUSTED），调用方应稍后重试
+var ErrQueueFull = errors.New(429, "RESOURCE_EXHAUSTED", "submission queue is full, retry later")
+
+const (
+	refreshInterval = 30 * time.Second       // Operator 列表刷新及过期租约回收间隔
+	retryBackoff    = 2 * time.Second        // 退避重试的初始间隔（每次翻倍）
+	maxRetryBackoff = time.Minute            // 退避重试的最大间隔
+	maxBackoffShift = 5                      // 退避翻倍次数上限（避免位移溢出）
+	orphanBatchSize = 100                    // 每批转移的已停用 Operator 排队任务数
+	feeBumpPercent  = 115                    // replacement underpriced 后每次重试的费用涨幅（需高于节点最小替换涨幅）
+	feeCapWait      = 15 * time.Second       // 费用超过交易类型上限时的等待间隔
+	recordBackoff   = 500 * time.Millisecond // 广播记录写入失败后重试的初始间隔（每次翻倍）
+)
+
+// Queue 持久化提交队列接口
+// 任务以 QUEUED 状态写入 transaction 表，由每个 Operator 独立的 worker 池认领执行，进程重启后自动恢复
+type Queue interface {
+	// Enqueue 写入 QUEUED 任务并唤醒对应 Operator 的 worker；队列已满时返回 ErrQueueFull
+	Enqueue(ctx context.Context, tx *data.Transaction) error
+
+	// Start 回收过期租约并启动 worker 池（阻塞直到 Stop 或 ctx 取消）
+	Start(ctx context.Context) error
+
+	// Stop 停止队列，等待执行中的任务完成
+	Stop()
+}
+
+// Config 队列配置
+type Config struct {
+	WorkersPerOperator int           // 每个 Operator 的并发 worker 数
+	MaxPending         int64         // 排队中（QUEUED、PROCESSING）任务上限
+	PollInterval       time.Duration // 空闲 worker 轮询间隔（兜底其他副本写入的任务）
+	ProcessingLease    time.Duration // PROCESSING 任务租约，超时未广播的任务重新入队
+	MaxRetry           int32         // 单个任务的最大重试次数（chain.max_retry）
+}
+
+// pool 单个 Operator 的 worker 池
+type pool struct {
+	address string
+	wake    chan struct{} // 有新任务时唤醒空闲 worker
+}
+
+// queue Queue 实现
+type queue struct {
+	cfg          Config
+	txRepo       data.TransactionRepo
+	operatorRepo data.OperatorRepo
+	executor     executor.Executor
+	nonceMgr     nonce.Manager
+	selector     selector.OperatorSelector
+	log          *log.Helper
+
+	mu       sync.Mutex
+	ctx      context.Context // worker 运行上下文（Start 之后有效）
+	pools    map[string]*pool
+	wg       sync.WaitGroup
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewQueue 创建持久化提交队列
+func NewQueue(
+	cfg Config,
+	txRepo data.TransactionRepo,
+	operatorRepo data.OperatorRepo,
+	exec executor.Executor,
+	nonceMgr nonce.Manager,
+	operatorSelector selector.OperatorSelector,
+	logger log.Logger,
+) Queue {
+	return &queue{
+		cfg:          cfg,
+		txRepo:       txRepo,
+		operatorRepo: operatorRepo,
+		executor:     exec,
+		nonceMgr:     nonceMgr,
+		selector:     operatorSelector,
+		log:          log.NewHelper(log.With(logger, "module", "queue")),
+		pools:        make(map[string]*pool),
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// Enqueue 写入任务
+// 容量检查与写入不在同一事务中，并发提交时可能短暂超出上限，用于背压而非精确配额
+func (q *queue) Enqueue(ctx context.Context, tx *data.Transaction) error {
+	count, err := q.txRepo.CountQueued(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count queued transactions: %w", err)
+	}
+	if count >= q.cfg.MaxPending {
+		q.log.WithContext(ctx).Warnw("msg", "submission queue is full", "queued", count, "max_pending", q.cfg.MaxPending)
+		return ErrQueueFull
+	}
+
+	tx.Status = "QUEUED"
+	if err := q.txRepo.Create(ctx, tx); err != nil {
+		return fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	q.notify(tx.FromAddress)
+	return nil
+}
+
+// Start 启动队列
+func (q *queue) Start(ctx context.Context) error {
+	q.mu.Lock()
+	q.ctx = ctx
+	q.mu.Unlock()
+
+	// 上次退出时未广播的任务（进程崩溃、发布中断）在租约过期后重新入队
+	q.requeueExpired(ctx)
+	q.refreshPools(ctx)
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			q.wg.Wait()
+			return ctx.Err()
+		case <-q.stopCh:
+			return nil
+		case <-ticker.C:
+			q.requeueExpired(ctx)
+			q.refreshPools(ctx)
+		}
+	}
+}
+
+// Stop 停止队列（可重复调用）
+func (q *queue) Stop() {
+	q.stopOnce.Do(func() { close(q.stopCh) })
+	q.wg.Wait()
+}
+
+// requeueExpired 回收过期租约
+func (q *queue) requeueExpired(ctx context.Context) {
+	n, err := q.txRepo.RequeueExpired(ctx, time.Now().Add(-q.cfg.ProcessingLease))
+	if err != nil {
+		q.log.WithContext(ctx).Errorw("msg", "failed to requeue expired tasks", "error", err)
+		return
+	}
+	if n > 0 {
+		q.log.WithContext(ctx).Warnw("msg", "requeued tasks with expired lease", "count", n)
+	}
+}
+
+// refreshPools 为新增的激活 Operator 创建 worker 池，并转移已停用 Operator 的排队任务
+// 已停用的 Operator 保留已有 worker 池，处理完执行中的任务后不再有新任务
+func (q *queue) refreshPools(ctx context.Context) {
+	operators, err := q.operatorRepo.GetActiveOperators(ctx)
+	if err != nil {
+		q.log.WithContext(ctx).Errorw("msg", "failed to get operators", "error", err)
+		return
+	}
+	active := make([]string, len(operators))
+	for i, op := range operators {
+		q.ensurePool(op.Address)
+		active[i] = op.Address
+	}
+	q.reassignOrphaned(ctx, active)
+}
+
+// reassignOrphaned 将已停用 Operator 的排队任务转移到其他激活 Operator（进程重启后不会为其创建 worker 池）
+// Proxy Wallet 中继交易的签名绑定了发送方，无法转移，直接失败；没有可用 Operator 时放回队列，下次刷新再转移
+func (q *queue) reassignOrphaned(ctx context.Context, active []string) {
+	for {
+		txs, err := q.txRepo.ClaimOrphaned(ctx, active, orphanBatchSize)
+		if err != nil {
+			q.log.WithContext(ctx).Errorw("msg", "failed to claim orphaned tasks", "error", err)
+			return
+		}
+		for i, tx := range txs {
+			if !q.reassign(ctx, tx) {
+				// 剩余任务放回队列留到下次刷新，避免本轮重复认领
+				for _, rest := range txs[i:] {
+					q.requeue(ctx, rest)
+				}
+				return
+			}
+		}
+		if len(txs) < orphanBatchSize {
+			return
+		}
+	}
+}
+
+// reassign 转移单个已认领的孤儿任务，没有可用 Operator 时返回 false（任务仍为已认领状态）
+func (q *queue) reassign(ctx context.Context, tx *data.Transaction) bool {
+	if tx.TransactionType == "PROXY_RELAY" {
+		q.fail(ctx, tx, executor.CategoryUnknown, fmt.Errorf("relay operator %s is no longer active", tx.FromAddress))
+		return true
+	}
+	operator, err := q.selector.Select(ctx, &selector.SelectRequest{BuilderAPIKey: tx.BuilderAPIKey})
+	if err != nil {
+		q.log.WithContext(ctx).Warnw("msg", "no operator available for orphaned task", "task_id", tx.TaskID, "operator", tx.FromAddress, "error", err)
+		return false
+	}
+
+	// 保留原有的退避时间和错误信息
+	notBefore := time.Now()
+	if tx.NextRetryAt != nil && tx.NextRetryAt.After(notBefore) {
+		notBefore = *tx.NextRetryAt
+	}
+	if err := q.txRepo.ScheduleRetry(ctx, tx.TaskID, operator.Address, tx.RetryCount, tx.ErrorMessage, notBefore); err != nil {
+		q.log.WithContext(ctx).Errorw("msg", "failed to reassign orphaned task", "task_id", tx.TaskID, "error", err)
+		q.requeue(ctx, tx)
+	} else {
+		q.log.WithContext(ctx).Infow("msg", "orphaned task reassigned", "task_id", tx.TaskID, "from", tx.FromAddress, "to", operator.Address)
+		q.notify(operator.Address)
+	}
+	return true
+}
+
+// ensurePool 获取 Operator 的 worker 池（不存在且队列已启动时创建）
+func (q *queue) ensurePool(address string) *pool {
+	key := strings.ToLower(address)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if p, ok := q.pools[key]; ok {
+		return p
+	}
+	if q.ctx == nil {
+		return nil
+	}
+
+	p := &pool{
+		address: address,
+		wake:    make(chan struct{}, q.cfg.WorkersPerOperator),
+	}
+	q.pools[key] = p
+	for i := 0; i < q.cfg.WorkersPerOperator; i++ {
+		q.wg.Add(1)
+		go q.work(q.ctx, p)
+	}
+	q.log.Infow("msg", "operator worker pool started", "operator", address, "workers", q.cfg.WorkersPerOperator)
+	return p
+}
+
+// notify 唤醒 Operator 的一个空闲 worker（全部繁忙时忽略，worker 处理完会继续认领）
+func (q *queue) notify(address string) {
+	p := q.ensurePool(address)
+	if p == nil {
+		return
+	}
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// work worker 循环：持续认领并执行任务，队列为空时等待唤醒或轮询
+func (q *queue) work(ctx context.Context, p *pool) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stopCh:
+			return
+		default:
+		}
+
+		if q.processNext(ctx, p.address) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stopCh:
+			return
+		case <-p.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// processNext 认领并执行一个任务，没有可认领的任务时返回 false
+func (q *queue) processNext(ctx context.Context, address string) bool {
+	txs, err := q.txRepo.ClaimQueued(ctx, address, time.Now(), 1)
+	if err != nil {
+		q.log.WithContext(ctx).Errorw("msg", "failed to claim queued task", "operator", address, "error", err)
+		return false
+	}
+	if len(txs) == 0 {
+		return false
+	}
+
+	q.process(ctx, txs[0])
+	return true
+}
+
+// process 执行已认领的任务
+// 广播或估算失败时按错误分类恢复：Nonce 过低时对账后重试，替换出价过低时加价重试，
+// 余额不足时切换 Operator 重新排队，RPC 临时错误退避后重新排队，其余错误直接失败；重试次数不超过 MaxRetry
+// 费用超过交易类型上限时等待费用回落后重新排队，不计入重试次数
+func (q *queue) process(ctx context.Context, tx *data.Transaction) {
+	operator, err := q.operatorRepo.GetByAddress(ctx, tx.FromAddress)
+	if err != nil {
+		q.log.WithContext(ctx).Errorw("msg", "failed to get operator, requeueing", "task_id", tx.TaskID, "error", err)
+		q.requeue(ctx, tx)
+		return
+	}
+	if operator == nil {
+		q.fail(ctx, tx, executor.CategoryUnknown, fmt.Errorf("operator %s not found", tx.FromAddress))
+		return
+	}
+
+	// 执行时间限制在租约内，避免租约过期后任务被其他 worker 重复执行
+	execCtx, cancel := context.WithTimeout(ctx, q.cfg.ProcessingLease/2)
+	defer cancel()
+
+	bump := int64(100)
+	for {
+		result, err := q.executor.ExecuteWithFeeBump(execCtx, tx, operator, bump)
+		if err == nil {
+			if result.SendError != nil {
+				// 广播结果不确定：保留 Nonce 并按已广播记录，由监控器确认或重新广播
+				q.log.WithContext(ctx).Warnw("msg", "transaction broadcast is ambiguous, recording as broadcast",
+					"task_id", tx.TaskID, "tx_hash", result.TxHash, "nonce", result.Nonce, "error", result.SendError)
+			}
+			q.record(ctx, tx, result, time.Now().Add(q.cfg.ProcessingLease/2))
+			return
+		}
+
+		// 进程退出导致的取消不计入重试
+		if ctx.Err() != nil {
+			q.requeue(context.Background(), tx)
+			return
+		}
+
+		category := executor.Classify(err)
+		action := category.Action()
+		if action == executor.ActionWait {
+			q.wait(ctx, tx, category, err)
+			return
+		}
+		if action == executor.ActionFailFast || tx.RetryCount >= q.cfg.MaxRetry {
+			q.fail(ctx, tx, category, err)
+			return
+		}
+		tx.RetryCount++
+
+		q.log.WithContext(ctx).Warnw("msg", "transaction execution failed, retrying",
+			"task_id", tx.TaskID, "operator", tx.FromAddress, "category", category, "action", action,
+			"retry", tx.RetryCount, "error", err)
+
+		switch action {
+		case executor.ActionResyncNonce:
+			if err := q.nonceMgr.Reconcile(execCtx, operator.Address); err != nil {
+				q.retryLater(ctx, tx, tx.FromAddress, category, err)
+				return
+			}
+		case executor.ActionBumpFee:
+			bump = bump * feeBumpPercent / 100
+		case executor.ActionSwitchOperator:
+			q.switchOperator(ctx, tx, category, err)
+			return
+		default:
+			q.retryLater(ctx, tx, tx.FromAddress, category, err)
+			return
+		}
+	}
+}
+
+// record 记录成功广播
+// 交易已上链广播，记录失败时任务仍为 PROCESSING，租约过期后会以新 Nonce 再次执行（重复广播），
+// 因此在 deadline（租约过期之前）内退避重试写入，且不受进程退出取消的影响
+func (q *queue) record(ctx context.Context, tx *data.Transaction, result *executor.ExecutionResult, deadline time.Time) {
+	// 原子记录交易哈希、nonce、gas limit、费用参数及原始广播（替换链的第 0 次尝试），任务进入 PENDING
+	attempt := &data.TransactionAttempt{
+		TaskID:               tx.TaskID,
+		TxHash:               result.TxHash,
+		Attempt:              0,
+		Nonce:                int64(result.Nonce),
+		MaxFeePerGas:         result.MaxFeePerGas.String(),
+		MaxPriorityFeePerGas: result.MaxPriorityFeePerGas.String(),
+		RawTx:                result.RawTx,
+		Status:               "BROADCAST",
+	}
+	recordCtx, cancel := context.WithDeadline(context.WithoutCancel(ctx), deadline)
+	defer cancel()
+
+	backoff := recordBackoff
+	for {
+		err := q.txRepo.RecordExecution(recordCtx, attempt, int64(result.GasLimit))
+		if err == nil {
+			return
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			q.log.WithContext(ctx).Errorw("msg", "failed to record execution, transaction may be broadcast again after the lease expires",
+				"task_id", tx.TaskID, "tx_hash", result.TxHash, "nonce", result.Nonce, "raw_tx", result.RawTx, "error", err)
+			return
+		}
+		q.log.WithContext(ctx).Warnw("msg", "failed to record execution, retrying", "task_id", tx.TaskID, "tx_hash", result.TxHash, "error", err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// switchOperator 切换到其他 Operator 重新排队（没有可用 Operator 时退避后在原 Operator 重试）
+// Proxy Wallet 中继交易的签名绑定了发送方，只能退避后在原 Operator 重试
+func (q *queue) switchOperator(ctx context.Context, tx *data.Transaction, category executor.ErrorCategory, cause error) {
+	if tx.TransactionType == "PROXY_RELAY" {
+		q.retryLater(ctx, tx, tx.FromAddress, category, cause)
+		return
+	}
+	operator, err := q.selector.Select(ctx, &selector.SelectRequest{
+		BuilderAPIKey: tx.BuilderAPIKey,
+		Exclude:       []string{tx.FromAddress},
+	})
+	if err != nil {
+		q.log.WithContext(ctx).Warnw("msg", "no alternative operator available", "task_id", tx.TaskID, "error", err)
+		q.retryLater(ctx, tx, tx.FromAddress, category, cause)
+		return
+	}
+
+	if err := q.txRepo.ScheduleRetry(ctx, tx.TaskID, operator.Address, tx.RetryCount, executor.FormatError(category, cause), time.Now()); err != nil {
+		q.log.WithContext(ctx).Errorw("msg", "failed to reassign task", "task_id", tx.TaskID, "error", err)
+		return
+	}
+	q.log.WithContext(ctx).Infow("msg", "task reassigned to another operator", "task_id", tx.TaskID, "from", tx.FromAddress, "to", operator.Address)
+	q.notify(operator.Address)
+}
+
+// retryLater 退避后重新排队（间隔按重试次数翻倍）
+func (q *queue) retryLater(ctx context.Context, tx *data.Transaction, fromAddress string, category executor.ErrorCategory, cause error) {
+	if err := q.txRepo.ScheduleRetry(ctx, tx.TaskID, fromAddress, tx.RetryCount, executor.FormatError(category, cause), time.Now().Add(retryDelay(tx.RetryCount))); err != nil {
+		q.log.WithContext(ctx).Errorw("msg", "failed to schedule retry", "task_id", tx.TaskID, "error", err)
+	}
+}
+
+// retryDelay 第 retryCount 次重试前的退避间隔（retryBackoff 起按次数翻倍，不超过 maxRetryBackoff）
+func retryDelay(retryCount int32) time.Duration {
+	shift := min(max(retryCount-1, 0), maxBackoffShift)
+	return min(retryBackoff<<shift, maxRetryBackoff)
+}
+
+// wait 等待 feeCapWait 后重新排队（不计入重试次数）
+func (q *queue) wait(ctx context.Context, tx *data.Transaction, category executor.ErrorCategory, cause error) {
+	q.log.WithContext(ctx).Infow("msg", "fee above cap, waiting", "task_id", tx.TaskID, "transaction_type", tx.TransactionType, "error", cause)
+	if err := q.txRepo.ScheduleRetry(ctx, tx.TaskID, tx.FromAddress, tx.RetryCount, executor.FormatError(category, cause), time.Now().Add(feeCapWait)); err != nil {
+		q.log.WithContext(ctx).Errorw("msg", "failed to schedule retry", "task_id", tx.TaskID, "error", err)
+	}
+}
+
+// requeue 放回队列（不计入重试次数）
+func (q *queue) requeue(ctx context.Context, tx *data.Transaction) {
+	if err := q.txRepo.Requeue(ctx, tx.TaskID); err != nil {
+		q.log.WithContext(ctx).Errorw("msg", "failed to requeue task", "task_id", tx.TaskID, "error", err)
+	}
+}
+
+// fail 标记任务失败，错误信息记录为 [CATEGORY] message
+func (q *queue) fail(ctx context.Context, tx *data.Transaction, category executor.ErrorCategory, cause error) {
+	q.log.WithContext(ctx).Errorw("msg", "transaction execution failed", "task_id", tx.TaskID, "operator", tx.FromAddress,
+		"category", category, "retries", tx.RetryCount, "error", cause)
+	if err := q.txRepo.MarkFailed(ctx, tx.TaskID, tx.RetryCount, executor.FormatError(category, cause)); err != nil {
+		q.log.WithContext(ctx).Errorw("msg", "failed to update transaction status", "task_id", tx.TaskID, "error", err)
+	}
+}
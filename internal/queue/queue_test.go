@@ -0,0 +1,146 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/selector"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// fakeTxRepo 内存中的排队任务（仅实现队列转移用到的方法）
+type fakeTxRepo struct {
+	data.TransactionRepo
+	txs map[string]*data.Transaction
+}
+
+func (f *fakeTxRepo) ClaimOrphaned(ctx context.Context, activeAddresses []string, limit int) ([]*data.Transaction, error) {
+	var claimed []*data.Transaction
+	for _, tx := range f.txs {
+		if tx.Status != "QUEUED" || isActive(tx.FromAddress, activeAddresses) || len(claimed) >= limit {
+			continue
+		}
+		tx.Status = "PROCESSING"
+		copied := *tx
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (f *fakeTxRepo) ScheduleRetry(ctx context.Context, taskID string, fromAddress string, retryCount int32, errorMessage string, notBefore time.Time) error {
+	tx := f.txs[taskID]
+	tx.Status, tx.FromAddress, tx.RetryCount, tx.ErrorMessage, tx.NextRetryAt = "QUEUED", fromAddress, retryCount, errorMessage, &notBefore
+	return nil
+}
+
+func (f *fakeTxRepo) MarkFailed(ctx context.Context, taskID string, retryCount int32, errorMessage string) error {
+	tx := f.txs[taskID]
+	tx.Status, tx.RetryCount, tx.ErrorMessage = "FAILED", retryCount, errorMessage
+	return nil
+}
+
+func (f *fakeTxRepo) Requeue(ctx context.Context, taskID string) error {
+	f.txs[taskID].Status = "QUEUED"
+	return nil
+}
+
+func isActive(address string, active []string) bool {
+	for _, a := range active {
+		if strings.EqualFold(a, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// fakeSelector 固定返回 operator，operator 为空时返回 ErrNoOperatorAvailable
+type fakeSelector struct {
+	operator string
+}
+
+func (f *fakeSelector) Select(ctx context.Context, req *selector.SelectRequest) (*data.Operator, error) {
+	if f.operator == "" {
+		return nil, fmt.Errorf("no active operators: %w", selector.ErrNoOperatorAvailable)
+	}
+	return &data.Operator{Address: f.operator}, nil
+}
+
+func TestReassignOrphaned(t *testing.T) {
+	const (
+		active   = "0x00000000000000000000000000000000000000aa"
+		inactive = "0x00000000000000000000000000000000000000bb"
+	)
+	backoff := time.Now().Add(time.Hour)
+	newRepo := func() *fakeTxRepo {
+		return &fakeTxRepo{txs: map[string]*data.Transaction{
+			"queued":   {TaskID: "queued", FromAddress: inactive, Status: "QUEUED", TransactionType: "SAFE"},
+			"backoff":  {TaskID: "backoff", FromAddress: inactive, Status: "QUEUED", TransactionType: "SAFE", RetryCount: 2, ErrorMessage: "[RPC_TRANSIENT] timeout", NextRetryAt: &backoff},
+			"relay":    {TaskID: "relay", FromAddress: inactive, Status: "QUEUED", TransactionType: "PROXY_RELAY"},
+			"active":   {TaskID: "active", FromAddress: active, Status: "QUEUED", TransactionType: "SAFE"},
+			"inflight": {TaskID: "inflight", FromAddress: inactive, Status: "PROCESSING", TransactionType: "SAFE"},
+		}}
+	}
+
+	t.Run("reassigns to an active operator", func(t *testing.T) {
+		repo := newRepo()
+		q := NewQueue(Config{}, repo, nil, nil, nil, &fakeSelector{operator: active}, log.DefaultLogger).(*queue)
+		q.reassignOrphaned(context.Background(), []string{active})
+
+		for _, id := range []string{"queued", "backoff", "active"} {
+			if tx := repo.txs[id]; tx.Status != "QUEUED" || tx.FromAddress != active {
+				t.Fatalf("%s = %s on %s; want QUEUED on %s", id, tx.Status, tx.FromAddress, active)
+			}
+		}
+		// 保留退避时间和错误信息
+		if tx := repo.txs["backoff"]; !tx.NextRetryAt.Equal(backoff) || tx.RetryCount != 2 || tx.ErrorMessage != "[RPC_TRANSIENT] timeout" {
+			t.Fatalf("backoff task = %+v", tx)
+		}
+		// 中继交易绑定了原 Operator，直接失败
+		if tx := repo.txs["relay"]; tx.Status != "FAILED" || !strings.Contains(tx.ErrorMessage, "no longer active") {
+			t.Fatalf("relay task = %s %q; want FAILED", tx.Status, tx.ErrorMessage)
+		}
+		// 执行中的任务不受影响
+		if tx := repo.txs["inflight"]; tx.Status != "PROCESSING" || tx.FromAddress != inactive {
+			t.Fatalf("inflight task = %s on %s", tx.Status, tx.FromAddress)
+		}
+	})
+
+	t.Run("no operator available", func(t *testing.T) {
+		repo := newRepo()
+		q := NewQueue(Config{}, repo, nil, nil, nil, &fakeSelector{}, log.DefaultLogger).(*queue)
+		q.reassignOrphaned(context.Background(), nil)
+
+		// 无法转移的任务放回原 Operator 的队列，下次刷新再转移
+		for _, id := range []string{"queued", "backoff"} {
+			if tx := repo.txs[id]; tx.Status != "QUEUED" || tx.FromAddress != inactive {
+				t.Fatalf("%s = %s on %s; want QUEUED on %s", id, tx.Status, tx.FromAddress, inactive)
+			}
+		}
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		retryCount int32
+		want       time.Duration
+	}{
+		{retryCount: -1, want: retryBackoff},
+		{retryCount: 0, want: retryBackoff},
+		{retryCount: 1, want: retryBackoff},
+		{retryCount: 2, want: 2 * retryBackoff},
+		{retryCount: 5, want: 16 * retryBackoff},
+		{retryCount: 6, want: maxRetryBackoff},
+		{retryCount: 64, want: maxRetryBackoff},
+		{retryCount: 1 << 30, want: maxRetryBackoff},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.retryCount); got != tt.want {
+			t.Fatalf("retryDelay(%d) = %v; want %v", tt.retryCount, got, tt.want)
+		}
+	}
+}
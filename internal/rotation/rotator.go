@@ -0,0 +1,184 @@
+package rotation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/kms"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// Rotator 主密钥轮换器接口
+// 将 operator.private_key_encrypted 与 Builder 凭证重新加密到 KMS 当前激活的密钥下
+type Rotator interface {
+	// Rotate 执行一次轮换（幂等，可在多个副本上并发执行）
+	Rotate(ctx context.Context) (*Report, error)
+}
+
+// Report 轮换结果
+type Report struct {
+	OperatorsRotated int // 重新加密的 Operator 数
+	BuildersRotated  int // 重新加密的 Builder 数
+	Skipped          int // 已是激活密钥或被并发修改而跳过的记录数
+}
+
+// rotator Rotator 实现
+type rotator struct {
+	kms          kms.KMS
+	operatorRepo data.OperatorRepo
+	builderRepo  data.BuilderRepo
+	log          *log.Helper
+}
+
+// NewRotator 创建主密钥轮换器
+func NewRotator(k kms.KMS, operatorRepo data.OperatorRepo, builderRepo data.BuilderRepo, logger log.Logger) Rotator {
+	return &rotator{
+		kms:          k,
+		operatorRepo: operatorRepo,
+		builderRepo:  builderRepo,
+		log:          log.NewHelper(log.With(logger, "module", "rotation")),
+	}
+}
+
+// Rotate 执行一次轮换
+// 旧密钥在轮换期间保留用于解密，每条记录使用 CAS 更新，运行中的副本不受影响
+func (r *rotator) Rotate(ctx context.Context) (*Report, error) {
+	report := &Report{}
+	var errs []error
+
+	operators, err := r.operatorRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operators: %w", err)
+	}
+	for _, op := range operators {
+		rotated, err := r.rotateOperator(ctx, op)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("operator %s: %w", op.Address, err))
+			continue
+		}
+		if rotated {
+			report.OperatorsRotated++
+		} else {
+			report.Skipped++
+		}
+	}
+
+	builders, err := r.builderRepo.GetAll(ctx)
+	if err != nil {
+		return report, errors.Join(append(errs, fmt.Errorf("failed to get builders: %w", err))...)
+	}
+	for _, b := range builders {
+		rotated, err := r.rotateBuilder(ctx, b)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("builder %s: %w", b.APIKey, err))
+			continue
+		}
+		if rotated {
+			report.BuildersRotated++
+		} else {
+			report.Skipped++
+		}
+	}
+
+	r.log.WithContext(ctx).Infow("msg", "key rotation finished",
+		"operators_rotated", report.OperatorsRotated, "builders_rotated", report.BuildersRotated,
+		"skipped", report.Skipped, "errors", len(errs))
+	return report, errors.Join(errs...)
+}
+
+// rotateOperator 重新加密 Operator 私钥（KMS 托管签名的 Operator 没有私钥密文，跳过）
+func (r *rotator) rotateOperator(ctx context.Context, op *data.Operator) (bool, error) {
+	if op.PrivateKeyEncrypted == "" {
+		return false, nil
+	}
+	needed, err := r.needsRotation(ctx, op.PrivateKeyEncrypted)
+	if err != nil || !needed {
+		return false, err
+	}
+
+	reencrypted, err := r.reencryptCiphertext(ctx, op.PrivateKeyEncrypted)
+	if err != nil {
+		return false, fmt.Errorf("private key: %w", err)
+	}
+
+	return r.operatorRepo.UpdatePrivateKey(ctx, op.Address, op.PrivateKeyEncrypted, reencrypted)
+}
+
+// rotateBuilder 重新加密 Builder 的 Secret 和 Passphrase
+func (r *rotator) rotateBuilder(ctx context.Context, b *data.Builder) (bool, error) {
+	secretNeeded, err := r.needsRotation(ctx, b.SecretHash)
+	if err != nil {
+		return false, err
+	}
+	passphraseNeeded, err := r.needsRotation(ctx, b.PassphraseHash)
+	if err != nil {
+		return false, err
+	}
+	if !secretNeeded && !passphraseNeeded {
+		return false, nil
+	}
+
+	secret, err := r.reencryptBuilderValue(ctx, b.SecretHash)
+	if err != nil {
+		return false, fmt.Errorf("secret: %w", err)
+	}
+	passphrase, err := r.reencryptBuilderValue(ctx, b.PassphraseHash)
+	if err != nil {
+		return false, fmt.Errorf("passphrase: %w", err)
+	}
+
+	return r.builderRepo.UpdateSecrets(ctx, b.APIKey, b.SecretHash, b.PassphraseHash, secret, passphrase)
+}
+
+// reencryptBuilderValue 重新加密 Builder 凭证
+// 显式标记为历史明文（plain: 前缀）的值直接加密；其余值无法解密时拒绝，不把密文当明文重新加密
+func (r *rotator) reencryptBuilderValue(ctx context.Context, value string) (string, error) {
+	if plain, ok := kms.ParseLegacyPlaintext(value); ok {
+		return r.reencrypt(ctx, plain)
+	}
+	return r.reencryptCiphertext(ctx, value)
+}
+
+// reencryptCiphertext 将密文重新加密到激活密钥：KMS 支持 Rewrap 时在 KMS 内完成（明文不离开 KMS），否则解密后重新加密
+func (r *rotator) reencryptCiphertext(ctx context.Context, encrypted string) (string, error) {
+	if rw, ok := r.kms.(kms.Rewrapper); ok {
+		rewrapped, err := rw.Rewrap(ctx, encrypted)
+		if err != nil {
+			return "", fmt.Errorf("failed to rewrap: %w", err)
+		}
+		return rewrapped, nil
+	}
+	plain, err := r.kms.Decrypt(ctx, encrypted)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return r.reencrypt(ctx, plain)
+}
+
+// reencrypt 使用激活密钥加密，并回读校验
+func (r *rotator) reencrypt(ctx context.Context, plain string) (string, error) {
+	encrypted, err := r.kms.Encrypt(ctx, plain)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt: %w", err)
+	}
+	check, err := r.kms.Decrypt(ctx, encrypted)
+	if err != nil || check != plain {
+		return "", fmt.Errorf("re-encrypted value failed verification")
+	}
+	return encrypted, nil
+}
+
+// needsRotation 是否需要轮换（KMS 不支持判断时总是重新加密）
+func (r *rotator) needsRotation(ctx context.Context, encrypted string) (bool, error) {
+	if kr, ok := r.kms.(kms.KeyRotator); ok {
+		needed, err := kr.NeedsRotation(ctx, encrypted)
+		if err != nil {
+			return false, fmt.Errorf("failed to check key version: %w", err)
+		}
+		return needed, nil
+	}
+	return true, nil
+}
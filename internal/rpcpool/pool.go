@@ -0,0 +1,509 @@
+package rpcpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// Config RPC 池配置
+type Config struct {
+	URLs                []string      // RPC 地址（第一个为主节点，评分相同时优先）
+	HealthCheckInterval time.Duration // 健康检查间隔
+	CheckTimeout        time.Duration // 单次健康检查超时
+	MaxHeadLag          uint64        // 区块高度落后最高节点超过该值视为不健康
+	MaxFailures         int           // 连续调用失败次数达到该值视为不健康（下次健康检查成功后恢复）
+	LagPenalty          time.Duration // 评分时每落后一个区块折算的延迟
+}
+
+// latencyWeight 延迟指数移动平均中最新一次测量的权重
+const latencyWeight = 0.3
+
+// Pool 多 RPC 节点客户端池
+// 读请求按健康评分（延迟 + 区块高度滞后）选择节点，节点错误时自动切换到下一个节点；
+// 依赖节点交易池状态的请求（pending Nonce、pending 状态模拟和估算）按账户固定到同一节点；
+// 广播发往所有健康节点，任一节点接受即视为成功
+type Pool struct {
+	cfg       Config
+	endpoints []*endpoint
+	log       *log.Helper
+
+	mu     sync.Mutex
+	sticky map[common.Address]*endpoint // 账户 -> 固定节点
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// endpoint 单个 RPC 节点及其健康状态
+type endpoint struct {
+	url    string
+	client *ethclient.Client
+
+	mu       sync.Mutex
+	checked  bool          // 是否完成过健康检查
+	reached  bool          // 最近一次健康检查是否成功
+	latency  time.Duration // 健康检查延迟（指数移动平均）
+	head     uint64        // 最近一次健康检查的区块高度
+	lag      uint64        // 落后最高节点的区块数
+	failures int           // 连续调用失败次数
+}
+
+// NewPool 创建 RPC 池，完成一次健康检查后在后台定期检查
+func NewPool(cfg Config, logger log.Logger) (*Pool, error) {
+	if len(cfg.URLs) == 0 {
+		return nil, fmt.Errorf("at least one rpc url is required")
+	}
+
+	p := &Pool{
+		cfg:    cfg,
+		log:    log.NewHelper(log.With(logger, "module", "rpcpool")),
+		sticky: make(map[common.Address]*endpoint),
+		stopCh: make(chan struct{}),
+	}
+	for _, url := range cfg.URLs {
+		client, err := ethclient.Dial(url)
+		if err != nil {
+			p.closeClients()
+			return nil, fmt.Errorf("failed to connect to rpc %s: %w", url, err)
+		}
+		p.endpoints = append(p.endpoints, &endpoint{url: url, client: client})
+	}
+
+	p.checkAll(context.Background())
+	p.wg.Add(1)
+	go p.healthLoop()
+	return p, nil
+}
+
+// Close 停止健康检查并关闭所有节点连接
+func (p *Pool) Close() {
+	close(p.stopCh)
+	p.wg.Wait()
+	p.closeClients()
+}
+
+// closeClients 关闭所有节点连接
+func (p *Pool) closeClients() {
+	for _, e := range p.endpoints {
+		e.client.Close()
+	}
+}
+
+// healthLoop 定期健康检查
+func (p *Pool) healthLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ticker.C:
+			p.checkAll(context.Background())
+		}
+	}
+}
+
+// checkAll 并发查询所有节点的区块高度，更新延迟、高度滞后和可达状态
+func (p *Pool) checkAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.check(ctx, e)
+		}()
+	}
+	wg.Wait()
+
+	var maxHead uint64
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		if e.reached && e.head > maxHead {
+			maxHead = e.head
+		}
+		e.mu.Unlock()
+	}
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		wasHealthy := e.healthyLocked(p.cfg)
+		if e.reached {
+			e.lag = maxHead - e.head
+		}
+		healthy := e.healthyLocked(p.cfg)
+		url, head, lag, latency := e.url, e.head, e.lag, e.latency
+		e.mu.Unlock()
+
+		if wasHealthy && !healthy {
+			p.log.Warnw("msg", "rpc endpoint marked unhealthy", "url", url, "head", head, "lag", lag, "latency", latency)
+		} else if !wasHealthy && healthy {
+			p.log.Infow("msg", "rpc endpoint recovered", "url", url, "head", head, "latency", latency)
+		}
+	}
+}
+
+// check 查询单个节点的区块高度
+func (p *Pool) check(ctx context.Context, e *endpoint) {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.CheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	head, err := e.client.BlockNumber(ctx)
+	elapsed := time.Since(start)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err != nil {
+		if !e.checked || e.reached {
+			p.log.Warnw("msg", "rpc endpoint health check failed", "url", e.url, "error", err)
+		}
+		e.checked, e.reached = true, false
+		return
+	}
+	if !e.checked || !e.reached {
+		e.latency = elapsed
+	} else {
+		e.latency = time.Duration(latencyWeight*float64(elapsed) + (1-latencyWeight)*float64(e.latency))
+	}
+	e.checked, e.reached = true, true
+	e.head = head
+	e.failures = 0
+}
+
+// healthyLocked 节点是否健康（调用方持有 e.mu）
+func (e *endpoint) healthyLocked(cfg Config) bool {
+	return e.reached && e.lag <= cfg.MaxHeadLag && e.failures < cfg.MaxFailures
+}
+
+// healthy 节点是否健康
+func (e *endpoint) healthy(cfg Config) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.healthyLocked(cfg)
+}
+
+// score 健康评分（越小越好）：延迟 + 高度滞后折算的延迟
+func (e *endpoint) score(cfg Config) time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.latency + time.Duration(e.lag)*cfg.LagPenalty
+}
+
+// recordResult 记录调用结果：节点错误计入连续失败次数，成功时清零
+func (p *Pool) recordResult(e *endpoint, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err == nil {
+		e.failures = 0
+		return
+	}
+	wasHealthy := e.healthyLocked(p.cfg)
+	e.failures++
+	if wasHealthy && !e.healthyLocked(p.cfg) {
+		p.log.Warnw("msg", "rpc endpoint marked unhealthy after consecutive failures", "url", e.url, "failures", e.failures, "error", err)
+	}
+}
+
+// ordered 按健康评分排序的节点（健康节点在前；没有健康节点时仍返回所有节点，尽力而为）
+func (p *Pool) ordered() []*endpoint {
+	var healthy, unhealthy []*endpoint
+	for _, e := range p.endpoints {
+		if e.healthy(p.cfg) {
+			healthy = append(healthy, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+	sortByScore(healthy, p.cfg)
+	sortByScore(unhealthy, p.cfg)
+	return append(healthy, unhealthy...)
+}
+
+// sortByScore 按评分排序（评分相同时保持配置顺序）
+func sortByScore(endpoints []*endpoint, cfg Config) {
+	scores := make(map[*endpoint]time.Duration, len(endpoints))
+	for _, e := range endpoints {
+		scores[e] = e.score(cfg)
+	}
+	sort.SliceStable(endpoints, func(i, j int) bool { return scores[endpoints[i]] < scores[endpoints[j]] })
+}
+
+// byHead 按区块高度从高到低排序的节点（健康节点在前，高度相同时按健康评分）
+// 用于查询规范链区块头：落后节点上较新的区块不存在，分叉节点返回的区块哈希过期，会被误判为链重组
+func (p *Pool) byHead() []*endpoint {
+	endpoints := p.ordered()
+	heads := make(map[*endpoint]uint64, len(endpoints))
+	healthy := make(map[*endpoint]bool, len(endpoints))
+	for _, e := range endpoints {
+		e.mu.Lock()
+		if e.reached {
+			heads[e] = e.head
+		}
+		healthy[e] = e.healthyLocked(p.cfg)
+		e.mu.Unlock()
+	}
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		a, b := endpoints[i], endpoints[j]
+		if healthy[a] != healthy[b] {
+			return healthy[a]
+		}
+		return heads[a] > heads[b]
+	})
+	return endpoints
+}
+
+// stickyOrdered 账户固定节点优先的节点顺序（固定节点不健康时重新固定到评分最好的节点）
+func (p *Pool) stickyOrdered(account common.Address) []*endpoint {
+	endpoints := p.ordered()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pinned, ok := p.sticky[account]
+	if !ok || !pinned.healthy(p.cfg) {
+		pinned = endpoints[0]
+		p.sticky[account] = pinned
+	}
+
+	result := make([]*endpoint, 0, len(endpoints))
+	result = append(result, pinned)
+	for _, e := range endpoints {
+		if e != pinned {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// repin 固定节点调用失败后将账户固定到实际成功的节点
+func (p *Pool) repin(account common.Address, e *endpoint) {
+	p.mu.Lock()
+	p.sticky[account] = e
+	p.mu.Unlock()
+}
+
+// do 依次在节点上执行调用，节点错误时切换到下一个节点，返回成功的节点
+func (p *Pool) do(ctx context.Context, endpoints []*endpoint, call func(*ethclient.Client) error) (*endpoint, error) {
+	var lastErr error
+	for _, e := range endpoints {
+		err := call(e.client)
+		if !isEndpointError(ctx, err) {
+			p.recordResult(e, nil)
+			return e, err
+		}
+		p.recordResult(e, err)
+		p.log.WithContext(ctx).Debugw("msg", "rpc call failed, trying next endpoint", "url", e.url, "error", err)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// isEndpointError 是否为节点自身的错误（网络错误、超时、限流、5xx、节点状态落后），切换节点后可能成功
+// 节点正常返回的 JSON-RPC 错误（例如回滚、Nonce 过低）由交易本身决定，切换节点没有意义
+func isEndpointError(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil || errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		msg := strings.ToLower(rpcErr.Error())
+		return strings.Contains(msg, "header not found") ||
+			strings.Contains(msg, "rate limit") ||
+			strings.Contains(msg, "too many requests")
+	}
+	return true
+}
+
+// BlockNumber 查询最新区块高度
+func (p *Pool) BlockNumber(ctx context.Context) (uint64, error) {
+	var head uint64
+	_, err := p.do(ctx, p.ordered(), func(c *ethclient.Client) (err error) {
+		head, err = c.BlockNumber(ctx)
+		return err
+	})
+	return head, err
+}
+
+// HeaderByNumber 查询区块头（优先使用区块高度最高的节点，用于链重组检测）
+func (p *Pool) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var header *types.Header
+	_, err := p.do(ctx, p.byHead(), func(c *ethclient.Client) (err error) {
+		header, err = c.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+// BalanceAt 查询账户余额
+func (p *Pool) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	var balance *big.Int
+	_, err := p.do(ctx, p.ordered(), func(c *ethclient.Client) (err error) {
+		balance, err = c.BalanceAt(ctx, account, blockNumber)
+		return err
+	})
+	return balance, err
+}
+
+// NonceAt 查询账户在指定区块的 Nonce
+func (p *Pool) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	var nonce uint64
+	_, err := p.do(ctx, p.ordered(), func(c *ethclient.Client) (err error) {
+		nonce, err = c.NonceAt(ctx, account, blockNumber)
+		return err
+	})
+	return nonce, err
+}
+
+// PendingNonceAt 查询账户的 pending Nonce（固定节点：不同节点交易池内容不同）
+func (p *Pool) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	var nonce uint64
+	e, err := p.do(ctx, p.stickyOrdered(account), func(c *ethclient.Client) (err error) {
+		nonce, err = c.PendingNonceAt(ctx, account)
+		return err
+	})
+	if e != nil {
+		p.repin(account, e)
+	}
+	return nonce, err
+}
+
+// FeeHistory 查询历史费用
+func (p *Pool) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	var history *ethereum.FeeHistory
+	_, err := p.do(ctx, p.ordered(), func(c *ethclient.Client) (err error) {
+		history, err = c.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+		return err
+	})
+	return history, err
+}
+
+// CallContract 在指定区块状态上执行 eth_call
+func (p *Pool) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result []byte
+	_, err := p.do(ctx, p.ordered(), func(c *ethclient.Client) (err error) {
+		result, err = c.CallContract(ctx, msg, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+// PendingCallContract 在 pending 状态上执行 eth_call（按 msg.From 固定节点）
+func (p *Pool) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	var result []byte
+	e, err := p.do(ctx, p.stickyOrdered(msg.From), func(c *ethclient.Client) (err error) {
+		result, err = c.PendingCallContract(ctx, msg)
+		return err
+	})
+	if e != nil {
+		p.repin(msg.From, e)
+	}
+	return result, err
+}
+
+// EstimateGas 估算 Gas Limit（基于 pending 状态，按 msg.From 固定节点）
+func (p *Pool) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	var gasLimit uint64
+	e, err := p.do(ctx, p.stickyOrdered(msg.From), func(c *ethclient.Client) (err error) {
+		gasLimit, err = c.EstimateGas(ctx, msg)
+		return err
+	})
+	if e != nil {
+		p.repin(msg.From, e)
+	}
+	return gasLimit, err
+}
+
+// BatchCallContext 批量 JSON-RPC 请求（单个请求的错误保存在对应元素中）
+func (p *Pool) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	_, err := p.do(ctx, p.ordered(), func(c *ethclient.Client) error {
+		return c.Client().BatchCallContext(ctx, batch)
+	})
+	return err
+}
+
+// SendTransaction 广播交易到所有健康节点（没有健康节点时广播到所有节点）
+// 任一节点接受（或已持有该交易）即视为成功；全部失败时返回发送方固定节点的错误，用于错误分类
+func (p *Pool) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	var targets []*endpoint
+	for _, e := range p.endpoints {
+		if e.healthy(p.cfg) {
+			targets = append(targets, e)
+		}
+	}
+	if len(targets) == 0 {
+		targets = p.endpoints
+	}
+
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, e := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := e.client.SendTransaction(ctx, tx)
+			if err != nil && isAlreadyKnown(err) {
+				err = nil
+			}
+			p.recordResult(e, endpointErr(ctx, err))
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+
+	// 优先返回发送方固定节点（与 Nonce 分配一致）的错误，其次返回第一个非节点错误
+	if from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+		p.mu.Lock()
+		pinned := p.sticky[from]
+		p.mu.Unlock()
+		for i, e := range targets {
+			if e == pinned {
+				return errs[i]
+			}
+		}
+	}
+	for _, err := range errs {
+		if !isEndpointError(ctx, err) {
+			return err
+		}
+	}
+	return errs[0]
+}
+
+// endpointErr 仅保留节点错误（用于健康统计）
+func endpointErr(ctx context.Context, err error) error {
+	if isEndpointError(ctx, err) {
+		return err
+	}
+	return nil
+}
+
+// isAlreadyKnown 节点是否已持有该交易（其他节点已广播到该节点的交易池）
+func isAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
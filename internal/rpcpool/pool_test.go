@@ -0,0 +1,296 @@
+package rpcpool
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// fakeNode JSON-RPC 节点 stand-in（eth_blockNumber / eth_getBlockByNumber / eth_getTransactionCount / eth_sendRawTransaction）
+type fakeNode struct {
+	mu      sync.Mutex
+	head    uint64
+	fork    byte   // 区块头 extraData，区分不同分叉上的同高度区块
+	nonce   uint64 // eth_getTransactionCount 返回值
+	down    bool   // 返回 HTTP 503
+	sendErr string // eth_sendRawTransaction 返回的 JSON-RPC 错误
+	calls   map[string]int
+}
+
+func (f *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[req.Method]++
+	if f.down {
+		http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	switch req.Method {
+	case "eth_blockNumber":
+		resp["result"] = hexutil.Uint64(f.head)
+	case "eth_getBlockByNumber":
+		var tag string
+		_ = json.Unmarshal(req.Params[0], &tag)
+		number := f.head
+		if tag != "latest" {
+			number, _ = hexutil.DecodeUint64(tag)
+		}
+		if number > f.head {
+			resp["result"] = nil
+			break
+		}
+		resp["result"] = &types.Header{Number: new(big.Int).SetUint64(number), Difficulty: new(big.Int), Extra: []byte{f.fork}}
+	case "eth_getTransactionCount":
+		resp["result"] = hexutil.Uint64(f.nonce)
+	case "eth_sendRawTransaction":
+		if f.sendErr != "" {
+			resp["error"] = map[string]interface{}{"code": -32000, "message": f.sendErr}
+			break
+		}
+		var raw hexutil.Bytes
+		_ = json.Unmarshal(req.Params[0], &raw)
+		tx := new(types.Transaction)
+		_ = tx.UnmarshalBinary(raw)
+		resp["result"] = tx.Hash()
+	default:
+		resp["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (f *fakeNode) set(fn func(f *fakeNode)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fn(f)
+}
+
+// count 返回并清零方法的调用次数
+func (f *fakeNode) count(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := f.calls[method]
+	f.calls[method] = 0
+	return n
+}
+
+// newTestPool 启动节点 stand-in 并创建 RPC 池（不依赖后台健康检查，由测试调用 checkAll）
+// 每落后一个区块折算 1 小时延迟，评分只由高度决定，与本地请求延迟无关
+func newTestPool(t *testing.T, nodes ...*fakeNode) *Pool {
+	t.Helper()
+	var urls []string
+	for _, n := range nodes {
+		n.calls = make(map[string]int)
+		srv := httptest.NewServer(n)
+		t.Cleanup(srv.Close)
+		urls = append(urls, srv.URL)
+	}
+	p, err := NewPool(Config{
+		URLs:                urls,
+		HealthCheckInterval: time.Hour,
+		CheckTimeout:        time.Second,
+		MaxHeadLag:          5,
+		MaxFailures:         2,
+		LagPenalty:          time.Hour,
+	}, log.DefaultLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(p.Close)
+	for _, n := range nodes {
+		n.count("eth_blockNumber") // 不统计健康检查
+	}
+	return p
+}
+
+func TestPoolFailover(t *testing.T) {
+	primary, backup := &fakeNode{head: 100}, &fakeNode{head: 99}
+	p := newTestPool(t, primary, backup)
+	ctx := context.Background()
+
+	if head, err := p.BlockNumber(ctx); err != nil || head != 100 {
+		t.Fatalf("BlockNumber = %d, %v; want 100 from primary", head, err)
+	}
+
+	// 主节点宕机：切换到备用节点，连续失败 MaxFailures 次后不再请求主节点
+	primary.set(func(f *fakeNode) { f.down = true })
+	for i := 0; i < 3; i++ {
+		if head, err := p.BlockNumber(ctx); err != nil || head != 99 {
+			t.Fatalf("BlockNumber after primary loss = %d, %v; want 99 from backup", head, err)
+		}
+	}
+	if n := primary.count("eth_blockNumber"); n != 3 {
+		t.Fatalf("primary called %d times, want 3 (2 failures + the first call)", n)
+	}
+
+	// 所有节点宕机：返回错误
+	backup.set(func(f *fakeNode) { f.down = true })
+	if _, err := p.BlockNumber(ctx); err == nil {
+		t.Fatal("BlockNumber succeeded with every endpoint down")
+	}
+
+	// 健康检查成功后主节点恢复
+	primary.set(func(f *fakeNode) { f.down = false })
+	backup.set(func(f *fakeNode) { f.down = false })
+	p.checkAll(ctx)
+	primary.count("eth_blockNumber")
+	if head, err := p.BlockNumber(ctx); err != nil || head != 100 || primary.count("eth_blockNumber") != 1 {
+		t.Fatalf("BlockNumber after recovery = %d, %v; want 100 from primary", head, err)
+	}
+}
+
+func TestPoolHeadLagEviction(t *testing.T) {
+	lagging, current := &fakeNode{head: 100}, &fakeNode{head: 200}
+	p := newTestPool(t, lagging, current)
+	ctx := context.Background()
+
+	if p.endpoints[0].healthy(p.cfg) {
+		t.Fatal("endpoint lagging 100 blocks is healthy")
+	}
+	if head, err := p.BlockNumber(ctx); err != nil || head != 200 {
+		t.Fatalf("BlockNumber = %d, %v; want 200", head, err)
+	}
+	if n := lagging.count("eth_blockNumber"); n != 0 {
+		t.Fatalf("lagging endpoint called %d times", n)
+	}
+
+	// 追上最高节点后恢复
+	lagging.set(func(f *fakeNode) { f.head = 198 })
+	p.checkAll(ctx)
+	if !p.endpoints[0].healthy(p.cfg) {
+		t.Fatal("endpoint within max head lag is unhealthy")
+	}
+}
+
+func TestPoolHeaderByNumberUsesHighestHead(t *testing.T) {
+	// 两个节点都健康（落后 3 个区块），落后节点仍在旧分叉上
+	lagging, current := &fakeNode{head: 100, fork: 1}, &fakeNode{head: 103, fork: 2}
+	p := newTestPool(t, lagging, current)
+	ctx := context.Background()
+
+	// 普通读请求按评分优先使用延迟低的落后节点
+	p.endpoints[0].latency, p.endpoints[1].latency = time.Millisecond, 500*time.Millisecond
+	p.cfg.LagPenalty = 0
+	if head, _ := p.BlockNumber(ctx); head != 100 {
+		t.Fatalf("BlockNumber = %d, want 100 from the lower-latency endpoint", head)
+	}
+
+	for _, number := range []int64{100, 102} {
+		header, err := p.HeaderByNumber(ctx, big.NewInt(number))
+		if err != nil {
+			t.Fatalf("HeaderByNumber(%d): %v", number, err)
+		}
+		if header.Number.Int64() != number || header.Extra[0] != 2 {
+			t.Fatalf("HeaderByNumber(%d) from fork %d, want fork 2 (highest head)", number, header.Extra[0])
+		}
+	}
+	if n := lagging.count("eth_getBlockByNumber"); n != 0 {
+		t.Fatalf("lagging endpoint served %d header lookups", n)
+	}
+
+	// 最高节点宕机时回退到其他节点
+	current.set(func(f *fakeNode) { f.down = true })
+	if header, err := p.HeaderByNumber(ctx, big.NewInt(100)); err != nil || header.Extra[0] != 1 {
+		t.Fatalf("HeaderByNumber with highest endpoint down = %v, %v", header, err)
+	}
+}
+
+func TestPoolStickyPendingCalls(t *testing.T) {
+	a, b := &fakeNode{head: 100, nonce: 5}, &fakeNode{head: 100, nonce: 7}
+	p := newTestPool(t, a, b)
+	ctx := context.Background()
+	account := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	first, err := p.PendingNonceAt(ctx, account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinned, other := a, b
+	if first == b.nonce {
+		pinned, other = b, a
+	}
+	// 评分变化不影响固定节点
+	for i := 0; i < 5; i++ {
+		p.endpoints[0].latency, p.endpoints[1].latency = p.endpoints[1].latency, p.endpoints[0].latency
+		if n, err := p.PendingNonceAt(ctx, account); err != nil || n != first {
+			t.Fatalf("PendingNonceAt = %d, %v; want %d from pinned endpoint", n, err, first)
+		}
+	}
+	if n := other.count("eth_getTransactionCount"); n != 0 {
+		t.Fatalf("other endpoint served %d pending calls", n)
+	}
+
+	// 固定节点失败：切换并重新固定到成功的节点，固定节点恢复后仍使用新节点
+	pinned.set(func(f *fakeNode) { f.down = true })
+	if n, err := p.PendingNonceAt(ctx, account); err != nil || n != other.nonce {
+		t.Fatalf("PendingNonceAt after pinned loss = %d, %v; want %d", n, err, other.nonce)
+	}
+	pinned.set(func(f *fakeNode) { f.down = false })
+	pinned.count("eth_getTransactionCount")
+	if n, err := p.PendingNonceAt(ctx, account); err != nil || n != other.nonce {
+		t.Fatalf("PendingNonceAt after repin = %d, %v; want %d", n, err, other.nonce)
+	}
+	if n := pinned.count("eth_getTransactionCount"); n != 0 {
+		t.Fatalf("previously pinned endpoint served %d calls after repin", n)
+	}
+}
+
+func TestPoolBroadcastFanOut(t *testing.T) {
+	a, b, lagging := &fakeNode{head: 100}, &fakeNode{head: 100}, &fakeNode{head: 10}
+	p := newTestPool(t, a, b, lagging)
+	ctx := context.Background()
+
+	key, _ := crypto.GenerateKey()
+	to := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(137)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(137), Nonce: 1, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 21000, To: &to, Value: new(big.Int),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 发往所有健康节点，落后节点不参与
+	if err := p.SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("SendTransaction: %v", err)
+	}
+	if a.count("eth_sendRawTransaction") != 1 || b.count("eth_sendRawTransaction") != 1 || lagging.count("eth_sendRawTransaction") != 0 {
+		t.Fatal("transaction not broadcast to exactly the healthy endpoints")
+	}
+
+	// 任一节点接受或已持有即成功
+	a.set(func(f *fakeNode) { f.down = true })
+	b.set(func(f *fakeNode) { f.sendErr = "already known" })
+	if err := p.SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("SendTransaction with one endpoint down and one already knowing the tx: %v", err)
+	}
+
+	// 全部拒绝：返回节点的 JSON-RPC 错误（而不是节点不可用错误），用于错误分类
+	b.set(func(f *fakeNode) { f.sendErr = "nonce too low" })
+	if err := p.SendTransaction(ctx, tx); err == nil || !strings.Contains(err.Error(), "nonce too low") {
+		t.Fatalf("SendTransaction rejected everywhere: err = %v; want nonce too low", err)
+	}
+}
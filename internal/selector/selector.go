@@ -0,0 +1,274 @@
+package selector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/big"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"prediction-relayer-service/internal/data"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// ErrNoOperatorAvailable 没有可选的 Operator（没有激活的 Operator，或都被排除、余额不足）
+var ErrNoOperatorAvailable = errors.New("no operator available")
+
+// OperatorSelector Operator 选择策略接口
+type OperatorSelector interface {
+	// Select 为一次提交选择 Operator（余额低于 BalanceThreshold 的 Operator 不参与选择）
+	Select(ctx context.Context, req *SelectRequest) (*data.Operator, error)
+}
+
+// SelectRequest 选择请求
+type SelectRequest struct {
+	BuilderAPIKey string   // 提交交易的 Builder（builder_sticky 策略使用）
+	Exclude       []string // 排除的 Operator 地址（例如余额不足后切换 Operator 时排除原 Operator）
+	Operator      string   // 指定 Operator 地址（Proxy Wallet 中继请求的签名绑定了发送方）
+}
+
+// BalanceReader 链上余额查询接口（*ethclient.Client 已实现）
+type BalanceReader interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// strategy 选择策略类型
+type strategy string
+
+const (
+	StrategyRoundRobin      strategy = "round_robin"      // 轮询
+	StrategyLeastInFlight   strategy = "least_in_flight"  // 排队中及未确认交易最少
+	StrategyBalanceWeighted strategy = "balance_weighted" // 按超出阈值的余额加权随机
+	StrategyBuilderSticky   strategy = "builder_sticky"   // 同一 Builder 固定到同一 Operator（Rendezvous 哈希）
+)
+
+// candidate 通过余额检查的候选 Operator
+type candidate struct {
+	operator *data.Operator
+	surplus  *big.Int // 余额超出 BalanceThreshold 的部分
+}
+
+// pickFunc 从候选 Operator 中选择一个
+type pickFunc func(ctx context.Context, req *SelectRequest, candidates []*candidate) (*data.Operator, error)
+
+// selector OperatorSelector 实现：公共的余额过滤 + 可替换的选择策略
+type selector struct {
+	operatorRepo data.OperatorRepo
+	balances     *balanceCache
+	pick         pickFunc
+	log          *log.Helper
+}
+
+// NewOperatorSelector 创建 Operator 选择器
+func NewOperatorSelector(
+	name string,
+	operatorRepo data.OperatorRepo,
+	txRepo data.TransactionRepo,
+	ethClient BalanceReader,
+	balanceTTL time.Duration,
+	logger log.Logger,
+) (OperatorSelector, error) {
+	s := &selector{
+		operatorRepo: operatorRepo,
+		balances:     newBalanceCache(ethClient, balanceTTL),
+		log:          log.NewHelper(log.With(logger, "module", "selector")),
+	}
+
+	switch strategy(name) {
+	case StrategyRoundRobin, "":
+		s.pick = newRoundRobin()
+	case StrategyLeastInFlight:
+		s.pick = newLeastInFlight(txRepo)
+	case StrategyBalanceWeighted:
+		s.pick = pickBalanceWeighted
+	case StrategyBuilderSticky:
+		s.pick = pickBuilderSticky
+	default:
+		return nil, fmt.Errorf("unknown operator selection strategy: %s", name)
+	}
+	return s, nil
+}
+
+// Select 选择 Operator
+func (s *selector) Select(ctx context.Context, req *SelectRequest) (*data.Operator, error) {
+	operators, err := s.operatorRepo.GetActiveOperators(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operators: %w", err)
+	}
+	if len(operators) == 0 {
+		return nil, fmt.Errorf("%w: no active operators", ErrNoOperatorAvailable)
+	}
+
+	// 按 ID 排序，保证轮询和哈希策略的顺序稳定
+	sort.Slice(operators, func(i, j int) bool { return operators[i].ID < operators[j].ID })
+
+	candidates := make([]*candidate, 0, len(operators))
+	for _, op := range operators {
+		if req != nil && isExcluded(op.Address, req.Exclude) {
+			continue
+		}
+		if req != nil && req.Operator != "" && !strings.EqualFold(op.Address, req.Operator) {
+			continue
+		}
+		balance, err := s.balances.get(ctx, op.Address)
+		if err != nil {
+			s.log.WithContext(ctx).Warnw("msg", "failed to get operator balance, skipping", "operator", op.Address, "error", err)
+			continue
+		}
+		threshold, ok := new(big.Int).SetString(op.BalanceThreshold, 10)
+		if !ok {
+			threshold = new(big.Int)
+		}
+		if balance.Cmp(threshold) < 0 {
+			s.log.WithContext(ctx).Warnw("msg", "operator balance below threshold, skipping",
+				"operator", op.Address, "balance", balance.String(), "threshold", threshold.String())
+			continue
+		}
+		candidates = append(candidates, &candidate{operator: op, surplus: new(big.Int).Sub(balance, threshold)})
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: no operator with sufficient balance", ErrNoOperatorAvailable)
+	}
+
+	return s.pick(ctx, req, candidates)
+}
+
+// isExcluded 地址是否在排除列表中（不区分大小写）
+func isExcluded(address string, exclude []string) bool {
+	for _, e := range exclude {
+		if strings.EqualFold(address, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// newRoundRobin 轮询策略
+func newRoundRobin() pickFunc {
+	var counter atomic.Uint64
+	return func(ctx context.Context, req *SelectRequest, candidates []*candidate) (*data.Operator, error) {
+		index := (counter.Add(1) - 1) % uint64(len(candidates))
+		return candidates[index].operator, nil
+	}
+}
+
+// newLeastInFlight 排队中及未确认交易最少策略（数量相同时轮询，避免总是命中第一个）
+func newLeastInFlight(txRepo data.TransactionRepo) pickFunc {
+	var counter atomic.Uint64
+	return func(ctx context.Context, req *SelectRequest, candidates []*candidate) (*data.Operator, error) {
+		counts, err := txRepo.CountPendingByOperator(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count pending transactions: %w", err)
+		}
+
+		offset := counter.Add(1) - 1
+		var best *data.Operator
+		var bestCount int64
+		for i := range candidates {
+			op := candidates[(uint64(i)+offset)%uint64(len(candidates))].operator
+			count := counts[strings.ToLower(op.Address)]
+			if best == nil || count < bestCount {
+				best, bestCount = op, count
+			}
+		}
+		return best, nil
+	}
+}
+
+// pickBalanceWeighted 按超出阈值的余额加权随机选择（余额均为阈值时退化为均匀随机）
+func pickBalanceWeighted(ctx context.Context, req *SelectRequest, candidates []*candidate) (*data.Operator, error) {
+	weights := make([]float64, len(candidates))
+	total := 0.0
+	for i, c := range candidates {
+		weights[i], _ = new(big.Float).SetInt(c.surplus).Float64()
+		total += weights[i]
+	}
+	if total <= 0 {
+		return candidates[rand.Intn(len(candidates))].operator, nil
+	}
+
+	r := rand.Float64() * total
+	for i, w := range weights {
+		if r < w {
+			return candidates[i].operator, nil
+		}
+		r -= w
+	}
+	return candidates[len(candidates)-1].operator, nil
+}
+
+// pickBuilderSticky 同一 Builder 固定到同一 Operator
+// 使用 Rendezvous 哈希：Operator 增减时只有映射到该 Operator 的 Builder 会迁移
+func pickBuilderSticky(ctx context.Context, req *SelectRequest, candidates []*candidate) (*data.Operator, error) {
+	builder := ""
+	if req != nil {
+		builder = req.BuilderAPIKey
+	}
+
+	var best *data.Operator
+	var bestScore uint64
+	for _, c := range candidates {
+		h := fnv.New64a()
+		h.Write([]byte(builder))
+		h.Write([]byte{0})
+		h.Write([]byte(strings.ToLower(c.operator.Address)))
+		if score := h.Sum64(); best == nil || score > bestScore {
+			best, bestScore = c.operator, score
+		}
+	}
+	return best, nil
+}
+
+// balanceCache Operator 余额短期缓存（避免每次提交都查询所有 Operator 余额）
+type balanceCache struct {
+	client BalanceReader
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*balanceEntry
+}
+
+// balanceEntry 缓存的余额
+type balanceEntry struct {
+	balance   *big.Int
+	fetchedAt time.Time
+}
+
+// newBalanceCache 创建余额缓存
+func newBalanceCache(client BalanceReader, ttl time.Duration) *balanceCache {
+	return &balanceCache{
+		client:  client,
+		ttl:     ttl,
+		entries: make(map[string]*balanceEntry),
+	}
+}
+
+// get 获取 Operator 余额（缓存过期时查询链上）
+func (c *balanceCache) get(ctx context.Context, address string) (*big.Int, error) {
+	key := strings.ToLower(address)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < c.ttl {
+		return entry.balance, nil
+	}
+
+	balance, err := c.client.BalanceAt(ctx, common.HexToAddress(address), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = &balanceEntry{balance: balance, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return balance, nil
+}
@@ -2,12 +2,20 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 
 	v1 "prediction-relayer-service/api/relayer/v1"
+	"prediction-relayer-service/internal/chain"
 	"prediction-relayer-service/internal/conf"
-	"prediction-relayer-service/internal/monitor"
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/keystore"
+	"prediction-relayer-service/internal/rotation"
 	"prediction-relayer-service/internal/service"
+	"prediction-relayer-service/internal/signer"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/middleware/recovery"
 	"github.com/go-kratos/kratos/v2/transport/grpc"
@@ -20,6 +28,12 @@ var ProviderSet = wire.NewSet(
 	NewHTTPServer,
 	NewGRPCServer,
 	NewMonitorRunner,
+	NewNonceReconciler,
+	NewNonceAuditorRunner,
+	NewKeyVerifier,
+	NewRelayVerifier,
+	NewKeyRotationRunner,
+	NewQueueRunner,
 )
 
 // NewHTTPServer 创建 HTTP 服务器
@@ -65,25 +79,257 @@ func NewGRPCServer(c *conf.Server, relayerService *service.RelayerService, logge
 }
 
 // NewMonitorRunner 创建监控器运行器
-func NewMonitorRunner(m monitor.Monitor, logger log.Logger) *MonitorRunner {
+func NewMonitorRunner(chains chain.Router, logger log.Logger) *MonitorRunner {
 	return &MonitorRunner{
-		monitor: m,
-		logger:  logger,
+		chains: chains,
+		logger: logger,
 	}
 }
 
-// MonitorRunner 监控器运行器
+// MonitorRunner 监控器运行器（每条链一个监控器）
 type MonitorRunner struct {
-	monitor monitor.Monitor
-	logger  log.Logger
+	chains chain.Router
+	logger log.Logger
 }
 
-// Start 启动监控器（在应用启动时运行）
+// Start 启动所有链的监控器（在应用启动时运行）
 func (r *MonitorRunner) Start(ctx context.Context) error {
+	for _, stack := range r.chains.All() {
+		go func() {
+			if err := stack.Monitor.Start(ctx); err != nil {
+				r.logger.Log(log.LevelError, "msg", "monitor stopped", "chain_id", stack.ChainID, "error", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// NewNonceReconciler 创建 Nonce 对账器
+func NewNonceReconciler(chains chain.Router, logger log.Logger) *NonceReconciler {
+	return &NonceReconciler{
+		chains: chains,
+		logger: logger,
+	}
+}
+
+// NonceReconciler 启动时将所有链上激活 Operator 的数据库 Nonce 与链上对账
+type NonceReconciler struct {
+	chains chain.Router
+	logger log.Logger
+}
+
+// Start 执行一次对账（在应用启动时运行）
+func (r *NonceReconciler) Start(ctx context.Context) error {
+	var errs []error
+	for _, stack := range r.chains.All() {
+		if err := stack.NonceManager.ReconcileAll(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("chain %d: %w", stack.ChainID, err))
+			continue
+		}
+		r.logger.Log(log.LevelInfo, "msg", "operator nonces reconciled with chain", "chain_id", stack.ChainID)
+	}
+	return errors.Join(errs...)
+}
+
+// NewNonceAuditorRunner 创建 Nonce 审计器运行器
+func NewNonceAuditorRunner(chains chain.Router, logger log.Logger) *NonceAuditorRunner {
+	return &NonceAuditorRunner{
+		chains: chains,
+		logger: logger,
+	}
+}
+
+// NonceAuditorRunner Nonce 审计器运行器（每条链一个审计器）
+type NonceAuditorRunner struct {
+	chains chain.Router
+	logger log.Logger
+}
+
+// Start 启动所有链的 Nonce 审计器（在应用启动时运行）
+func (r *NonceAuditorRunner) Start(ctx context.Context) error {
+	for _, stack := range r.chains.All() {
+		go func() {
+			if err := stack.Auditor.Start(ctx); err != nil {
+				r.logger.Log(log.LevelError, "msg", "nonce auditor stopped", "chain_id", stack.ChainID, "error", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// NewKeyVerifier 创建 Operator 私钥校验器
+func NewKeyVerifier(signers signer.Provider, operatorRepo data.OperatorRepo, logger log.Logger) *KeyVerifier {
+	return &KeyVerifier{
+		signers:      signers,
+		operatorRepo: operatorRepo,
+		logger:       logger,
+	}
+}
+
+// KeyVerifier 启动时校验所有激活 Operator 都可以签名
+// 本地签名器校验私钥可通过 KMS 解密且与地址匹配，远程签名器校验账户由签名服务管理
+// 校验失败的 Operator 置为 INACTIVE（不再被选择器选中，修复密钥后需手动重新激活）
+type KeyVerifier struct {
+	signers      signer.Provider
+	operatorRepo data.OperatorRepo
+	logger       log.Logger
+}
+
+// Start 执行一次校验（在应用启动时运行）
+// 无法完成校验（例如读取 Operator 或访问签名服务失败）时返回错误，由调用方终止启动
+func (r *KeyVerifier) Start(ctx context.Context) error {
+	err := r.signers.VerifyAll(ctx)
+	if err == nil {
+		r.logger.Log(log.LevelInfo, "msg", "operator keys verified")
+		return nil
+	}
+
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	var unverified []error
+	for _, e := range errs {
+		var keyErr *keystore.KeyError
+		if !errors.As(e, &keyErr) {
+			unverified = append(unverified, e)
+			continue
+		}
+		if err := r.operatorRepo.UpdateStatus(ctx, keyErr.Operator, "INACTIVE"); err != nil {
+			unverified = append(unverified, fmt.Errorf("failed to deactivate operator %s: %w", keyErr.Operator, err))
+			continue
+		}
+		r.logger.Log(log.LevelError, "msg", "operator key check failed, operator deactivated", "operator", keyErr.Operator, "error", keyErr.Err)
+	}
+	return errors.Join(unverified...)
+}
+
+// NewRelayVerifier 创建 RelayHub relay 注册校验器
+func NewRelayVerifier(chains chain.Router, operatorRepo data.OperatorRepo, logger log.Logger) *RelayVerifier {
+	return &RelayVerifier{
+		chains:       chains,
+		operatorRepo: operatorRepo,
+		logger:       logger,
+	}
+}
+
+// RelayVerifier 启动时校验配置了 Proxy Wallet 中继的链上，所有激活 Operator 都已在 RelayHub 注册为 relay
+// 未注册的 Operator 仍可执行普通交易（不置为 INACTIVE），但指定它为 relay 的中继请求会在模拟时被拒绝，记录错误日志提示注册
+type RelayVerifier struct {
+	chains       chain.Router
+	operatorRepo data.OperatorRepo
+	logger       log.Logger
+}
+
+// Start 执行一次校验（在应用启动时运行）
+// 无法完成校验（例如读取 Operator 或访问 RelayHub 失败）时返回错误，由调用方终止启动
+func (r *RelayVerifier) Start(ctx context.Context) error {
+	operators, err := r.operatorRepo.GetActiveOperators(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active operators: %w", err)
+	}
+
+	var errs []error
+	for _, stack := range r.chains.All() {
+		if stack.Proxy == nil {
+			continue
+		}
+		relayHub := common.HexToAddress(stack.RelayHub)
+		for _, op := range operators {
+			if op.ChainID != stack.ChainID {
+				continue
+			}
+			registered, err := stack.Proxy.IsRegisteredRelay(ctx, relayHub, common.HexToAddress(op.Address))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to check relay registration of operator %s on chain %d: %w", op.Address, stack.ChainID, err))
+				continue
+			}
+			if !registered {
+				r.logger.Log(log.LevelError, "msg", "operator is not registered as a relay at the relay hub, proxy wallet requests relayed by it will be rejected",
+					"chain_id", stack.ChainID, "operator", op.Address, "relay_hub", stack.RelayHub)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NewKeyRotationRunner 创建主密钥轮换运行器
+func NewKeyRotationRunner(r rotation.Rotator, c *conf.Security, logger log.Logger) *KeyRotationRunner {
+	return &KeyRotationRunner{
+		rotator: r,
+		enabled: c != nil && c.RotateKeysOnStartup,
+		logger:  logger,
+	}
+}
+
+// KeyRotationRunner 启动时在后台将密文重新加密到 KMS 激活密钥下
+type KeyRotationRunner struct {
+	rotator rotation.Rotator
+	enabled bool
+	logger  log.Logger
+}
+
+// Start 启动一次轮换（在应用启动时运行，未开启时跳过）
+func (r *KeyRotationRunner) Start(ctx context.Context) error {
+	if !r.enabled {
+		return nil
+	}
 	go func() {
-		if err := r.monitor.Start(ctx); err != nil {
-			r.logger.Log(log.LevelError, "msg", "monitor stopped", "error", err)
+		if _, err := r.rotator.Rotate(ctx); err != nil {
+			r.logger.Log(log.LevelError, "msg", "key rotation failed", "error", err)
 		}
 	}()
 	return nil
 }
+
+// NewQueueRunner 创建提交队列运行器
+func NewQueueRunner(chains chain.Router, logger log.Logger) *QueueRunner {
+	return &QueueRunner{
+		chains: chains,
+		logger: logger,
+	}
+}
+
+// QueueRunner 提交队列运行器（每条链一个队列）
+type QueueRunner struct {
+	chains chain.Router
+	logger log.Logger
+}
+
+// Start 启动所有链的提交队列（在应用启动时运行，恢复上次退出时未处理完的任务）
+func (r *QueueRunner) Start(ctx context.Context) error {
+	for _, stack := range r.chains.All() {
+		go func() {
+			if err := stack.Queue.Start(ctx); err != nil {
+				r.logger.Log(log.LevelError, "msg", "submission queue stopped", "chain_id", stack.ChainID, "error", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// Stop 停止所有链的提交队列，等待执行中的任务完成（在应用停止后运行）
+// ctx 到期时不再等待，未完成的任务在租约过期后由其他副本或下次启动恢复
+func (r *QueueRunner) Stop(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, stack := range r.chains.All() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stack.Queue.Stop()
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		r.logger.Log(log.LevelInfo, "msg", "submission queues drained")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to drain submission queues: %w", ctx.Err())
+	}
+}
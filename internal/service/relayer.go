@@ -88,6 +88,7 @@ func (s *RelayerService) SubmitTransaction(ctx context.Context, req *v1.SubmitTr
 
 	// 2. 构建业务请求
 	bizReq := &biz.SubmitTransactionRequest{
+		ChainID:         req.ChainId,
 		To:              req.To,
 		Data:            req.Data,
 		Signature:       req.Signature,
@@ -95,6 +96,8 @@ func (s *RelayerService) SubmitTransaction(ctx context.Context, req *v1.SubmitTr
 		GasLimit:        req.GasLimit,
 		TransactionType: req.TransactionType.String(),
 		Value:           req.Value,
+		Safe:            toSafeTransaction(req.SafeTx),
+		Proxy:           toProxyTransaction(req.ProxyTx),
 		AuthRequest:     authReq,
 	}
 
@@ -111,6 +114,73 @@ func (s *RelayerService) SubmitTransaction(ctx context.Context, req *v1.SubmitTr
 	}, nil
 }
 
+// toSafeTransaction 转换 Safe 交易
+func toSafeTransaction(tx *v1.SafeTransaction) *biz.SafeTransaction {
+	if tx == nil {
+		return nil
+	}
+	return &biz.SafeTransaction{
+		SafeAddress:    tx.SafeAddress,
+		To:             tx.To,
+		Value:          tx.Value,
+		Data:           tx.Data,
+		Operation:      tx.Operation,
+		SafeTxGas:      tx.SafeTxGas,
+		BaseGas:        tx.BaseGas,
+		GasPrice:       tx.GasPrice,
+		GasToken:       tx.GasToken,
+		RefundReceiver: tx.RefundReceiver,
+		Nonce:          tx.Nonce,
+		Signatures:     tx.Signatures,
+		Calls:          toBizSafeCalls(tx.Calls),
+	}
+}
+
+// toProxyTransaction 转换 Proxy Wallet 中继请求
+func toProxyTransaction(tx *v1.ProxyTransaction) *biz.ProxyTransaction {
+	if tx == nil {
+		return nil
+	}
+	calls := make([]*biz.ProxyCall, 0, len(tx.Calls))
+	for _, c := range tx.Calls {
+		calls = append(calls, &biz.ProxyCall{To: c.To, Value: c.Value, Data: c.Data})
+	}
+	return &biz.ProxyTransaction{
+		From:       tx.From,
+		Calls:      calls,
+		RelayerFee: tx.RelayerFee,
+		GasPrice:   tx.GasPrice,
+		GasLimit:   tx.GasLimit,
+		Nonce:      tx.Nonce,
+		Relay:      tx.Relay,
+		Signature:  tx.Signature,
+	}
+}
+
+// toBizSafeCalls 转换 Safe MultiSend 子调用（请求）
+func toBizSafeCalls(calls []*v1.SafeCall) []*biz.SafeCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	converted := make([]*biz.SafeCall, 0, len(calls))
+	for _, c := range calls {
+		converted = append(converted, &biz.SafeCall{To: c.To, Value: c.Value, Data: c.Data})
+	}
+	return converted
+}
+
+// toSafeCalls 转换 Safe MultiSend 子调用（响应）
+func toSafeCalls(calls []*biz.SafeCall) []*v1.SafeCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	converted := make([]*v1.SafeCall, 0, len(calls))
+	for _, c := range calls {
+		converted = append(converted, &v1.SafeCall{To: c.To, Value: c.Value, Data: c.Data})
+	}
+	return converted
+}
+
 // SubmitBatchTransaction 提交批量交易
 func (s *RelayerService) SubmitBatchTransaction(ctx context.Context, req *v1.SubmitBatchTransactionRequest) (*v1.SubmitBatchTransactionReply, error) {
 	// 1. 提取 Builder 认证信息（使用第一个交易的认证信息）
@@ -128,7 +198,9 @@ func (s *RelayerService) SubmitBatchTransaction(ctx context.Context, req *v1.Sub
 	bizTransactions := make([]*biz.SubmitTransactionRequest, 0, len(req.Transactions))
 	for _, tx := range req.Transactions {
 		bizTransactions = append(bizTransactions, &biz.SubmitTransactionRequest{
+			ChainID:         tx.ChainId,
 			To:              tx.To,
+			AllowFailure:    tx.AllowFailure,
 			Data:            tx.Data,
 			Signature:       tx.Signature,
 			Forwarder:       tx.Forwarder,
@@ -142,6 +214,7 @@ func (s *RelayerService) SubmitBatchTransaction(ctx context.Context, req *v1.Sub
 	bizReq := &biz.SubmitBatchTransactionRequest{
 		Transactions:  bizTransactions,
 		BuilderAPIKey: req.BuilderApiKey,
+		Atomic:        req.Atomic,
 	}
 
 	// 3. 调用业务服务
@@ -154,6 +227,8 @@ func (s *RelayerService) SubmitBatchTransaction(ctx context.Context, req *v1.Sub
 		TaskIds: reply.TaskIDs,
 		Success: reply.Success,
 		Message: reply.Message,
+		TaskId:  reply.TaskID,
+		Results: toCallResults(reply.Results),
 	}, nil
 }
 
@@ -180,18 +255,41 @@ func (s *RelayerService) GetTransactionStatus(ctx context.Context, req *v1.GetTr
 
 	return &v1.GetTransactionStatusReply{
 		Status: &v1.TransactionStatus{
-			TaskId:      status.TaskID,
-			TxHash:      status.TxHash,
-			Status:      status.Status,
-			GasPrice:    status.GasPrice,
-			BlockNumber: status.BlockNumber,
-			GasUsed:     status.GasUsed,
-			CreatedAt:   status.CreatedAt,
-			UpdatedAt:   status.UpdatedAt,
+			TaskId:               status.TaskID,
+			ChainId:              status.ChainID,
+			TxHash:               status.TxHash,
+			Status:               status.Status,
+			GasPrice:             status.GasPrice,
+			MaxFeePerGas:         status.MaxFeePerGas,
+			MaxPriorityFeePerGas: status.MaxPriorityFeePerGas,
+			BlockNumber:          status.BlockNumber,
+			GasUsed:              status.GasUsed,
+			ErrorMessage:         status.ErrorMessage,
+			SimulatedCallResults: toCallResults(status.SimulatedCallResults),
+			SafeCalls:            toSafeCalls(status.SafeCalls),
+			CreatedAt:            status.CreatedAt,
+			UpdatedAt:            status.UpdatedAt,
 		},
 	}, nil
 }
 
+// toCallResults 转换 Multicall3 子调用结果
+func toCallResults(results []*biz.CallResult) []*v1.CallResult {
+	if len(results) == 0 {
+		return nil
+	}
+	converted := make([]*v1.CallResult, 0, len(results))
+	for _, r := range results {
+		converted = append(converted, &v1.CallResult{
+			Index:        r.Index,
+			Success:      r.Success,
+			ReturnData:   r.ReturnData,
+			ErrorMessage: r.ErrorMessage,
+		})
+	}
+	return converted
+}
+
 // GetBuilderFeeStats 获取 Builder 费用统计
 func (s *RelayerService) GetBuilderFeeStats(ctx context.Context, req *v1.GetBuilderFeeStatsRequest) (*v1.GetBuilderFeeStatsReply, error) {
 	// 1. 提取 Builder 认证信息
@@ -0,0 +1,199 @@
+package signer
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/keystore"
+	"prediction-relayer-service/internal/kms"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// secp256k1N secp256k1 曲线阶，secp256k1HalfN 为其一半（用于 low-S 规范化）
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// kmsProvider KMS 托管密钥签名器提供者（私钥不离开 KMS）
+// Operator 的 kms_key_id 指向 ECC_SECG_P256K1 签名密钥
+type kmsProvider struct {
+	client       kms.AsymmetricSigner
+	operatorRepo data.OperatorRepo
+}
+
+// NewKMSProvider 创建 KMS 托管密钥签名器提供者
+func NewKMSProvider(client kms.AsymmetricSigner, operatorRepo data.OperatorRepo) Provider {
+	return &kmsProvider{
+		client:       client,
+		operatorRepo: operatorRepo,
+	}
+}
+
+// Signer 获取 Operator 的 KMS 签名器
+func (p *kmsProvider) Signer(ctx context.Context, operator *data.Operator) (Signer, error) {
+	if operator.KmsKeyID == "" {
+		return nil, fmt.Errorf("operator %s has no kms key id", operator.Address)
+	}
+	return &kmsSigner{
+		client:  p.client,
+		keyID:   operator.KmsKeyID,
+		address: common.HexToAddress(operator.Address),
+	}, nil
+}
+
+// VerifyAll 校验所有激活 Operator 的 KMS 公钥推导出的地址与 Operator 地址一致
+func (p *kmsProvider) VerifyAll(ctx context.Context) error {
+	operators, err := p.operatorRepo.GetActiveOperators(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get operators: %w", err)
+	}
+
+	var errs []error
+	for _, op := range operators {
+		if op.KmsKeyID == "" {
+			errs = append(errs, &keystore.KeyError{Operator: op.Address, Err: fmt.Errorf("no kms key id")})
+			continue
+		}
+		der, err := p.client.GetPublicKey(ctx, op.KmsKeyID)
+		if err != nil {
+			errs = append(errs, &keystore.KeyError{Operator: op.Address, Err: err})
+			continue
+		}
+		addr, err := AddressFromPublicKeyDER(der)
+		if err != nil {
+			errs = append(errs, &keystore.KeyError{Operator: op.Address, Err: err})
+			continue
+		}
+		if addr != common.HexToAddress(op.Address) {
+			errs = append(errs, &keystore.KeyError{Operator: op.Address, Err: fmt.Errorf("kms key address mismatch: %s", addr.Hex())})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// kmsSigner KMS 托管密钥签名器
+type kmsSigner struct {
+	client  kms.AsymmetricSigner
+	keyID   string
+	address common.Address
+}
+
+// Address 签名账户地址
+func (s *kmsSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx 通过 KMS Sign 签名交易
+func (s *kmsSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(chainID)
+	sig, err := s.signHash(ctx, signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx, err := tx.WithSignature(signer, sig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply signature: %w", err)
+	}
+	return signedTx, nil
+}
+
+// SignTypedData 通过 KMS Sign 对 EIP-712 结构化数据签名
+func (s *kmsSigner) SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+
+	sig, err := s.signHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+// signHash 对摘要签名，返回以太坊格式签名 [R || S || V]（V 为 0/1）
+func (s *kmsSigner) signHash(ctx context.Context, hash []byte) ([]byte, error) {
+	der, err := s.client.SignDigest(ctx, s.keyID, hash)
+	if err != nil {
+		return nil, err
+	}
+	return DERToEthereumSignature(der, hash, s.address)
+}
+
+// ecdsaSignature DER 编码的 ECDSA 签名（ECDSA-Sig-Value）
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// subjectPublicKeyInfo DER 编码的公钥（SubjectPublicKeyInfo）
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// DERToEthereumSignature 将 DER 编码的 ECDSA 签名转换为以太坊格式 [R || S || V]
+// S 按 EIP-2 规范化为 low-S，V 通过恢复公钥并与期望地址比对确定
+func DERToEthereumSignature(der []byte, hash []byte, expected common.Address) ([]byte, error) {
+	var parsed ecdsaSignature
+	rest, err := asn1.Unmarshal(der, &parsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode der signature: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("trailing data after der signature")
+	}
+	if parsed.R == nil || parsed.S == nil || parsed.R.Sign() <= 0 || parsed.S.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid der signature")
+	}
+	// R、S 须在 [1, N-1] 内（超出时 FillBytes 会 panic 或写入错误的值）
+	if parsed.R.Cmp(secp256k1N) >= 0 || parsed.S.Cmp(secp256k1N) >= 0 {
+		return nil, fmt.Errorf("der signature r or s out of range")
+	}
+
+	// low-S 规范化：s > N/2 时取 N - s
+	sVal := new(big.Int).Set(parsed.S)
+	if sVal.Cmp(secp256k1HalfN) > 0 {
+		sVal.Sub(secp256k1N, sVal)
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	parsed.R.FillBytes(sig[:32])
+	sVal.FillBytes(sig[32:64])
+
+	for v := byte(0); v < 2; v++ {
+		sig[crypto.RecoveryIDOffset] = v
+		pub, err := crypto.SigToPub(hash, sig)
+		if err != nil {
+			continue
+		}
+		if crypto.PubkeyToAddress(*pub) == expected {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("signature does not recover to %s", expected.Hex())
+}
+
+// AddressFromPublicKeyDER 从 DER 编码的 secp256k1 公钥推导以太坊地址
+func AddressFromPublicKeyDER(der []byte) (common.Address, error) {
+	var info subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return common.Address{}, fmt.Errorf("failed to decode public key: %w", err)
+	}
+	pub, err := crypto.UnmarshalPubkey(info.PublicKey.Bytes)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
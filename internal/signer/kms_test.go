@@ -0,0 +1,254 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/keystore"
+	"prediction-relayer-service/internal/kms"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// OID：ecPublicKey 和 secp256k1
+var (
+	oidECPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1   = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// fakeAWSKMS AWS KMS JSON 协议 stand-in（GetPublicKey / Sign，ECC_SECG_P256K1）
+type fakeAWSKMS struct {
+	t     *testing.T
+	keys  map[string]*ecdsa.PrivateKey
+	highS bool // 返回 high-S 签名（AWS KMS 不做 low-S 规范化）
+}
+
+func (f *fakeAWSKMS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDTEST/") || !strings.Contains(auth, "/us-east-1/kms/aws4_request") {
+		f.t.Errorf("unexpected Authorization header: %q", auth)
+	}
+	if r.Header.Get("Content-Type") != "application/x-amz-json-1.1" {
+		f.t.Errorf("unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+	}
+	target := r.Header.Get("X-Amz-Target")
+
+	var in struct {
+		KeyId            string
+		Message          []byte
+		MessageType      string
+		SigningAlgorithm string
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	key, ok := f.keys[in.KeyId]
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"__type": "NotFoundException", "message": "key not found"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	switch target {
+	case "TrentService.GetPublicKey":
+		der, err := asn1.Marshal(subjectPublicKeyInfo{
+			Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidECPublicKey, Parameters: asn1.RawValue{FullBytes: mustMarshal(f.t, oidSecp256k1)}},
+			PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(&key.PublicKey), BitLength: 65 * 8},
+		})
+		if err != nil {
+			f.t.Fatal(err)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"KeyId": in.KeyId, "KeySpec": "ECC_SECG_P256K1", "PublicKey": der})
+	case "TrentService.Sign":
+		if in.MessageType != "DIGEST" || in.SigningAlgorithm != "ECDSA_SHA_256" || len(in.Message) != 32 {
+			f.t.Errorf("unexpected sign request: %+v", in)
+		}
+		sig, err := crypto.Sign(in.Message, key)
+		if err != nil {
+			f.t.Fatal(err)
+		}
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+		if f.highS {
+			s.Sub(secp256k1N, s)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"KeyId": in.KeyId, "Signature": mustMarshal(f.t, ecdsaSignature{R: r, S: s})})
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"__type": "UnknownOperationException"})
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	b, err := asn1.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// newTestKMSProvider 启动 stand-in 并创建指向它的 KMS 签名器提供者（每个 Operator 对应一个 KMS 密钥）
+func newTestKMSProvider(t *testing.T, highS bool, operators ...*data.Operator) (Provider, map[string]*ecdsa.PrivateKey) {
+	t.Helper()
+	keys := make(map[string]*ecdsa.PrivateKey)
+	for _, op := range operators {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.KmsKeyID != "" {
+			keys[op.KmsKeyID] = key
+		}
+		if op.Address == "" {
+			op.Address = crypto.PubkeyToAddress(key.PublicKey).Hex()
+		}
+	}
+	srv := httptest.NewServer(&fakeAWSKMS{t: t, keys: keys, highS: highS})
+	t.Cleanup(srv.Close)
+
+	client, err := kms.NewAWSKMS(kms.AWSConfig{
+		Region:          "us-east-1",
+		Endpoint:        srv.URL,
+		AccessKeyID:     "AKIDTEST",
+		SecretAccessKey: "secret",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewKMSProvider(client, &fakeOperatorRepo{operators: operators}), keys
+}
+
+func TestKMSSignTx(t *testing.T) {
+	for _, highS := range []bool{false, true} {
+		op := &data.Operator{KmsKeyID: "alias/operator-1"}
+		provider, _ := newTestKMSProvider(t, highS, op)
+
+		s, err := provider.Signer(context.Background(), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 8; i++ { // 多次签名覆盖 V 为 0 和 1 的情况
+			tx := testTx()
+			signed, err := s.SignTx(context.Background(), tx, big.NewInt(137))
+			if err != nil {
+				t.Fatalf("highS=%v: SignTx: %v", highS, err)
+			}
+			sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(137)), signed)
+			if err != nil || sender != common.HexToAddress(op.Address) {
+				t.Fatalf("highS=%v: sender = %s, %v; want %s", highS, sender.Hex(), err, op.Address)
+			}
+			if _, _, sv := signed.RawSignatureValues(); sv.Cmp(secp256k1HalfN) > 0 {
+				t.Fatalf("highS=%v: signature S not normalised to low-S", highS)
+			}
+		}
+	}
+}
+
+func TestKMSSignTypedData(t *testing.T) {
+	op := &data.Operator{KmsKeyID: "alias/operator-1"}
+	provider, _ := newTestKMSProvider(t, true, op)
+	s, _ := provider.Signer(context.Background(), op)
+
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {{Name: "name", Type: "string"}},
+			"Order":        {{Name: "salt", Type: "uint256"}},
+		},
+		PrimaryType: "Order",
+		Domain:      apitypes.TypedDataDomain{Name: "Test"},
+		Message:     apitypes.TypedDataMessage{"salt": "7"},
+	}
+	sig, err := s.SignTypedData(context.Background(), typedData)
+	if err != nil {
+		t.Fatalf("SignTypedData: %v", err)
+	}
+	if v := sig[crypto.RecoveryIDOffset]; v != 27 && v != 28 {
+		t.Fatalf("V = %d, want 27/28", v)
+	}
+
+	hash, _, _ := apitypes.TypedDataAndHash(typedData)
+	recoverSig := append([]byte(nil), sig...)
+	recoverSig[crypto.RecoveryIDOffset] -= 27
+	pub, err := crypto.SigToPub(hash, recoverSig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != common.HexToAddress(op.Address) {
+		t.Fatalf("typed data signature does not recover to %s", op.Address)
+	}
+}
+
+func TestDERToEthereumSignature(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	hash := crypto.Keccak256([]byte("message"))
+	sig, _ := crypto.Sign(hash, key)
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+	highS := new(big.Int).Sub(secp256k1N, s)
+
+	// DER 中的 high-S 被规范化为与 crypto.Sign 一致的 [R || S || V]
+	got, err := DERToEthereumSignature(mustMarshal(t, ecdsaSignature{R: r, S: highS}), hash, address)
+	if err != nil {
+		t.Fatalf("DERToEthereumSignature: %v", err)
+	}
+	if string(got) != string(sig) {
+		t.Fatalf("signature = %x, want %x", got, sig)
+	}
+
+	other, _ := crypto.GenerateKey()
+	if _, err := DERToEthereumSignature(mustMarshal(t, ecdsaSignature{R: r, S: s}), hash, crypto.PubkeyToAddress(other.PublicKey)); err == nil {
+		t.Fatal("accepted a signature that recovers to another address")
+	}
+	if _, err := DERToEthereumSignature(append(mustMarshal(t, ecdsaSignature{R: r, S: s}), 0), hash, address); err == nil {
+		t.Fatal("accepted trailing data after the DER signature")
+	}
+
+	// R 或 S 不小于 N：拒绝（不 panic，也不把 N - S 的绝对值写入签名）
+	wide := new(big.Int).Lsh(big.NewInt(1), 264)
+	for _, bad := range []ecdsaSignature{
+		{R: wide, S: s},
+		{R: new(big.Int).Set(secp256k1N), S: s},
+		{R: r, S: new(big.Int).Set(secp256k1N)},
+		{R: r, S: new(big.Int).Add(secp256k1N, s)},
+	} {
+		if _, err := DERToEthereumSignature(mustMarshal(t, bad), hash, address); err == nil || !strings.Contains(err.Error(), "out of range") {
+			t.Fatalf("DERToEthereumSignature(r=%s, s=%s) err = %v; want out of range", bad.R, bad.S, err)
+		}
+	}
+}
+
+func TestKMSVerifyAll(t *testing.T) {
+	good := &data.Operator{KmsKeyID: "alias/good"}
+	mismatched := &data.Operator{KmsKeyID: "alias/mismatched", Address: "0x000000000000000000000000000000000000dEaD"}
+	noKey := &data.Operator{Address: "0x000000000000000000000000000000000000bEEF"}
+	provider, _ := newTestKMSProvider(t, false, good, mismatched, noKey)
+
+	err := provider.VerifyAll(context.Background())
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 2 {
+		t.Fatalf("VerifyAll = %v; want 2 operator errors", err)
+	}
+	failed := map[string]bool{}
+	for _, e := range joined.Unwrap() {
+		var keyErr *keystore.KeyError
+		if !errors.As(e, &keyErr) {
+			t.Fatalf("error %v is not a KeyError", e)
+		}
+		failed[keyErr.Operator] = true
+	}
+	if !failed[mismatched.Address] || !failed[noKey.Address] || failed[good.Address] {
+		t.Fatalf("failed operators = %v", failed)
+	}
+}
@@ -0,0 +1,88 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/keystore"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// localProvider 本地私钥签名器提供者
+type localProvider struct {
+	keyStore keystore.KeyStore
+}
+
+// NewLocalProvider 创建本地私钥签名器提供者（私钥通过 KeyStore 解密）
+func NewLocalProvider(ks keystore.KeyStore) Provider {
+	return &localProvider{keyStore: ks}
+}
+
+// Signer 获取 Operator 的本地签名器
+func (p *localProvider) Signer(ctx context.Context, operator *data.Operator) (Signer, error) {
+	return &localSigner{
+		keyStore: p.keyStore,
+		operator: operator,
+		address:  common.HexToAddress(operator.Address),
+	}, nil
+}
+
+// VerifyAll 校验所有激活 Operator 的私钥可以解密且与地址匹配
+func (p *localProvider) VerifyAll(ctx context.Context) error {
+	return p.keyStore.VerifyAll(ctx)
+}
+
+// localSigner 本地私钥签名器
+// 每次签名时从 KeyStore 获取私钥副本，签名后立即清零
+type localSigner struct {
+	keyStore keystore.KeyStore
+	operator *data.Operator
+	address  common.Address
+}
+
+// Address 签名账户地址
+func (s *localSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx 签名交易
+func (s *localSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	key, err := s.keyStore.PrivateKey(ctx, s.operator)
+	if err != nil {
+		return nil, err
+	}
+	defer keystore.Zero(key)
+
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signedTx, nil
+}
+
+// SignTypedData 对 EIP-712 结构化数据签名
+func (s *localSigner) SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+
+	key, err := s.keyStore.PrivateKey(ctx, s.operator)
+	if err != nil {
+		return nil, err
+	}
+	defer keystore.Zero(key)
+
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %w", err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
@@ -0,0 +1,221 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/keystore"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// RemoteConfig 外部签名服务配置
+type RemoteConfig struct {
+	URL        string            // JSON-RPC 地址（Clef / Web3Signer）
+	Headers    map[string]string // 附加 HTTP 头（例如鉴权 Token）
+	Timeout    time.Duration     // 单次请求超时
+	HTTPClient *http.Client      // 可选，自定义 HTTP 客户端（为空时按 Timeout 创建）
+}
+
+// remoteProvider 外部签名服务签名器提供者
+// 私钥保存在外部签名服务中，relayer 进程只持有地址
+type remoteProvider struct {
+	client       *rpc.Client
+	operatorRepo data.OperatorRepo
+}
+
+// NewRemoteProvider 创建外部签名服务签名器提供者
+func NewRemoteProvider(ctx context.Context, cfg RemoteConfig, operatorRepo data.OperatorRepo) (Provider, func(), error) {
+	if cfg.URL == "" {
+		return nil, nil, fmt.Errorf("remote signer url is required")
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: cfg.Timeout}
+	}
+	opts := []rpc.ClientOption{rpc.WithHTTPClient(httpClient)}
+	for k, v := range cfg.Headers {
+		opts = append(opts, rpc.WithHeader(k, v))
+	}
+
+	client, err := rpc.DialOptions(ctx, cfg.URL, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to remote signer: %w", err)
+	}
+
+	return &remoteProvider{
+		client:       client,
+		operatorRepo: operatorRepo,
+	}, client.Close, nil
+}
+
+// Signer 获取 Operator 的远程签名器
+func (p *remoteProvider) Signer(ctx context.Context, operator *data.Operator) (Signer, error) {
+	return &remoteSigner{
+		client:  p.client,
+		address: common.HexToAddress(operator.Address),
+	}, nil
+}
+
+// VerifyAll 校验所有激活 Operator 都由外部签名服务管理（eth_accounts）
+func (p *remoteProvider) VerifyAll(ctx context.Context) error {
+	var accounts []common.Address
+	if err := p.client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+		return fmt.Errorf("failed to list remote signer accounts: %w", err)
+	}
+	managed := make(map[common.Address]bool, len(accounts))
+	for _, a := range accounts {
+		managed[a] = true
+	}
+
+	operators, err := p.operatorRepo.GetActiveOperators(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get operators: %w", err)
+	}
+
+	var errs []error
+	for _, op := range operators {
+		if !managed[common.HexToAddress(op.Address)] {
+			errs = append(errs, &keystore.KeyError{Operator: op.Address, Err: fmt.Errorf("account not managed by remote signer")})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// remoteSigner 外部签名服务签名器
+type remoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// signTxArgs eth_signTransaction 请求参数
+type signTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// signTxResult eth_signTransaction 返回值（Clef 返回 {raw, tx}，Web3Signer 直接返回 raw hex）
+type signTxResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
+
+// Address 签名账户地址
+func (s *remoteSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx 通过 eth_signTransaction 签名交易，并校验签名者与交易内容未被篡改
+func (s *remoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if tx.Type() != types.DynamicFeeTxType {
+		return nil, fmt.Errorf("remote signer only supports dynamic fee transactions, got type %d", tx.Type())
+	}
+
+	args := signTxArgs{
+		From:                 s.address,
+		To:                   tx.To(),
+		Gas:                  hexutil.Uint64(tx.Gas()),
+		MaxFeePerGas:         (*hexutil.Big)(tx.GasFeeCap()),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.GasTipCap()),
+		Value:                (*hexutil.Big)(tx.Value()),
+		Nonce:                hexutil.Uint64(tx.Nonce()),
+		Data:                 tx.Data(),
+		ChainID:              (*hexutil.Big)(chainID),
+	}
+
+	var result json.RawMessage
+	if err := s.client.CallContext(ctx, &result, "eth_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	raw, err := decodeSignTxResult(result)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode signed transaction: %w", err)
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+	if signer.Hash(signedTx) != signer.Hash(tx) {
+		return nil, fmt.Errorf("remote signer returned a different transaction")
+	}
+	sender, err := types.Sender(signer, signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover sender: %w", err)
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("remote signer signed with %s, expected %s", sender.Hex(), s.address.Hex())
+	}
+
+	return signedTx, nil
+}
+
+// SignTypedData 通过 eth_signTypedData 对 EIP-712 结构化数据签名，并校验签名者
+func (s *remoteSigner) SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+
+	var sig hexutil.Bytes
+	if err := s.client.CallContext(ctx, &sig, "eth_signTypedData", s.address, typedData); err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length: %d", len(sig))
+	}
+	if sig[crypto.RecoveryIDOffset] < 27 {
+		sig[crypto.RecoveryIDOffset] += 27
+	}
+
+	// 恢复签名者地址（恢复时 V 需为 0/1）
+	recoverSig := make([]byte, len(sig))
+	copy(recoverSig, sig)
+	recoverSig[crypto.RecoveryIDOffset] -= 27
+	pub, err := crypto.SigToPub(hash, recoverSig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover signer: %w", err)
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != s.address {
+		return nil, fmt.Errorf("remote signer signed with %s, expected %s", signer.Hex(), s.address.Hex())
+	}
+
+	return sig, nil
+}
+
+// decodeSignTxResult 解析 eth_signTransaction 返回的已签名交易
+func decodeSignTxResult(result json.RawMessage) ([]byte, error) {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err == nil {
+		return raw, nil
+	}
+
+	var obj signTxResult
+	if err := json.Unmarshal(result, &obj); err != nil {
+		return nil, fmt.Errorf("failed to decode sign transaction result: %w", err)
+	}
+	if len(obj.Raw) == 0 {
+		return nil, fmt.Errorf("remote signer returned empty transaction")
+	}
+	return obj.Raw, nil
+}
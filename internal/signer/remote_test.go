@@ -0,0 +1,260 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"prediction-relayer-service/internal/data"
+	"prediction-relayer-service/internal/keystore"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// fakeOperatorRepo 内存 Operator 仓库（只实现签名器使用的方法）
+type fakeOperatorRepo struct {
+	data.OperatorRepo
+	operators []*data.Operator
+}
+
+func (r *fakeOperatorRepo) GetActiveOperators(ctx context.Context) ([]*data.Operator, error) {
+	return r.operators, nil
+}
+
+// fakeRemoteSigner 外部签名服务 stand-in（Clef / Web3Signer JSON-RPC 子集）
+type fakeRemoteSigner struct {
+	key      *ecdsa.PrivateKey
+	clefRaw  bool                              // eth_signTransaction 按 Clef 格式返回 {raw, tx}
+	tamper   func(tx *types.DynamicFeeTx)      // 签名前篡改交易
+	header   http.Header                       // 最近一次请求的 HTTP 头
+	typedSig func(hash []byte) ([]byte, error) // 自定义 eth_signTypedData 签名
+}
+
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func (f *fakeRemoteSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.header = r.Header.Clone()
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := f.handle(req)
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if err != nil {
+		resp["error"] = map[string]interface{}{"code": -32000, "message": err.Error()}
+	} else {
+		resp["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (f *fakeRemoteSigner) handle(req rpcRequest) (interface{}, error) {
+	switch req.Method {
+	case "eth_accounts":
+		return []common.Address{crypto.PubkeyToAddress(f.key.PublicKey)}, nil
+	case "eth_signTransaction":
+		var args signTxArgs
+		if err := json.Unmarshal(req.Params[0], &args); err != nil {
+			return nil, err
+		}
+		txData := &types.DynamicFeeTx{
+			ChainID:   (*big.Int)(args.ChainID),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: (*big.Int)(args.MaxPriorityFeePerGas),
+			GasFeeCap: (*big.Int)(args.MaxFeePerGas),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     (*big.Int)(args.Value),
+			Data:      args.Data,
+		}
+		if f.tamper != nil {
+			f.tamper(txData)
+		}
+		signed, err := types.SignNewTx(f.key, types.LatestSignerForChainID(txData.ChainID), txData)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := signed.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if f.clefRaw {
+			return map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": signed}, nil
+		}
+		return hexutil.Bytes(raw), nil
+	case "eth_signTypedData":
+		var typedData apitypes.TypedData
+		if err := json.Unmarshal(req.Params[1], &typedData); err != nil {
+			return nil, err
+		}
+		hash, _, err := apitypes.TypedDataAndHash(typedData)
+		if err != nil {
+			return nil, err
+		}
+		if f.typedSig != nil {
+			sig, err := f.typedSig(hash)
+			return hexutil.Bytes(sig), err
+		}
+		sig, err := crypto.Sign(hash, f.key) // V 为 0/1，由签名器规范化为 27/28
+		return hexutil.Bytes(sig), err
+	default:
+		return nil, errors.New("method not found")
+	}
+}
+
+// newTestRemoteProvider 启动 stand-in 并创建指向它的远程签名器提供者
+func newTestRemoteProvider(t *testing.T, fake *fakeRemoteSigner, operators ...*data.Operator) Provider {
+	t.Helper()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	provider, cleanup, err := NewRemoteProvider(context.Background(), RemoteConfig{
+		URL:     srv.URL,
+		Headers: map[string]string{"Authorization": "Bearer test-token"},
+	}, &fakeOperatorRepo{operators: operators})
+	if err != nil {
+		t.Fatalf("NewRemoteProvider: %v", err)
+	}
+	t.Cleanup(cleanup)
+	return provider
+}
+
+func newTestKey(t *testing.T) (*ecdsa.PrivateKey, *data.Operator) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, &data.Operator{Address: crypto.PubkeyToAddress(key.PublicKey).Hex()}
+}
+
+func testTx() *types.Transaction {
+	to := common.HexToAddress("0x4bFb41d5B3570DeFd03C39a9A4D8dE6Bd8B8982E")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(137),
+		Nonce:     42,
+		GasTipCap: big.NewInt(30_000_000_000),
+		GasFeeCap: big.NewInt(100_000_000_000),
+		Gas:       150_000,
+		To:        &to,
+		Value:     new(big.Int),
+		Data:      common.FromHex("0xa9059cbb"),
+	})
+}
+
+func TestRemoteSignTx(t *testing.T) {
+	for _, clefRaw := range []bool{false, true} {
+		key, op := newTestKey(t)
+		fake := &fakeRemoteSigner{key: key, clefRaw: clefRaw}
+		provider := newTestRemoteProvider(t, fake, op)
+
+		s, err := provider.Signer(context.Background(), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := testTx()
+		signed, err := s.SignTx(context.Background(), tx, big.NewInt(137))
+		if err != nil {
+			t.Fatalf("clefRaw=%v: SignTx: %v", clefRaw, err)
+		}
+		sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(137)), signed)
+		if err != nil || sender != common.HexToAddress(op.Address) {
+			t.Fatalf("clefRaw=%v: sender = %s, %v; want %s", clefRaw, sender.Hex(), err, op.Address)
+		}
+		if signed.Nonce() != tx.Nonce() || signed.Gas() != tx.Gas() {
+			t.Fatalf("clefRaw=%v: signed transaction differs from request", clefRaw)
+		}
+		if got := fake.header.Get("Authorization"); got != "Bearer test-token" {
+			t.Fatalf("Authorization header = %q", got)
+		}
+	}
+}
+
+func TestRemoteSignTxRejectsTampering(t *testing.T) {
+	key, op := newTestKey(t)
+	fake := &fakeRemoteSigner{key: key, tamper: func(tx *types.DynamicFeeTx) { tx.Nonce++ }}
+	provider := newTestRemoteProvider(t, fake, op)
+
+	s, _ := provider.Signer(context.Background(), op)
+	if _, err := s.SignTx(context.Background(), testTx(), big.NewInt(137)); err == nil || !strings.Contains(err.Error(), "different transaction") {
+		t.Fatalf("SignTx with tampered transaction: err = %v", err)
+	}
+}
+
+func TestRemoteSignTxRejectsWrongSigner(t *testing.T) {
+	key, _ := newTestKey(t)
+	_, op := newTestKey(t)
+	provider := newTestRemoteProvider(t, &fakeRemoteSigner{key: key}, op)
+
+	s, _ := provider.Signer(context.Background(), op)
+	if _, err := s.SignTx(context.Background(), testTx(), big.NewInt(137)); err == nil || !strings.Contains(err.Error(), "signed with") {
+		t.Fatalf("SignTx with wrong signer: err = %v", err)
+	}
+}
+
+func TestRemoteSignTypedData(t *testing.T) {
+	key, op := newTestKey(t)
+	provider := newTestRemoteProvider(t, &fakeRemoteSigner{key: key}, op)
+	s, _ := provider.Signer(context.Background(), op)
+
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {{Name: "name", Type: "string"}, {Name: "chainId", Type: "uint256"}},
+			"Order":        {{Name: "salt", Type: "uint256"}},
+		},
+		PrimaryType: "Order",
+		Domain:      apitypes.TypedDataDomain{Name: "Test", ChainId: (*math.HexOrDecimal256)(big.NewInt(137))},
+		Message:     apitypes.TypedDataMessage{"salt": "1"},
+	}
+	sig, err := s.SignTypedData(context.Background(), typedData)
+	if err != nil {
+		t.Fatalf("SignTypedData: %v", err)
+	}
+	if v := sig[crypto.RecoveryIDOffset]; v != 27 && v != 28 {
+		t.Fatalf("V = %d, want 27/28", v)
+	}
+
+	// 签名服务用其他账户签名时拒绝
+	other, _ := crypto.GenerateKey()
+	provider = newTestRemoteProvider(t, &fakeRemoteSigner{key: key, typedSig: func(hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, other)
+	}}, op)
+	s, _ = provider.Signer(context.Background(), op)
+	if _, err := s.SignTypedData(context.Background(), typedData); err == nil {
+		t.Fatal("SignTypedData accepted a signature from another account")
+	}
+}
+
+func TestRemoteVerifyAll(t *testing.T) {
+	key, managed := newTestKey(t)
+	_, unmanaged := newTestKey(t)
+	provider := newTestRemoteProvider(t, &fakeRemoteSigner{key: key}, managed, unmanaged)
+
+	err := provider.VerifyAll(context.Background())
+	var keyErr *keystore.KeyError
+	if !errors.As(err, &keyErr) || keyErr.Operator != unmanaged.Address {
+		t.Fatalf("VerifyAll = %v; want KeyError for %s", err, unmanaged.Address)
+	}
+
+	provider = newTestRemoteProvider(t, &fakeRemoteSigner{key: key}, managed)
+	if err := provider.VerifyAll(context.Background()); err != nil {
+		t.Fatalf("VerifyAll with managed operators: %v", err)
+	}
+}
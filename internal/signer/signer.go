@@ -0,0 +1,42 @@
+package signer
+
+import (
+	"context"
+	"math/big"
+
+	"prediction-relayer-service/internal/data"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer 交易签名器接口
+type Signer interface {
+	// Address 签名账户地址
+	Address() common.Address
+
+	// SignTx 签名交易（返回已签名交易，不广播）
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+
+	// SignTypedData 对 EIP-712 结构化数据签名，返回 65 字节签名 [R || S || V]（V 为 27/28）
+	SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error)
+}
+
+// Provider 根据 Operator 提供签名器
+type Provider interface {
+	// Signer 获取 Operator 的签名器
+	Signer(ctx context.Context, operator *data.Operator) (Signer, error)
+
+	// VerifyAll 校验所有激活 Operator 都可以签名（启动时调用）
+	VerifyAll(ctx context.Context) error
+}
+
+// signerType 签名器类型
+type signerType string
+
+const (
+	SignerTypeLocal  signerType = "local"   // 本地私钥签名（私钥经 KMS 解密）
+	SignerTypeRemote signerType = "remote"  // 外部签名服务（Clef / Web3Signer JSON-RPC）
+	SignerTypeKMS    signerType = "aws-kms" // KMS 托管密钥签名（AWS KMS ECC_SECG_P256K1）
+)
@@ -48,138 +48,8 @@ func (d *deployer) DeploySafeWallet(ctx context.Context, owners []common.Address
 }
 
 // DeployProxyWallet 部署 Proxy Wallet（自动部署）
-// Proxy Wallet 通常在首次交易时自动部署
+// Proxy Wallet 不单独部署：ProxyWalletFactory.proxy 在 owner 的钱包不存在时先部署再执行调用，
+// 首次中继 Proxy 交易（SubmitTransactionRequest.proxy_tx）即完成部署
 func (d *deployer) DeployProxyWallet(ctx context.Context, owner common.Address) (common.Address, *types.Transaction, error) {
-	// TODO: 实现 Proxy Wallet 部署
-	// 1. 调用 Proxy Factory 合约的 createProxy 方法
-	// 2. 返回部署的钱包地址和交易
-	// 3. 这里需要集成 Proxy Factory 合约
-
-	// 临时实现：返回错误，提示需要实现
-	return common.Address{}, nil, fmt.Errorf("Proxy Wallet deployment not implemented yet")
-}
-
-
-import (
-	"context"
-	"fmt"
-	"math/big"
-
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
-)
-
-// Deployer 钱包部署器接口
-type Deployer interface {
-	// DeploySafeWallet 部署 Gnosis Safe Wallet
-	DeploySafeWallet(ctx context.Context, owners []common.Address, threshold uint64) (common.Address, *types.Transaction, error)
-
-	// DeployProxyWallet 部署 Proxy Wallet（自动部署）
-	DeployProxyWallet(ctx context.Context, owner common.Address) (common.Address, *types.Transaction, error)
-}
-
-// deployer 钱包部署器实现
-type deployer struct {
-	ethClient *ethclient.Client
-	chainID   *big.Int
-	// TODO: 添加 Gnosis Safe Factory 合约地址和 ABI
-	// safeFactory *bind.BoundContract
-}
-
-// NewDeployer 创建钱包部署器
-func NewDeployer(ethClient *ethclient.Client, chainID *big.Int) Deployer {
-	return &deployer{
-		ethClient: ethClient,
-		chainID:   chainID,
-	}
-}
-
-// DeploySafeWallet 部署 Gnosis Safe Wallet
-// 参考：https://docs.gnosis-safe.io/contracts/safe-contracts
-func (d *deployer) DeploySafeWallet(ctx context.Context, owners []common.Address, threshold uint64) (common.Address, *types.Transaction, error) {
-	// TODO: 实现 Gnosis Safe Wallet 部署
-	// 1. 调用 Gnosis Safe Factory 合约的 createProxyWithNonce 方法
-	// 2. 返回部署的钱包地址和交易
-	// 3. 这里需要集成 Gnosis Safe Factory 合约
-
-	// 临时实现：返回错误，提示需要实现
-	return common.Address{}, nil, fmt.Errorf("Gnosis Safe Wallet deployment not implemented yet")
-}
-
-// DeployProxyWallet 部署 Proxy Wallet（自动部署）
-// Proxy Wallet 通常在首次交易时自动部署
-func (d *deployer) DeployProxyWallet(ctx context.Context, owner common.Address) (common.Address, *types.Transaction, error) {
-	// TODO: 实现 Proxy Wallet 部署
-	// 1. 调用 Proxy Factory 合约的 createProxy 方法
-	// 2. 返回部署的钱包地址和交易
-	// 3. 这里需要集成 Proxy Factory 合约
-
-	// 临时实现：返回错误，提示需要实现
-	return common.Address{}, nil, fmt.Errorf("Proxy Wallet deployment not implemented yet")
-}
-
-
-import (
-	"context"
-	"fmt"
-	"math/big"
-
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
-)
-
-// Deployer 钱包部署器接口
-type Deployer interface {
-	// DeploySafeWallet 部署 Gnosis Safe Wallet
-	DeploySafeWallet(ctx context.Context, owners []common.Address, threshold uint64) (common.Address, *types.Transaction, error)
-
-	// DeployProxyWallet 部署 Proxy Wallet（自动部署）
-	DeployProxyWallet(ctx context.Context, owner common.Address) (common.Address, *types.Transaction, error)
+	return common.Address{}, nil, fmt.Errorf("proxy wallets are deployed by the proxy wallet factory on the first relayed proxy transaction")
 }
-
-// deployer 钱包部署器实现
-type deployer struct {
-	ethClient *ethclient.Client
-	chainID   *big.Int
-	// TODO: 添加 Gnosis Safe Factory 合约地址和 ABI
-	// safeFactory *bind.BoundContract
-}
-
-// NewDeployer 创建钱包部署器
-func NewDeployer(ethClient *ethclient.Client, chainID *big.Int) Deployer {
-	return &deployer{
-		ethClient: ethClient,
-		chainID:   chainID,
-	}
-}
-
-// DeploySafeWallet 部署 Gnosis Safe Wallet
-// 参考：https://docs.gnosis-safe.io/contracts/safe-contracts
-func (d *deployer) DeploySafeWallet(ctx context.Context, owners []common.Address, threshold uint64) (common.Address, *types.Transaction, error) {
-	// TODO: 实现 Gnosis Safe Wallet 部署
-	// 1. 调用 Gnosis Safe Factory 合约的 createProxyWithNonce 方法
-	// 2. 返回部署的钱包地址和交易
-	// 3. 这里需要集成 Gnosis Safe Factory 合约
-
-	// 临时实现：返回错误，提示需要实现
-	return common.Address{}, nil, fmt.Errorf("Gnosis Safe Wallet deployment not implemented yet")
-}
-
-// DeployProxyWallet 部署 Proxy Wallet（自动部署）
-// Proxy Wallet 通常在首次交易时自动部署
-func (d *deployer) DeployProxyWallet(ctx context.Context, owner common.Address) (common.Address, *types.Transaction, error) {
-	// TODO: 实现 Proxy Wallet 部署
-	// 1. 调用 Proxy Factory 合约的 createProxy 方法
-	// 2. 返回部署的钱包地址和交易
-	// 3. 这里需要集成 Proxy Factory 合约
-
-	// 临时实现：返回错误，提示需要实现
-	return common.Address{}, nil, fmt.Errorf("Proxy Wallet deployment not implemented yet")
-}
-
-
-
-
-
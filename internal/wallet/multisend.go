@@ -0,0 +1,136 @@
+package wallet
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultMultiSendCallOnlyAddress MultiSendCallOnly（Safe v1.3.0）标准部署地址
+const DefaultMultiSendCallOnlyAddress = "0x40A2aCCbd92BCA938b02010E17A5b8929b49130D"
+
+// multiSendABI MultiSendCallOnly.multiSend 的 ABI
+const multiSendABI = `[{"name": "multiSend", "type": "function", "stateMutability": "payable", "inputs": [{"name": "transactions", "type": "bytes"}], "outputs": []}]`
+
+// parsedMultiSendABI 解析后的 MultiSend ABI
+var parsedMultiSendABI = mustParseABI(multiSendABI)
+
+// multiSendHeaderLength 每个子调用的固定头部长度：operation 1 + to 20 + value 32 + dataLength 32
+const multiSendHeaderLength = 1 + 20 + 32 + 32
+
+// MultiSendCall MultiSend 子调用（MultiSendCallOnly 只允许 CALL）
+type MultiSendCall struct {
+	To    common.Address
+	Value *big.Int
+	Data  []byte
+}
+
+// PackMultiSend 按 MultiSend 紧凑格式编码子调用并返回 multiSend(bytes) 调用数据
+// 每个子调用编码为 operation(uint8) | to(address) | value(uint256) | dataLength(uint256) | data
+func PackMultiSend(calls []MultiSendCall) ([]byte, error) {
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("no calls to multisend")
+	}
+
+	var packed bytes.Buffer
+	for _, call := range calls {
+		value := call.Value
+		if value == nil {
+			value = new(big.Int)
+		}
+		packed.WriteByte(OperationCall)
+		packed.Write(call.To.Bytes())
+		packed.Write(common.LeftPadBytes(value.Bytes(), 32))
+		packed.Write(common.LeftPadBytes(new(big.Int).SetInt64(int64(len(call.Data))).Bytes(), 32))
+		packed.Write(call.Data)
+	}
+
+	data, err := parsedMultiSendABI.Pack("multiSend", packed.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack multiSend: %w", err)
+	}
+	return data, nil
+}
+
+// UnpackMultiSend 解码 multiSend(bytes) 调用数据中的子调用
+func UnpackMultiSend(data []byte) ([]MultiSendCall, error) {
+	method, ok := parsedMultiSendABI.Methods["multiSend"]
+	if !ok || len(data) < 4 || !bytes.Equal(data[:4], method.ID) {
+		return nil, fmt.Errorf("not a multiSend call")
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack multiSend: %w", err)
+	}
+	packed, _ := args[0].([]byte)
+
+	var calls []MultiSendCall
+	for len(packed) > 0 {
+		if len(packed) < multiSendHeaderLength {
+			return nil, fmt.Errorf("truncated multisend transaction")
+		}
+		if packed[0] != OperationCall {
+			return nil, fmt.Errorf("unsupported multisend operation: %d", packed[0])
+		}
+		length := new(big.Int).SetBytes(packed[53:85])
+		if !length.IsUint64() || length.Uint64() > uint64(len(packed)-multiSendHeaderLength) {
+			return nil, fmt.Errorf("truncated multisend transaction")
+		}
+		end := multiSendHeaderLength + int(length.Uint64())
+		calls = append(calls, MultiSendCall{
+			To:    common.BytesToAddress(packed[1:21]),
+			Value: new(big.Int).SetBytes(packed[21:53]),
+			Data:  common.CopyBytes(packed[multiSendHeaderLength:end]),
+		})
+		packed = packed[end:]
+	}
+	return calls, nil
+}
+
+// UnpackExecTransaction 解码 execTransaction 调用数据中的 SafeTx 和签名（Nonce 不在调用参数中，返回的 SafeTx 不含 Nonce）
+func UnpackExecTransaction(data []byte) (*SafeTx, []byte, error) {
+	method := parsedSafeABI.Methods["execTransaction"]
+	if len(data) < 4 || !bytes.Equal(data[:4], method.ID) {
+		return nil, nil, fmt.Errorf("not an execTransaction call")
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to unpack execTransaction: %w", err)
+	}
+
+	tx := &SafeTx{}
+	var signatures []byte
+	var ok [10]bool
+	tx.To, ok[0] = args[0].(common.Address)
+	tx.Value, ok[1] = args[1].(*big.Int)
+	tx.Data, ok[2] = args[2].([]byte)
+	tx.Operation, ok[3] = args[3].(uint8)
+	tx.SafeTxGas, ok[4] = args[4].(*big.Int)
+	tx.BaseGas, ok[5] = args[5].(*big.Int)
+	tx.GasPrice, ok[6] = args[6].(*big.Int)
+	tx.GasToken, ok[7] = args[7].(common.Address)
+	tx.RefundReceiver, ok[8] = args[8].(common.Address)
+	signatures, ok[9] = args[9].([]byte)
+	for i := range ok {
+		if !ok[i] {
+			return nil, nil, fmt.Errorf("unexpected execTransaction argument %d", i)
+		}
+	}
+	return tx, signatures, nil
+}
+
+// DecodeSafeMultiSend 从 execTransaction 调用数据中解码 MultiSend 子调用（不是 Safe MultiSend 交易时返回 false）
+// 只解码 DELEGATECALL 到 multiSend（配置的 MultiSendCallOnly 合约）的交易：其他合约的调用数据即使以 multiSend 选择器开头，执行语义也未知
+func DecodeSafeMultiSend(execData []byte, multiSend common.Address) ([]MultiSendCall, bool) {
+	tx, _, err := UnpackExecTransaction(execData)
+	if err != nil || tx.Operation != OperationDelegateCall || tx.To != multiSend {
+		return nil, false
+	}
+	calls, err := UnpackMultiSend(tx.Data)
+	if err != nil {
+		return nil, false
+	}
+	return calls, true
+}
@@ -0,0 +1,52 @@
+package wallet
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDecodeSafeMultiSend(t *testing.T) {
+	multiSend := common.HexToAddress(DefaultMultiSendCallOnlyAddress)
+	calls := []MultiSendCall{
+		{To: common.HexToAddress("0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174"), Value: big.NewInt(0), Data: common.FromHex("0x095ea7b3")},
+		{To: common.HexToAddress("0x4bFb41d5B3570DeFd03C39a9A4D8dE6Bd8B8982E"), Value: big.NewInt(5), Data: nil},
+	}
+	multiSendData, err := PackMultiSend(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	execData := func(to common.Address, operation uint8) []byte {
+		tx := testSafeTx(0)
+		tx.To, tx.Data, tx.Operation = to, multiSendData, operation
+		data, err := PackExecTransaction(tx, make([]byte, signatureLength))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	decoded, ok := DecodeSafeMultiSend(execData(multiSend, OperationDelegateCall), multiSend)
+	if !ok || len(decoded) != len(calls) {
+		t.Fatalf("DecodeSafeMultiSend = %v, %v; want %d calls", decoded, ok, len(calls))
+	}
+	for i, call := range decoded {
+		if call.To != calls[i].To || call.Value.Cmp(calls[i].Value) != 0 || !bytes.Equal(call.Data, calls[i].Data) {
+			t.Fatalf("call %d = %+v, want %+v", i, call, calls[i])
+		}
+	}
+
+	// 其他合约的调用数据即使以 multiSend 选择器开头也不解码
+	other := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	if _, ok := DecodeSafeMultiSend(execData(other, OperationDelegateCall), multiSend); ok {
+		t.Fatal("decoded a delegatecall to a contract other than the configured multiSend")
+	}
+	if _, ok := DecodeSafeMultiSend(execData(multiSend, OperationCall), multiSend); ok {
+		t.Fatal("decoded a CALL to multiSend")
+	}
+	if _, ok := DecodeSafeMultiSend(common.FromHex("0xa9059cbb"), multiSend); ok {
+		t.Fatal("decoded data that is not an execTransaction call")
+	}
+}
@@ -0,0 +1,353 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// proxyFactoryABI ProxyWalletFactory.proxy 的 ABI（msg.sender 经 GSN 解析为 owner，owner 的 Proxy Wallet 不存在时先部署）
+const proxyFactoryABI = `[{
+	"name": "proxy",
+	"type": "function",
+	"stateMutability": "payable",
+	"inputs": [{
+		"name": "calls",
+		"type": "tuple[]",
+		"components": [
+			{"name": "typeCode", "type": "uint8"},
+			{"name": "to", "type": "address"},
+			{"name": "value", "type": "uint256"},
+			{"name": "data", "type": "bytes"}
+		]
+	}],
+	"outputs": [{"name": "returnValues", "type": "bytes[]"}]
+}]`
+
+// relayHubABI GSN（v1）RelayHub 中 relayer 使用的方法和事件
+// relayCall 在 canRelay 不通过或目标调用失败时不回滚，只通过 CanRelayFailed / TransactionRelayed 事件报告结果
+const relayHubABI = `[
+	{"name": "getNonce", "type": "function", "stateMutability": "view", "inputs": [{"name": "from", "type": "address"}], "outputs": [{"name": "", "type": "uint256"}]},
+	{"name": "getRelay", "type": "function", "stateMutability": "view", "inputs": [{"name": "relay", "type": "address"}], "outputs": [
+		{"name": "totalStake", "type": "uint256"},
+		{"name": "unstakeDelay", "type": "uint256"},
+		{"name": "unstakeTime", "type": "uint256"},
+		{"name": "owner", "type": "address"},
+		{"name": "state", "type": "uint8"}
+	]},
+	{"name": "canRelay", "type": "function", "stateMutability": "view", "inputs": [
+		{"name": "relay", "type": "address"},
+		{"name": "from", "type": "address"},
+		{"name": "to", "type": "address"},
+		{"name": "encodedFunction", "type": "bytes"},
+		{"name": "transactionFee", "type": "uint256"},
+		{"name": "gasPrice", "type": "uint256"},
+		{"name": "gasLimit", "type": "uint256"},
+		{"name": "nonce", "type": "uint256"},
+		{"name": "signature", "type": "bytes"},
+		{"name": "approvalData", "type": "bytes"}
+	], "outputs": [{"name": "status", "type": "uint256"}, {"name": "recipientContext", "type": "bytes"}]},
+	{"name": "CanRelayFailed", "type": "event", "anonymous": false, "inputs": [
+		{"name": "relay", "type": "address", "indexed": true},
+		{"name": "from", "type": "address", "indexed": true},
+		{"name": "to", "type": "address", "indexed": true},
+		{"name": "selector", "type": "bytes4", "indexed": false},
+		{"name": "reason", "type": "uint256", "indexed": false}
+	]},
+	{"name": "TransactionRelayed", "type": "event", "anonymous": false, "inputs": [
+		{"name": "relay", "type": "address", "indexed": true},
+		{"name": "from", "type": "address", "indexed": true},
+		{"name": "to", "type": "address", "indexed": true},
+		{"name": "selector", "type": "bytes4", "indexed": false},
+		{"name": "status", "type": "uint8", "indexed": false},
+		{"name": "charge", "type": "uint256", "indexed": false}
+	]},
+	{"name": "relayCall", "type": "function", "stateMutability": "nonpayable", "inputs": [
+		{"name": "from", "type": "address"},
+		{"name": "recipient", "type": "address"},
+		{"name": "encodedFunction", "type": "bytes"},
+		{"name": "transactionFee", "type": "uint256"},
+		{"name": "gasPrice", "type": "uint256"},
+		{"name": "gasLimit", "type": "uint256"},
+		{"name": "nonce", "type": "uint256"},
+		{"name": "signature", "type": "bytes"},
+		{"name": "approvalData", "type": "bytes"}
+	], "outputs": []}
+]`
+
+// 解析后的 ProxyWalletFactory 和 RelayHub ABI
+var (
+	parsedProxyFactoryABI = mustParseABI(proxyFactoryABI)
+	parsedRelayHubABI     = mustParseABI(relayHubABI)
+)
+
+// RelayStateRegistered RelayHub 中 relay 的已注册状态（RelayState: Unknown, Staked, Registered, Removed）
+const RelayStateRegistered uint8 = 2
+
+// canRelayStatuses RelayHub.canRelay 的状态码（大于 10 的状态码由 recipient 的 acceptRelayedCall 定义）
+var canRelayStatuses = []string{"OK", "WrongSignature", "WrongNonce", "AcceptRelayedCallReverted", "InvalidRecipientStatusCode"}
+
+// relayCallStatuses TransactionRelayed 事件的状态码
+var relayCallStatuses = []string{"OK", "RelayedCallFailed", "PreRelayedFailed", "PostRelayedFailed", "RecipientBalanceChanged"}
+
+// ProxyCallTypeCall Proxy Wallet 调用类型 CALL（0 为无效类型，2 为 DELEGATECALL，relayer 只中继 CALL）
+const ProxyCallTypeCall uint8 = 1
+
+// ProxyCall Proxy Wallet 调用
+type ProxyCall struct {
+	TypeCode uint8          `abi:"typeCode"`
+	To       common.Address `abi:"to"`
+	Value    *big.Int       `abi:"value"`
+	Data     []byte         `abi:"data"`
+}
+
+// RelayRequest GSN 中继请求（owner 签名的字段）
+type RelayRequest struct {
+	From     common.Address // Proxy Wallet owner
+	To       common.Address // ProxyWalletFactory
+	Data     []byte         // proxy(calls) 调用数据
+	TxFee    *big.Int       // transactionFee（百分比）
+	GasPrice *big.Int
+	GasLimit *big.Int
+	Nonce    *big.Int
+	RelayHub common.Address
+	Relay    common.Address // 发送 relayCall 的 Operator
+}
+
+// PackProxy 编码 ProxyWalletFactory.proxy(calls) 调用数据
+func PackProxy(calls []ProxyCall) ([]byte, error) {
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("no calls to proxy")
+	}
+	packed := make([]ProxyCall, len(calls))
+	for i, call := range calls {
+		packed[i] = call
+		if packed[i].Value == nil {
+			packed[i].Value = new(big.Int)
+		}
+	}
+	data, err := parsedProxyFactoryABI.Pack("proxy", packed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack proxy: %w", err)
+	}
+	return data, nil
+}
+
+// RelayRequestHash 计算中继请求哈希（与 RelayHub.canRelay 一致，owner 对该哈希做 eth_sign 签名）
+// keccak256("rlx:" ++ from ++ to ++ data ++ txFee ++ gasPrice ++ gasLimit ++ nonce ++ relayHub ++ relay)
+func RelayRequestHash(req *RelayRequest) common.Hash {
+	return crypto.Keccak256Hash(
+		[]byte("rlx:"),
+		req.From.Bytes(),
+		req.To.Bytes(),
+		req.Data,
+		common.LeftPadBytes(req.TxFee.Bytes(), 32),
+		common.LeftPadBytes(req.GasPrice.Bytes(), 32),
+		common.LeftPadBytes(req.GasLimit.Bytes(), 32),
+		common.LeftPadBytes(req.Nonce.Bytes(), 32),
+		req.RelayHub.Bytes(),
+		req.Relay.Bytes(),
+	)
+}
+
+// PackRelayCall 编码 RelayHub.relayCall 调用数据（approvalData 为空）
+func PackRelayCall(req *RelayRequest, signature []byte) ([]byte, error) {
+	data, err := parsedRelayHubABI.Pack("relayCall",
+		req.From, req.To, req.Data, req.TxFee, req.GasPrice, req.GasLimit, req.Nonce, signature, []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack relayCall: %w", err)
+	}
+	return data, nil
+}
+
+// RelayRejectedError RelayHub 拒绝或未能执行中继请求（relayCall 不回滚，交易成功上链但目标调用没有执行）
+type RelayRejectedError struct {
+	Source string   // canRelay、CanRelayFailed 或 TransactionRelayed
+	Status *big.Int // 状态码
+}
+
+// Error 实现 error 接口
+func (e *RelayRejectedError) Error() string {
+	names := canRelayStatuses
+	if e.Source == "TransactionRelayed" {
+		names = relayCallStatuses
+	}
+	name := "recipient rejected"
+	if e.Status.IsUint64() && e.Status.Uint64() < uint64(len(names)) {
+		name = names[e.Status.Uint64()]
+	}
+	return fmt.Sprintf("relay hub %s: %s (status %s)", e.Source, name, e.Status)
+}
+
+// CheckRelayCall 以 relayCall 调用数据调用 RelayHub.canRelay（relay 为发送交易的 Operator）
+// 状态码非 0 时返回 *RelayRejectedError：relayCall 上链后只会记录 CanRelayFailed 事件，Operator 白付 Gas
+func CheckRelayCall(ctx context.Context, client ContractCaller, relayHub, relay common.Address, relayCallData []byte) error {
+	args, err := unpackRelayCall(relayCallData)
+	if err != nil {
+		return err
+	}
+	input, err := parsedRelayHubABI.Pack("canRelay", append([]interface{}{relay}, args...)...)
+	if err != nil {
+		return fmt.Errorf("failed to pack canRelay: %w", err)
+	}
+	output, err := client.CallContract(ctx, ethereum.CallMsg{From: relay, To: &relayHub, Data: input}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to call relay hub canRelay: %w", err)
+	}
+	values, err := parsedRelayHubABI.Unpack("canRelay", output)
+	if err != nil {
+		return fmt.Errorf("failed to unpack canRelay: %w", err)
+	}
+	if status := values[0].(*big.Int); status.Sign() != 0 {
+		return &RelayRejectedError{Source: "canRelay", Status: status}
+	}
+	return nil
+}
+
+// RelayCallGasPrice 解析 relayCall 调用数据中签名的 gasPrice
+// RelayHub.relayCall 要求 tx.gasprice 不低于该值，否则回滚
+func RelayCallGasPrice(relayCallData []byte) (*big.Int, error) {
+	args, err := unpackRelayCall(relayCallData)
+	if err != nil {
+		return nil, err
+	}
+	return args[4].(*big.Int), nil
+}
+
+// unpackRelayCall 解码 relayCall 调用数据的参数
+func unpackRelayCall(relayCallData []byte) ([]interface{}, error) {
+	method, err := parsedRelayHubABI.MethodById(relayCallData)
+	if err != nil || method.Name != "relayCall" {
+		return nil, fmt.Errorf("data is not a relayCall")
+	}
+	args, err := method.Inputs.Unpack(relayCallData[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack relayCall: %w", err)
+	}
+	return args, nil
+}
+
+// CheckRelayLogs 根据回执中 RelayHub 的事件判断中继调用是否执行成功
+// CanRelayFailed 或状态非 OK 的 TransactionRelayed 返回 *RelayRejectedError，没有中继事件时返回错误
+func CheckRelayLogs(logs []*types.Log, relayHub common.Address) error {
+	canRelayFailed := parsedRelayHubABI.Events["CanRelayFailed"]
+	relayed := parsedRelayHubABI.Events["TransactionRelayed"]
+	for _, l := range logs {
+		if l.Address != relayHub || len(l.Topics) == 0 {
+			continue
+		}
+		switch l.Topics[0] {
+		case canRelayFailed.ID:
+			values, err := canRelayFailed.Inputs.NonIndexed().Unpack(l.Data)
+			if err != nil {
+				return fmt.Errorf("failed to unpack CanRelayFailed: %w", err)
+			}
+			return &RelayRejectedError{Source: "CanRelayFailed", Status: values[1].(*big.Int)}
+		case relayed.ID:
+			values, err := relayed.Inputs.NonIndexed().Unpack(l.Data)
+			if err != nil {
+				return fmt.Errorf("failed to unpack TransactionRelayed: %w", err)
+			}
+			if status := values[1].(uint8); status != 0 {
+				return &RelayRejectedError{Source: "TransactionRelayed", Status: new(big.Int).SetUint64(uint64(status))}
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no relay event emitted by relay hub %s", relayHub.Hex())
+}
+
+// ProxyVerifier Proxy Wallet 中继请求签名校验接口
+type ProxyVerifier interface {
+	// Verify 校验签名者为 owner（req.From）、Nonce 等于 RelayHub 中 owner 的当前 Nonce，且 RelayHub.canRelay 接受该请求
+	// 校验不通过时返回 400 INVALID_PROXY_TX
+	Verify(ctx context.Context, req *RelayRequest, signature []byte) error
+
+	// IsRegisteredRelay 查询 relay 是否已在 RelayHub 注册（未注册的 relay 发送的 relayCall 会回滚）
+	IsRegisteredRelay(ctx context.Context, relayHub, relay common.Address) (bool, error)
+}
+
+// proxyVerifier ProxyVerifier 实现
+type proxyVerifier struct {
+	client ContractCaller
+}
+
+// NewProxyVerifier 创建 Proxy Wallet 中继请求签名校验器
+func NewProxyVerifier(client ContractCaller) ProxyVerifier {
+	return &proxyVerifier{client: client}
+}
+
+// Verify 校验中继请求签名
+func (v *proxyVerifier) Verify(ctx context.Context, req *RelayRequest, signature []byte) error {
+	// 1. 签名须为 owner 对请求哈希的 eth_sign 签名（v 兼容 0/1 和 27/28）
+	if len(signature) != signatureLength {
+		return errors.BadRequest("INVALID_PROXY_TX", fmt.Sprintf("signature must be %d bytes", signatureLength))
+	}
+	sigV := signature[64]
+	if sigV < 27 {
+		sigV += 27
+	}
+	hash := RelayRequestHash(req)
+	signer, err := ecrecover(accounts.TextHash(hash.Bytes()), signature[:32], signature[32:64], sigV)
+	if err != nil {
+		return errors.BadRequest("INVALID_PROXY_TX", err.Error())
+	}
+	if signer != req.From {
+		return errors.BadRequest("INVALID_PROXY_TX", fmt.Sprintf("signer %s is not the proxy owner %s", signer.Hex(), req.From.Hex()))
+	}
+
+	// 2. Nonce 须等于链上 Nonce：RelayHub 只接受当前 Nonce，其他 Nonce 的 relayCall 只记录 CanRelayFailed
+	input, err := parsedRelayHubABI.Pack("getNonce", req.From)
+	if err != nil {
+		return fmt.Errorf("failed to pack getNonce: %w", err)
+	}
+	output, err := v.client.CallContract(ctx, ethereum.CallMsg{To: &req.RelayHub, Data: input}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to call relay hub getNonce: %w", err)
+	}
+	var nonce *big.Int
+	if err := parsedRelayHubABI.UnpackIntoInterface(&nonce, "getNonce", output); err != nil {
+		return fmt.Errorf("failed to unpack getNonce: %w", err)
+	}
+	if req.Nonce.Cmp(nonce) != 0 {
+		return errors.BadRequest("INVALID_PROXY_TX", fmt.Sprintf("relay nonce %s does not match current nonce %s", req.Nonce, nonce))
+	}
+
+	// 3. RelayHub.canRelay 须接受该请求（同时校验 recipient 的 acceptRelayedCall）
+	callData, err := PackRelayCall(req, signature)
+	if err != nil {
+		return err
+	}
+	if err := CheckRelayCall(ctx, v.client, req.RelayHub, req.Relay, callData); err != nil {
+		var rejected *RelayRejectedError
+		if errors.As(err, &rejected) {
+			return errors.BadRequest("INVALID_PROXY_TX", rejected.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+// IsRegisteredRelay 查询 relay 是否已在 RelayHub 注册
+func (v *proxyVerifier) IsRegisteredRelay(ctx context.Context, relayHub, relay common.Address) (bool, error) {
+	input, err := parsedRelayHubABI.Pack("getRelay", relay)
+	if err != nil {
+		return false, fmt.Errorf("failed to pack getRelay: %w", err)
+	}
+	output, err := v.client.CallContract(ctx, ethereum.CallMsg{To: &relayHub, Data: input}, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to call relay hub getRelay: %w", err)
+	}
+	values, err := parsedRelayHubABI.Unpack("getRelay", output)
+	if err != nil {
+		return false, fmt.Errorf("failed to unpack getRelay: %w", err)
+	}
+	return values[4].(uint8) == RelayStateRegistered, nil
+}
@@ -0,0 +1,180 @@
+package wallet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fakeRelayHub RelayHub 只读方法 stand-in（getNonce / canRelay / getRelay）
+type fakeRelayHub struct {
+	address        common.Address
+	nonce          int64
+	canRelayStatus int64                   // canRelay 返回的状态码
+	registered     map[common.Address]bool // 已注册的 relay
+	canRelayArgs   []interface{}           // 最近一次 canRelay 调用的参数
+}
+
+func (f *fakeRelayHub) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if msg.To == nil || *msg.To != f.address {
+		return nil, nil
+	}
+	method, err := parsedRelayHubABI.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Unpack(msg.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "getNonce":
+		return method.Outputs.Pack(big.NewInt(f.nonce))
+	case "canRelay":
+		f.canRelayArgs = args
+		return method.Outputs.Pack(big.NewInt(f.canRelayStatus), []byte{})
+	case "getRelay":
+		state := uint8(1) // Staked
+		if f.registered[args[0].(common.Address)] {
+			state = RelayStateRegistered
+		}
+		return method.Outputs.Pack(big.NewInt(1e18), big.NewInt(604800), big.NewInt(0), common.Address{}, state)
+	}
+	return nil, fmt.Errorf("unexpected method %s", method.Name)
+}
+
+// testRelayRequest 中继请求测试数据
+func testRelayRequest(t *testing.T, owner *ecdsa.PrivateKey, nonce int64) *RelayRequest {
+	t.Helper()
+	data, err := PackProxy([]ProxyCall{{TypeCode: ProxyCallTypeCall, To: common.HexToAddress("0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174"), Data: common.FromHex("0x095ea7b3")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &RelayRequest{
+		From:     crypto.PubkeyToAddress(owner.PublicKey),
+		To:       common.HexToAddress("0xaB45c5A4B0c941a2F231C04C3f49182e1A254052"),
+		Data:     data,
+		TxFee:    big.NewInt(0),
+		GasPrice: big.NewInt(30_000_000_000),
+		GasLimit: big.NewInt(500_000),
+		Nonce:    big.NewInt(nonce),
+		RelayHub: common.HexToAddress("0xD216153c06E857cD7f72665E0aF1d7D82172F494"),
+		Relay:    common.HexToAddress("0x00000000000000000000000000000000000000aa"),
+	}
+}
+
+// signRelayRequest owner 对中继请求哈希做 eth_sign 签名
+func signRelayRequest(t *testing.T, key *ecdsa.PrivateKey, req *RelayRequest) []byte {
+	t.Helper()
+	sig, err := crypto.Sign(accounts.TextHash(RelayRequestHash(req).Bytes()), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[64] += 27
+	return sig
+}
+
+func TestProxyVerify(t *testing.T) {
+	owner, _ := crypto.GenerateKey()
+	outsider, _ := crypto.GenerateKey()
+
+	tests := []struct {
+		name           string
+		nonce          int64
+		signer         *ecdsa.PrivateKey
+		canRelayStatus int64
+		wantErr        string
+	}{
+		{name: "valid", nonce: 3, signer: owner},
+		{name: "not the owner", nonce: 3, signer: outsider, wantErr: "is not the proxy owner"},
+		{name: "used nonce", nonce: 2, signer: owner, wantErr: "does not match current nonce"},
+		{name: "future nonce", nonce: 4, signer: owner, wantErr: "does not match current nonce"},
+		{name: "recipient rejects", nonce: 3, signer: owner, canRelayStatus: 3, wantErr: "AcceptRelayedCallReverted"},
+		{name: "recipient status code", nonce: 3, signer: owner, canRelayStatus: 11, wantErr: "recipient rejected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testRelayRequest(t, owner, tt.nonce)
+			hub := &fakeRelayHub{address: req.RelayHub, nonce: 3, canRelayStatus: tt.canRelayStatus}
+			err := NewProxyVerifier(hub).Verify(context.Background(), req, signRelayRequest(t, tt.signer, req))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Verify: %v", err)
+				}
+				// canRelay 以签名绑定的 relay 调用
+				if hub.canRelayArgs == nil || hub.canRelayArgs[0].(common.Address) != req.Relay || hub.canRelayArgs[7].(*big.Int).Cmp(req.Nonce) != 0 {
+					t.Fatalf("canRelay args = %v", hub.canRelayArgs)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Verify err = %v; want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProxyIsRegisteredRelay(t *testing.T) {
+	hub := common.HexToAddress("0xD216153c06E857cD7f72665E0aF1d7D82172F494")
+	registered, unregistered := common.HexToAddress("0xaa"), common.HexToAddress("0xbb")
+	verifier := NewProxyVerifier(&fakeRelayHub{address: hub, registered: map[common.Address]bool{registered: true}})
+
+	for relay, want := range map[common.Address]bool{registered: true, unregistered: false} {
+		got, err := verifier.IsRegisteredRelay(context.Background(), hub, relay)
+		if err != nil || got != want {
+			t.Fatalf("IsRegisteredRelay(%s) = %v, %v; want %v", relay.Hex(), got, err, want)
+		}
+	}
+}
+
+func TestCheckRelayLogs(t *testing.T) {
+	hub := common.HexToAddress("0xD216153c06E857cD7f72665E0aF1d7D82172F494")
+	selector := [4]byte{0x6b, 0x1a, 0x0b, 0x77}
+	relayLog := func(address common.Address, event string, values ...interface{}) *types.Log {
+		e := parsedRelayHubABI.Events[event]
+		data, err := e.Inputs.NonIndexed().Pack(values...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &types.Log{Address: address, Topics: []common.Hash{e.ID, {}, {}, {}}, Data: data}
+	}
+	// 目标合约的日志在 RelayHub 事件之前
+	other := &types.Log{Address: common.HexToAddress("0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174"), Topics: []common.Hash{{0x01}}}
+
+	tests := []struct {
+		name    string
+		logs    []*types.Log
+		wantErr string
+	}{
+		{name: "relayed", logs: []*types.Log{other, relayLog(hub, "TransactionRelayed", selector, uint8(0), big.NewInt(1e15))}},
+		{name: "relayed call failed", logs: []*types.Log{relayLog(hub, "TransactionRelayed", selector, uint8(1), big.NewInt(1e15))},
+			wantErr: "RelayedCallFailed"},
+		{name: "can relay failed", logs: []*types.Log{relayLog(hub, "CanRelayFailed", selector, big.NewInt(2))}, wantErr: "WrongNonce"},
+		{name: "event from another contract", logs: []*types.Log{relayLog(other.Address, "TransactionRelayed", selector, uint8(0), big.NewInt(0))},
+			wantErr: "no relay event"},
+		{name: "no logs", wantErr: "no relay event"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRelayLogs(tt.logs, hub)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckRelayLogs: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("CheckRelayLogs err = %v; want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// safeABI Safe（v1.3.0+）合约中 relayer 使用的方法
+const safeABI = `[
+	{"name": "getOwners", "type": "function", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "address[]"}]},
+	{"name": "getThreshold", "type": "function", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "uint256"}]},
+	{"name": "nonce", "type": "function", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "uint256"}]},
+	{"name": "approvedHashes", "type": "function", "stateMutability": "view", "inputs": [{"name": "owner", "type": "address"}, {"name": "hash", "type": "bytes32"}], "outputs": [{"name": "", "type": "uint256"}]},
+	{"name": "execTransaction", "type": "function", "stateMutability": "payable", "inputs": [
+		{"name": "to", "type": "address"},
+		{"name": "value", "type": "uint256"},
+		{"name": "data", "type": "bytes"},
+		{"name": "operation", "type": "uint8"},
+		{"name": "safeTxGas", "type": "uint256"},
+		{"name": "baseGas", "type": "uint256"},
+		{"name": "gasPrice", "type": "uint256"},
+		{"name": "gasToken", "type": "address"},
+		{"name": "refundReceiver", "type": "address"},
+		{"name": "signatures", "type": "bytes"}
+	], "outputs": [{"name": "success", "type": "bool"}]}
+]`
+
+// parsedSafeABI 解析后的 Safe ABI
+var parsedSafeABI = mustParseABI(safeABI)
+
+// EIP-712 类型哈希（Safe v1.3.0+，domain 包含 chainId）
+var (
+	domainSeparatorTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(uint256 chainId,address verifyingContract)"))
+	safeTxTypeHash          = crypto.Keccak256Hash([]byte("SafeTx(address to,uint256 value,bytes data,uint8 operation,uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)"))
+)
+
+// signatureLength 单个 owner 签名长度（r 32 字节 + s 32 字节 + v 1 字节）
+const signatureLength = 65
+
+// Safe 操作类型
+const (
+	OperationCall         uint8 = 0 // CALL
+	OperationDelegateCall uint8 = 1 // DELEGATECALL
+)
+
+// SafeTx Safe 交易（EIP-712 SafeTx 结构）
+type SafeTx struct {
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      uint8
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	Nonce          *big.Int
+}
+
+// SafeTxHash 计算 SafeTx 的 EIP-712 哈希（owner 签名的消息）
+func SafeTxHash(chainID *big.Int, safe common.Address, tx *SafeTx) common.Hash {
+	domainSeparator := crypto.Keccak256Hash(
+		domainSeparatorTypeHash.Bytes(),
+		common.LeftPadBytes(chainID.Bytes(), 32),
+		common.LeftPadBytes(safe.Bytes(), 32),
+	)
+	structHash := crypto.Keccak256Hash(
+		safeTxTypeHash.Bytes(),
+		common.LeftPadBytes(tx.To.Bytes(), 32),
+		common.LeftPadBytes(tx.Value.Bytes(), 32),
+		crypto.Keccak256(tx.Data),
+		common.LeftPadBytes([]byte{tx.Operation}, 32),
+		common.LeftPadBytes(tx.SafeTxGas.Bytes(), 32),
+		common.LeftPadBytes(tx.BaseGas.Bytes(), 32),
+		common.LeftPadBytes(tx.GasPrice.Bytes(), 32),
+		common.LeftPadBytes(tx.GasToken.Bytes(), 32),
+		common.LeftPadBytes(tx.RefundReceiver.Bytes(), 32),
+		common.LeftPadBytes(tx.Nonce.Bytes(), 32),
+	)
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator.Bytes(), structHash.Bytes())
+}
+
+// PackExecTransaction 编码 execTransaction 调用数据
+func PackExecTransaction(tx *SafeTx, signatures []byte) ([]byte, error) {
+	data, err := parsedSafeABI.Pack("execTransaction",
+		tx.To, tx.Value, tx.Data, tx.Operation, tx.SafeTxGas, tx.BaseGas, tx.GasPrice, tx.GasToken, tx.RefundReceiver, signatures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack execTransaction: %w", err)
+	}
+	return data, nil
+}
+
+// ContractCaller eth_call 接口（*ethclient.Client 和 *rpcpool.Pool 已实现）
+type ContractCaller interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// SafeVerifier Safe 交易签名校验接口
+type SafeVerifier interface {
+	// Verify 计算 SafeTx 的 EIP-712 哈希，按链上 owners 和 threshold 校验签名（与 Safe.checkSignatures 规则一致）
+	// 校验不通过时返回 400 INVALID_SAFE_TX；Nonce 不等于链上 Nonce 时同样拒绝
+	Verify(ctx context.Context, safe common.Address, tx *SafeTx, signatures []byte) error
+}
+
+// safeVerifier SafeVerifier 实现
+type safeVerifier struct {
+	client  ContractCaller
+	chainID *big.Int
+}
+
+// NewSafeVerifier 创建 Safe 交易签名校验器
+func NewSafeVerifier(client ContractCaller, chainID *big.Int) SafeVerifier {
+	return &safeVerifier{client: client, chainID: chainID}
+}
+
+// Verify 校验 SafeTx 签名
+func (v *safeVerifier) Verify(ctx context.Context, safe common.Address, tx *SafeTx, signatures []byte) error {
+	// 1. 读取链上 owners、threshold 和 Nonce
+	var owners []common.Address
+	if err := v.call(ctx, safe, &owners, "getOwners"); err != nil {
+		return err
+	}
+	var threshold, nonce *big.Int
+	if err := v.call(ctx, safe, &threshold, "getThreshold"); err != nil {
+		return err
+	}
+	if err := v.call(ctx, safe, &nonce, "nonce"); err != nil {
+		return err
+	}
+	if threshold.Sign() == 0 {
+		return errors.BadRequest("INVALID_SAFE_TX", fmt.Sprintf("safe %s is not set up", safe.Hex()))
+	}
+	// execTransaction 按 Safe 当前 Nonce 计算哈希：低于链上 Nonce 说明已执行，高于链上 Nonce 时签名校验失败（GS026），都无法执行
+	if tx.Nonce.Cmp(nonce) != 0 {
+		return errors.BadRequest("INVALID_SAFE_TX", fmt.Sprintf("safe nonce %s does not match current nonce %s", tx.Nonce, nonce))
+	}
+
+	// 2. 校验前 threshold 个签名：签名者须为 owner 且按地址严格递增（防止重复计数）
+	if !threshold.IsInt64() || int64(len(signatures)) < threshold.Int64()*signatureLength {
+		return errors.BadRequest("INVALID_SAFE_TX", fmt.Sprintf("%d signatures required", threshold))
+	}
+	isOwner := make(map[common.Address]bool, len(owners))
+	for _, owner := range owners {
+		isOwner[owner] = true
+	}
+
+	hash := SafeTxHash(v.chainID, safe, tx)
+	var lastOwner common.Address
+	for i := 0; i < int(threshold.Int64()); i++ {
+		sig := signatures[i*signatureLength : (i+1)*signatureLength]
+		owner, err := v.recoverSigner(ctx, safe, hash, sig)
+		if err != nil {
+			return errors.BadRequest("INVALID_SAFE_TX", fmt.Sprintf("signature %d: %v", i, err))
+		}
+		if !isOwner[owner] {
+			return errors.BadRequest("INVALID_SAFE_TX", fmt.Sprintf("signature %d: signer %s is not an owner", i, owner.Hex()))
+		}
+		if bytes.Compare(owner.Bytes(), lastOwner.Bytes()) <= 0 {
+			return errors.BadRequest("INVALID_SAFE_TX", fmt.Sprintf("signature %d: signatures must be sorted by owner address without duplicates", i))
+		}
+		lastOwner = owner
+	}
+	return nil
+}
+
+// recoverSigner 按签名类型（v）恢复签名者
+// v = 27/28：EIP-712 签名；v > 30：eth_sign 签名（v - 4）；v = 1：链上预先批准的哈希（approveHash）；v = 0：合约签名（EIP-1271，暂不支持）
+func (v *safeVerifier) recoverSigner(ctx context.Context, safe common.Address, hash common.Hash, sig []byte) (common.Address, error) {
+	r, s, sigV := sig[:32], sig[32:64], sig[64]
+	switch {
+	case sigV == 0:
+		return common.Address{}, fmt.Errorf("contract signatures are not supported")
+	case sigV == 1:
+		owner := common.BytesToAddress(r)
+		var approved *big.Int
+		if err := v.call(ctx, safe, &approved, "approvedHashes", owner, hash); err != nil {
+			return common.Address{}, err
+		}
+		if approved.Sign() == 0 {
+			return common.Address{}, fmt.Errorf("hash not approved by %s", owner.Hex())
+		}
+		return owner, nil
+	case sigV > 30:
+		return ecrecover(accounts.TextHash(hash.Bytes()), r, s, sigV-4)
+	default:
+		return ecrecover(hash.Bytes(), r, s, sigV)
+	}
+}
+
+// ecrecover 从 (r, s, v) 签名恢复签名者地址（v 为 27/28）
+func ecrecover(hash, r, s []byte, v byte) (common.Address, error) {
+	if v != 27 && v != 28 {
+		return common.Address{}, fmt.Errorf("invalid signature v: %d", v)
+	}
+	sig := make([]byte, signatureLength)
+	copy(sig, r)
+	copy(sig[32:], s)
+	sig[64] = v - 27
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// call 调用 Safe 的只读方法（最新区块状态）
+func (v *safeVerifier) call(ctx context.Context, safe common.Address, result interface{}, method string, args ...interface{}) error {
+	input, err := parsedSafeABI.Pack(method, args...)
+	if err != nil {
+		return fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+	output, err := v.client.CallContract(ctx, ethereum.CallMsg{To: &safe, Data: input}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to call safe %s: %w", method, err)
+	}
+	if len(output) == 0 {
+		return errors.BadRequest("INVALID_SAFE_TX", fmt.Sprintf("%s is not a safe contract", safe.Hex()))
+	}
+	if err := parsedSafeABI.UnpackIntoInterface(result, method, output); err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", method, err)
+	}
+	return nil
+}
+
+// mustParseABI 解析内置 ABI（解析失败说明 ABI 常量有误）
+func mustParseABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(fmt.Sprintf("invalid abi: %v", err))
+	}
+	return parsed
+}
@@ -0,0 +1,258 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// fakeSafe Safe 合约只读方法 stand-in（getOwners / getThreshold / nonce / approvedHashes）
+type fakeSafe struct {
+	address   common.Address
+	owners    []common.Address
+	threshold int64
+	nonce     int64
+	approved  map[common.Address]common.Hash // owner -> approveHash 批准的哈希
+}
+
+func (f *fakeSafe) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if msg.To == nil || *msg.To != f.address {
+		return nil, nil // 非合约地址：返回空数据
+	}
+	method, err := parsedSafeABI.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "getOwners":
+		return method.Outputs.Pack(f.owners)
+	case "getThreshold":
+		return method.Outputs.Pack(big.NewInt(f.threshold))
+	case "nonce":
+		return method.Outputs.Pack(big.NewInt(f.nonce))
+	case "approvedHashes":
+		args, err := method.Inputs.Unpack(msg.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		owner, hash := args[0].(common.Address), common.Hash(args[1].([32]byte))
+		approved := big.NewInt(0)
+		if f.approved[owner] == hash {
+			approved.SetInt64(1)
+		}
+		return method.Outputs.Pack(approved)
+	}
+	return nil, fmt.Errorf("unexpected method %s", method.Name)
+}
+
+// testSafeTx Safe 交易测试数据（USDC transfer）
+func testSafeTx(nonce int64) *SafeTx {
+	return &SafeTx{
+		To:             common.HexToAddress("0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174"),
+		Value:          big.NewInt(0),
+		Data:           common.FromHex("0xa9059cbb000000000000000000000000000000000000000000000000000000000000dead00000000000000000000000000000000000000000000000000000000000f4240"),
+		Operation:      OperationCall,
+		SafeTxGas:      big.NewInt(0),
+		BaseGas:        big.NewInt(0),
+		GasPrice:       big.NewInt(0),
+		GasToken:       common.Address{},
+		RefundReceiver: common.Address{},
+		Nonce:          big.NewInt(nonce),
+	}
+}
+
+// testOwners 生成按地址升序排列的 owner 私钥
+func testOwners(t *testing.T, n int) []*ecdsa.PrivateKey {
+	t.Helper()
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(crypto.PubkeyToAddress(keys[i].PublicKey).Bytes(), crypto.PubkeyToAddress(keys[j].PublicKey).Bytes()) < 0
+	})
+	return keys
+}
+
+// signEIP712 owner 直接签名 SafeTx 哈希（v = 27/28）
+func signEIP712(t *testing.T, key *ecdsa.PrivateKey, hash common.Hash) []byte {
+	t.Helper()
+	sig, err := crypto.Sign(hash.Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[64] += 27
+	return sig
+}
+
+// signEthSign owner 通过 eth_sign 签名 SafeTx 哈希（v = 31/32）
+func signEthSign(t *testing.T, key *ecdsa.PrivateKey, hash common.Hash) []byte {
+	t.Helper()
+	sig, err := crypto.Sign(accounts.TextHash(hash.Bytes()), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[64] += 31
+	return sig
+}
+
+// approvedHashSig 链上预先批准的哈希（r = owner，v = 1）
+func approvedHashSig(owner common.Address) []byte {
+	sig := make([]byte, signatureLength)
+	copy(sig, common.LeftPadBytes(owner.Bytes(), 32))
+	sig[64] = 1
+	return sig
+}
+
+func TestSafeTxHashMatchesEIP712(t *testing.T) {
+	chainID := big.NewInt(137)
+	safe := common.HexToAddress("0x1C2D3E4F5061728394a5B6c7D8e9f0A1b2C3d4E5")
+	tx := testSafeTx(3)
+	tx.Value = big.NewInt(12345)
+	tx.SafeTxGas, tx.BaseGas, tx.GasPrice = big.NewInt(50000), big.NewInt(21000), big.NewInt(1)
+	tx.RefundReceiver = common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+	// 以 go-ethereum 的 EIP-712 实现独立计算 Safe v1.3.0+ 的 SafeTx 哈希
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {{Name: "chainId", Type: "uint256"}, {Name: "verifyingContract", Type: "address"}},
+			"SafeTx": {
+				{Name: "to", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "data", Type: "bytes"},
+				{Name: "operation", Type: "uint8"},
+				{Name: "safeTxGas", Type: "uint256"},
+				{Name: "baseGas", Type: "uint256"},
+				{Name: "gasPrice", Type: "uint256"},
+				{Name: "gasToken", Type: "address"},
+				{Name: "refundReceiver", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+			},
+		},
+		PrimaryType: "SafeTx",
+		Domain:      apitypes.TypedDataDomain{ChainId: (*math.HexOrDecimal256)(chainID), VerifyingContract: safe.Hex()},
+		Message: apitypes.TypedDataMessage{
+			"to":             tx.To.Hex(),
+			"value":          tx.Value.String(),
+			"data":           hexutil.Encode(tx.Data),
+			"operation":      fmt.Sprint(tx.Operation),
+			"safeTxGas":      tx.SafeTxGas.String(),
+			"baseGas":        tx.BaseGas.String(),
+			"gasPrice":       tx.GasPrice.String(),
+			"gasToken":       tx.GasToken.Hex(),
+			"refundReceiver": tx.RefundReceiver.Hex(),
+			"nonce":          tx.Nonce.String(),
+		},
+	}
+	want, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := SafeTxHash(chainID, safe, tx); !bytes.Equal(got.Bytes(), want) {
+		t.Fatalf("SafeTxHash = %s, want %s", got.Hex(), hexutil.Encode(want))
+	}
+
+	// 链 ID、Safe 地址和 Nonce 都参与哈希
+	if SafeTxHash(big.NewInt(1), safe, tx) == SafeTxHash(chainID, safe, tx) {
+		t.Fatal("hash does not depend on chain id")
+	}
+	if SafeTxHash(chainID, common.Address{}, tx) == SafeTxHash(chainID, safe, tx) {
+		t.Fatal("hash does not depend on safe address")
+	}
+	if SafeTxHash(chainID, safe, testSafeTx(4)) == SafeTxHash(chainID, safe, testSafeTx(3)) {
+		t.Fatal("hash does not depend on nonce")
+	}
+}
+
+func TestSafeVerify(t *testing.T) {
+	chainID := big.NewInt(137)
+	safe := common.HexToAddress("0x1C2D3E4F5061728394a5B6c7D8e9f0A1b2C3d4E5")
+	keys := testOwners(t, 3)
+	owners := make([]common.Address, len(keys))
+	for i, key := range keys {
+		owners[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	outsider, _ := crypto.GenerateKey()
+
+	tx := testSafeTx(7)
+	hash := SafeTxHash(chainID, safe, tx)
+	concat := func(sigs ...[]byte) []byte { return bytes.Join(sigs, nil) }
+
+	tests := []struct {
+		name     string
+		tx       *SafeTx
+		sigs     []byte
+		approved map[common.Address]common.Hash
+		wantErr  string
+	}{
+		{name: "eip712", tx: tx, sigs: concat(signEIP712(t, keys[0], hash), signEIP712(t, keys[1], hash))},
+		{name: "eth_sign", tx: tx, sigs: concat(signEthSign(t, keys[0], hash), signEthSign(t, keys[2], hash))},
+		{name: "approved hash", tx: tx, sigs: concat(approvedHashSig(owners[0]), signEIP712(t, keys[1], hash)),
+			approved: map[common.Address]common.Hash{owners[0]: hash}},
+		{name: "mixed types with extra signature", tx: tx,
+			sigs:     concat(approvedHashSig(owners[0]), signEthSign(t, keys[1], hash), signEIP712(t, keys[2], hash)),
+			approved: map[common.Address]common.Hash{owners[0]: hash}},
+		{name: "hash not approved", tx: tx, sigs: concat(approvedHashSig(owners[0]), signEIP712(t, keys[1], hash)),
+			wantErr: "not approved"},
+		{name: "approved other hash", tx: tx, sigs: concat(approvedHashSig(owners[0]), signEIP712(t, keys[1], hash)),
+			approved: map[common.Address]common.Hash{owners[0]: common.HexToHash("0x01")}, wantErr: "not approved"},
+		{name: "unsorted owners", tx: tx, sigs: concat(signEIP712(t, keys[1], hash), signEIP712(t, keys[0], hash)),
+			wantErr: "sorted"},
+		{name: "duplicate owner", tx: tx, sigs: concat(signEIP712(t, keys[0], hash), signEthSign(t, keys[0], hash)),
+			wantErr: "sorted"},
+		{name: "not an owner", tx: tx, sigs: concat(signEIP712(t, keys[0], hash), signEIP712(t, outsider, hash)),
+			wantErr: "not an owner"},
+		{name: "below threshold", tx: tx, sigs: signEIP712(t, keys[0], hash), wantErr: "2 signatures required"},
+		{name: "contract signature", tx: tx, sigs: concat(make([]byte, signatureLength), signEIP712(t, keys[1], hash)),
+			wantErr: "contract signatures"},
+		{name: "eth_sign over raw hash", tx: tx, sigs: concat(signEIP712(t, keys[0], hash), func() []byte {
+			sig := signEIP712(t, keys[1], hash)
+			sig[64] += 4 // v > 30 但签名的是原始哈希：恢复出其他地址
+			return sig
+		}()), wantErr: "signature 1"},
+		{name: "used nonce", tx: testSafeTx(6), sigs: concat(signEIP712(t, keys[0], SafeTxHash(chainID, safe, testSafeTx(6))), signEIP712(t, keys[1], SafeTxHash(chainID, safe, testSafeTx(6)))),
+			wantErr: "does not match current nonce"},
+		{name: "future nonce", tx: testSafeTx(8), sigs: concat(signEIP712(t, keys[0], SafeTxHash(chainID, safe, testSafeTx(8))), signEIP712(t, keys[1], SafeTxHash(chainID, safe, testSafeTx(8)))),
+			wantErr: "does not match current nonce"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := NewSafeVerifier(&fakeSafe{address: safe, owners: owners, threshold: 2, nonce: 7, approved: tt.approved}, chainID)
+			err := verifier.Verify(context.Background(), safe, tt.tx, tt.sigs)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Verify: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Verify err = %v; want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSafeVerifyNotASafe(t *testing.T) {
+	verifier := NewSafeVerifier(&fakeSafe{address: common.HexToAddress("0x01")}, big.NewInt(137))
+	err := verifier.Verify(context.Background(), common.HexToAddress("0x02"), testSafeTx(0), make([]byte, signatureLength))
+	if err == nil || !strings.Contains(err.Error(), "is not a safe contract") {
+		t.Fatalf("Verify err = %v; want not a safe contract", err)
+	}
+}